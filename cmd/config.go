@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/spf13/cobra"
+	"gopkg.in/AlecAivazis/survey.v1"
 	"gopkg.in/yaml.v2"
 
 	"encoding/json"
@@ -19,12 +20,25 @@ type configInputType struct {
 	accessKeyID     *string
 	accessKeySecret *string
 	securityToken   *string
+	roleARN         *string
+	roleSessionName *string
+	roleSessionDur  *int
+	stsEndpoint     *string
+	ecsRAMRole      *string
+	ecsMetadataURL  *string
 	apiVersion      *string
 	timeout         *uint
+	maxAttempts     *int
 	debug           *bool
 	display         *bool
+	encrypt         *bool
+	decrypt         *bool
 }
 
+// gConfigPassphrase is the passphrase of the secrets in the config file, the secrets are written
+// in plain text if it is empty.
+var gConfigPassphrase string
+
 var configInput configInputType
 
 func init() {
@@ -35,29 +49,50 @@ func init() {
 	configInput.accessKeyID = configCmd.Flags().String("access-key-id", "", "access key id")
 	configInput.accessKeySecret = configCmd.Flags().String("access-key-secret", "", "access key secret")
 	configInput.securityToken = configCmd.Flags().String("security-token", "", "ram security token")
+	configInput.roleARN = configCmd.Flags().String("role-arn", "", "arn of the role to assume by sts, set empty to disable")
+	configInput.roleSessionName = configCmd.Flags().String("role-session-name", "", "session name of the assumed role")
+	configInput.roleSessionDur = configCmd.Flags().Int(
+		"role-session-duration", util.DefaultRoleSessionDuration, "duration in seconds of the assumed role credentials")
+	configInput.stsEndpoint = configCmd.Flags().String("sts-endpoint", "", "sts endpoint to assume the role")
+	configInput.ecsRAMRole = configCmd.Flags().String("ecs-ram-role", "", "ram role attached to the ecs instance")
+	configInput.ecsMetadataURL = configCmd.Flags().String(
+		"ecs-metadata-url", "", "url of the ram role credentials in the ecs instance metadata")
 	configInput.timeout = configCmd.Flags().Uint("timeout", 60, "timeout in seconds")
+	configInput.maxAttempts = configCmd.Flags().Int("max-attempts", util.DefaultMaxAttempts,
+		"max attempts of a fc api call on throttling, 5xx and network errors, 1 to disable the retries")
 	configInput.apiVersion = configCmd.Flags().String("api-version", "2016-08-15", "fc api version")
 	configInput.debug = configCmd.Flags().Bool("debug", false, "enable debug or not")
 	configInput.display = configCmd.Flags().Bool("display", false, "display the configuration")
+	configInput.encrypt = configCmd.Flags().Bool("encrypt", false,
+		"encrypt the secrets in the config file with a passphrase, or "+util.ConfigPassphraseEnv)
+	configInput.decrypt = configCmd.Flags().Bool("decrypt", false, "store the secrets in the config file in plain text")
 }
 
 var configCmd = &cobra.Command{
 	Use:     "config",
 	Aliases: []string{"c"},
 	Short:   "Configure the fcli",
-	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
+	Long: `
+Configure the fcli, the configuration is stored in ~/.fcli/config.yaml.
+The access key secret and the security token can be encrypted with a passphrase by --encrypt,
+which is prompted when the config file is loaded, or read from the environment variable
+FCLI_CONFIG_PASSPHRASE in CI.
+EXAMPLE:
+fcli config --access-key-id id --access-key-secret secret
+fcli config --encrypt
+fcli config --display
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		if cmd.Flags().NFlag() == 0 {
-			readConfig()
-			return
+			return readConfig()
 		}
 		if *configInput.display {
-			fmt.Printf(displayConfig())
-			return
+			fmt.Print(displayConfig())
+			return nil
 		}
 		config, err := getConfigAways()
 		if err != nil {
-			return
+			return err
 		}
 		if cmd.Flags().Changed("debug") {
 			config.Debug = *configInput.debug
@@ -65,11 +100,13 @@ var configCmd = &cobra.Command{
 		if cmd.Flags().Changed("timeout") {
 			config.Timeout = *configInput.timeout
 		}
+		if cmd.Flags().Changed("max-attempts") {
+			config.MaxAttempts = *configInput.maxAttempts
+		}
 		if cmd.Flags().Changed("endpoint") {
 			config.Endpoint = *configInput.endpoint
 			config.Endpoint = strings.TrimSpace(config.Endpoint)
-			config.SLSEndpoint = fmt.Sprintf(
-				util.LogEndpointFmt, util.GetRegionNoForSLSEndpoint(config.Endpoint))
+			config.SLSEndpoint = util.SLSEndpointForEndpoint(config.Endpoint)
 		}
 
 		if cmd.Flags().Changed("access-key-id") {
@@ -81,41 +118,164 @@ var configCmd = &cobra.Command{
 		if cmd.Flags().Changed("security-token") {
 			config.SecurityToken = *configInput.securityToken
 		}
+		if cmd.Flags().Changed("role-arn") {
+			config.RoleARN = *configInput.roleARN
+		}
+		if cmd.Flags().Changed("role-session-name") {
+			config.RoleSessionName = *configInput.roleSessionName
+		}
+		if cmd.Flags().Changed("role-session-duration") {
+			config.RoleSessionDuration = *configInput.roleSessionDur
+		}
+		if cmd.Flags().Changed("sts-endpoint") {
+			config.STSEndpoint = *configInput.stsEndpoint
+		}
+		if cmd.Flags().Changed("ecs-ram-role") {
+			config.ECSRAMRole = *configInput.ecsRAMRole
+		}
+		if cmd.Flags().Changed("ecs-metadata-url") {
+			config.ECSMetadataURL = *configInput.ecsMetadataURL
+		}
 		if cmd.Flags().Changed("api-version") {
 			config.APIVersion = *configInput.apiVersion
 		}
-
-		data, err := yaml.Marshal(config)
-		if err != nil {
-			fmt.Printf("Failed to marshal config: %v. Error: %v\n", data, err)
-			return
+		if *configInput.decrypt {
+			gConfigPassphrase = ""
+		} else if *configInput.encrypt && gConfigPassphrase == "" {
+			gConfigPassphrase, err = configPassphrase(true)
+			if err != nil {
+				return fmt.Errorf("Failed to get the passphrase. Error: %v", err)
+			}
 		}
-		err = ioutil.WriteFile(gConfigPath, data, 0600)
+
+		return writeConfigFile(config)
+	},
+}
+
+// configPassphrase returns the passphrase from the environment variable, or prompts for it.
+// The passphrase is asked twice if it is a new one.
+func configPassphrase(isNew bool) (string, error) {
+	if passphrase := os.Getenv(util.ConfigPassphraseEnv); passphrase != "" {
+		return passphrase, nil
+	}
+	if !isInteractive() {
+		return "", util.NewValidationError("%s is required in the non-interactive mode", util.ConfigPassphraseEnv)
+	}
+	passphrase := ""
+	err := survey.AskOne(&survey.Password{Message: "Passphrase of the config file"}, &passphrase, survey.Required)
+	if err != nil || !isNew {
+		return passphrase, err
+	}
+	again := ""
+	err = survey.AskOne(&survey.Password{Message: "Confirm the passphrase"}, &again, nil)
+	if err != nil {
+		return "", err
+	}
+	if again != passphrase {
+		return "", fmt.Errorf("the passphrases do not match")
+	}
+	return passphrase, nil
+}
+
+// decryptConfigSecrets decrypts the encrypted secrets of the config, the passphrase is asked once.
+// The encrypted secrets are cleared if they can not be decrypted, so that the ciphertext is never
+// used to sign the requests.
+func decryptConfigSecrets(config *util.GlobalConfig) error {
+	if !config.HasEncryptedSecrets() {
+		return nil
+	}
+	if gConfigPassphrase == "" {
+		passphrase, err := configPassphrase(false)
 		if err != nil {
-			fmt.Printf("Failed to write file: %s. Error: %v\n", gConfigPath, err)
-			return
+			clearEncryptedSecrets(config)
+			return fmt.Errorf("failed to get the passphrase of the encrypted secrets: %v", err)
 		}
-	},
+		gConfigPassphrase = passphrase
+	}
+	if err := config.DecryptSecrets(gConfigPassphrase); err != nil {
+		gConfigPassphrase = ""
+		clearEncryptedSecrets(config)
+		return err
+	}
+	return nil
+}
+
+func clearEncryptedSecrets(config *util.GlobalConfig) {
+	if util.IsEncryptedSecret(config.AccessKeySecret) {
+		config.AccessKeySecret = ""
+	}
+	if util.IsEncryptedSecret(config.SecurityToken) {
+		config.SecurityToken = ""
+	}
+}
+
+// writeConfigFile writes the config file, the secrets are encrypted if the config file was encrypted.
+func writeConfigFile(config *util.GlobalConfig) error {
+	data, err := util.MarshalConfig(config, gConfigPassphrase)
+	if err != nil {
+		return fmt.Errorf("Failed to marshal config. Error: %v", err)
+	}
+	err = ioutil.WriteFile(gConfigPath, data, 0600)
+	if err != nil {
+		return fmt.Errorf("Failed to write file: %s. Error: %v", gConfigPath, err)
+	}
+	return nil
 }
 
 func displayConfig() string {
-	return displayConfigFile() + displayAllEnv()
+	return displayConfigFile() + displayAllEnv() + displayEffectiveConfig()
+}
+
+// displayEffectiveConfig displays the merged config and where each value came from.
+func displayEffectiveConfig() string {
+	outputStr := "Effective configuration:\n"
+	if gProjectConfigPath != "" {
+		outputStr += fmt.Sprintf("  (project config file: %s)\n", gProjectConfigPath)
+	}
+	for _, key := range util.ConfigKeys() {
+		value, _ := gConfig.GetValue(key)
+		if secretConfigKeys[key] || key == "access_key_id" {
+			value = mark(value)
+		}
+		source := gConfigSources[key]
+		if source == "" {
+			source = configSourceDefault
+		}
+		outputStr += fmt.Sprintf("  %s: %s (%s)\n", key, value, source)
+	}
+	return outputStr
 }
 
 func displayConfigFile() string {
 	outputStr := fmt.Sprintf("Config file directory: %s\n", gConfigDir)
-	config, _ := getConfigFromFile()
-	if config != nil {
-		content, _ := json.MarshalIndent(config, "", "  ")
-		outputStr += fmt.Sprintf("%s\n", string(content))
+	data, err := ioutil.ReadFile(gConfigPath)
+	if err != nil {
+		return outputStr + fmt.Sprintf("Config file does not yet exist: %s\n", gConfigPath)
 	}
-	return outputStr
+	// the secrets are masked, so there is no need to decrypt them
+	config := util.NewGlobalConfig()
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return outputStr + fmt.Sprintf("Failed to unmarshal config: %v. Error: %v\n", gConfigPath, err)
+	}
+	config.AccessKeyID = mark(config.AccessKeyID)
+	config.AccessKeySecret = markSecret(config.AccessKeySecret)
+	config.SecurityToken = markSecret(config.SecurityToken)
+	content, _ := json.MarshalIndent(config, "", "  ")
+	return outputStr + fmt.Sprintf("%s\n", string(content))
+}
+
+func markSecret(secret string) string {
+	if util.IsEncryptedSecret(secret) {
+		return "(encrypted)"
+	}
+	return mark(secret)
 }
 
 func displayAllEnv() string {
 	return fmt.Sprintf("Environment variables for fcli: \n") +
 		displayEnv("ALIBABA_CLOUD_ACCESS_KEY_ID") +
 		displayEnv("ALIBABA_CLOUD_ACCESS_KEY_SECRET") +
+		displayEnv("ALIBABA_CLOUD_SECURITY_TOKEN") +
 		displayEnv("ALIBABA_CLOUD_DEFAULT_REGION") +
 		displayEnv("ALIBABA_CLOUD_ACCOUNT_ID") +
 		fmt.Sprintf("Environment variables for fcli: (deprecated)\n") +
@@ -125,25 +285,38 @@ func displayAllEnv() string {
 
 func displayEnv(key string) string {
 	if value := os.Getenv(key); value != "" {
+		if strings.Contains(key, "ACCESS_KEY") || strings.Contains(key, "TOKEN") {
+			value = mark(value)
+		}
 		return fmt.Sprintf("  %s: %s\n", key, value)
 	}
 	return fmt.Sprintf("  %s is not set.\n", key)
 }
 
 func getConfigFromFile() (*util.GlobalConfig, error) {
+	config, err := readConfigFile()
+	if err != nil || config == nil {
+		return nil, err
+	}
+	if err := decryptConfigSecrets(config); err != nil {
+		return nil, fmt.Errorf("Failed to load config: %v. Error: %v", gConfigPath, err)
+	}
+	return config, nil
+}
+
+// readConfigFile parses the config file without decrypting the secrets, it returns nil if the
+// config file does not exist.
+func readConfigFile() (*util.GlobalConfig, error) {
 	config := util.NewGlobalConfig()
 	data, err := ioutil.ReadFile(gConfigPath)
 	if err != nil {
-		fmt.Printf("Config file does not yet exist: %s\n", gConfigPath)
+		fmt.Fprintf(os.Stderr, "Config file does not yet exist: %s\n", gConfigPath)
 		return nil, nil
 	}
-	err = yaml.Unmarshal(data, config)
-	if err != nil {
-		fmt.Printf("Failed to unmarshal config: %v. Error: %v\n", gConfigPath, err)
-		return nil, err
+	if err := yaml.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("Failed to load config: %v. Error: %v", gConfigPath, err)
 	}
 	return config, nil
-
 }
 
 // getConfigAways first try to parse the config object from the config file,
@@ -157,8 +330,7 @@ func getConfigAways() (*util.GlobalConfig, error) {
 		config = util.NewGlobalConfig()
 		err := os.Mkdir(gConfigDir, 0755)
 		if err != nil && os.IsNotExist(err) {
-			fmt.Printf("Failed to mkdir: %s. Error: %v\n", gConfigDir, err)
-			return nil, err
+			return nil, fmt.Errorf("Failed to mkdir: %s. Error: %v", gConfigDir, err)
 		}
 	}
 	return config, nil
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// the config keys which are masked by config get
+var secretConfigKeys = map[string]bool{
+	"access_key_secret": true,
+	"security_token":    true,
+}
+
+func init() {
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configGetCmd)
+	configSetCmd.Flags().Bool("help", false, "set a key in the config file")
+	configGetCmd.Flags().Bool("help", false, "get a key in the config file")
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "set a key in the config file",
+	Long: `
+set a key in the config file without prompts, the sls_endpoint is updated with the endpoint.
+The keys are: ` + strings.Join(util.ConfigKeys(), ", ") + `
+EXAMPLE:
+fcli config set endpoint https://{accountID}.cn-shanghai.fc.aliyuncs.com
+fcli config set access_key_id id
+fcli config set timeout 120
+			`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return configSetRun(args[0], args[1])
+	},
+}
+
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "get a key in the config file",
+	Long: `
+get a key in the config file, the access_key_secret and security_token are masked.
+EXAMPLE:
+fcli config get endpoint
+			`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		value, err := configGetRun(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(value)
+		return nil
+	},
+}
+
+func configSetRun(key, value string) error {
+	config, err := getConfigAways()
+	if err != nil {
+		return err
+	}
+	value = strings.TrimSpace(value)
+	if err := config.SetValue(key, value); err != nil {
+		return err
+	}
+	if key == "endpoint" {
+		config.SLSEndpoint = util.SLSEndpointForEndpoint(config.Endpoint)
+	}
+	return writeConfigFile(config)
+}
+
+func configGetRun(key string) (string, error) {
+	// the secrets are masked, so they are not decrypted, which asks for the passphrase
+	config, err := readConfigFile()
+	if err != nil {
+		return "", err
+	}
+	if config == nil {
+		config = util.NewGlobalConfig()
+	}
+	value, err := config.GetValue(key)
+	if err != nil {
+		return "", err
+	}
+	if secretConfigKeys[key] {
+		return markSecret(value), nil
+	}
+	return value, nil
+}
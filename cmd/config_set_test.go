@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestConfigNonInteractive() {
+	assert := s.Require()
+	assert.True(isConfigCommand(configSetCmd))
+	assert.False(isConfigCommand(ramRoleCmd))
+
+	os.Setenv("CI", "true")
+	defer os.Unsetenv("CI")
+	assert.False(isInteractive())
+
+	saved := gConfig
+	defer func() { gConfig = saved }()
+	os.Unsetenv("ALIBABA_CLOUD_ACCESS_KEY_ID")
+	os.Unsetenv("ALIYUN_ACCESS_KEY_ID")
+	gConfig = util.NewGlobalConfig()
+	gConfig.AccessKeyID = "id"
+	err := missingConfigError().Error()
+	assert.Contains(err, "access_key_secret")
+	assert.Contains(err, "endpoint")
+	assert.NotContains(err, "access_key_id")
+}
+
+func (s *FunctionStructsTestSuite) TestApplyConfigFlagDefaults() {
+	assert := s.Require()
+	saved := gConfig
+	defer func() { gConfig = saved }()
+	gConfig = util.NewGlobalConfig()
+	gConfig.ServiceName = "demo"
+	gConfig.Qualifier = "prod"
+
+	applyConfigFlagDefaults(getFuncCmd)
+	assert.Equal("demo", getFuncCmd.Flags().Lookup("service-name").Value.String())
+	assert.Equal("prod", *getFuncInput.Qualifier)
+	assert.False(getFuncCmd.Flags().Changed("qualifier"))
+	getFuncCmd.Flags().Lookup("service-name").Value.Set("")
+	getFuncCmd.Flags().Lookup("qualifier").Value.Set("")
+}
+
+func (s *FunctionStructsTestSuite) TestDecryptConfigSecrets() {
+	assert := s.Require()
+	passphrase := gConfigPassphrase
+	defer func() { gConfigPassphrase = passphrase }()
+	defer os.Unsetenv(util.ConfigPassphraseEnv)
+
+	secret, err := util.EncryptSecret("secret", "right")
+	assert.Nil(err)
+	config := util.NewGlobalConfig()
+	config.AccessKeySecret = secret
+	os.Setenv(util.ConfigPassphraseEnv, "right")
+	gConfigPassphrase = ""
+	assert.Nil(decryptConfigSecrets(config))
+	assert.Equal("secret", config.AccessKeySecret)
+
+	// the ciphertext is never left in the config to sign the requests
+	config.AccessKeySecret = secret
+	os.Setenv(util.ConfigPassphraseEnv, "wrong")
+	gConfigPassphrase = ""
+	assert.NotNil(decryptConfigSecrets(config))
+	assert.Equal("", config.AccessKeySecret)
+	assert.Equal("", gConfigPassphrase)
+
+	os.Unsetenv(util.ConfigPassphraseEnv)
+	os.Setenv("CI", "true")
+	defer os.Unsetenv("CI")
+	config.AccessKeySecret = secret
+	assert.NotNil(decryptConfigSecrets(config))
+	assert.Equal("", config.AccessKeySecret)
+}
+
+func (s *FunctionStructsTestSuite) TestConfigGetEncrypted() {
+	assert := s.Require()
+	dir, err := ioutil.TempDir("", "fcli-config")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	savedPath, savedPassphrase := gConfigPath, gConfigPassphrase
+	defer func() { gConfigPath, gConfigPassphrase = savedPath, savedPassphrase }()
+	gConfigPath, gConfigPassphrase = path.Join(dir, "config.yaml"), ""
+
+	// the missing config file has the default values
+	value, err := configGetRun("timeout")
+	assert.Nil(err)
+	assert.Equal("60", value)
+
+	cfg := util.NewGlobalConfig()
+	cfg.Endpoint = "https://1.cn-hangzhou.fc.aliyuncs.com"
+	cfg.AccessKeySecret = "secret"
+	data, err := util.MarshalConfig(cfg, "passphrase")
+	assert.Nil(err)
+	assert.Nil(ioutil.WriteFile(gConfigPath, data, 0600))
+
+	// the secret is masked without asking for the passphrase
+	value, err = configGetRun("access_key_secret")
+	assert.Nil(err)
+	assert.Equal("(encrypted)", value)
+	value, err = configGetRun("endpoint")
+	assert.Nil(err)
+	assert.Equal(cfg.Endpoint, value)
+}
@@ -24,6 +24,7 @@ func init() {
 		"description", "d", "", "version description, optional")
 	createRoutes = createAliasCmd.Flags().StringArrayP(
 		"route", "r", []string{}, "additional version weight for dark launch purpose, optional")
+	addInputJSONFlags(createAliasCmd)
 }
 
 var createRoutes = new([]string)
@@ -42,17 +43,38 @@ fcli alias create -s(--service-name)      service_name
 			-v(--version-id)  1
 			-d(--description) description
 			-r(--route)       2=0.05
+fcli alias create --generate-skeleton > alias.json
+fcli alias create --input-json alias.json -v(--version-id) 1
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := util.NewFClient(gConfig)
-		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.CreateAliasInput{})
+		}
+		input := &fc.CreateAliasInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = createAliasInput.ServiceName
+		}
+		if useFlag(cmd, "alias-name") {
+			input.AliasName = createAliasInput.AliasName
 		}
-		createAliasInput.AdditionalVersionWeight = util.ParseAdditionalVersionWeight(*createRoutes)
-		_, err = client.CreateAlias(&createAliasInput)
+		if useFlag(cmd, "version-id") {
+			input.VersionID = createAliasInput.VersionID
+		}
+		if useFlag(cmd, "description") {
+			input.Description = createAliasInput.Description
+		}
+		if useFlag(cmd, "route") {
+			input.AdditionalVersionWeight = util.ParseAdditionalVersionWeight(*createRoutes)
+		}
+
+		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
+		_, err = client.CreateAlias(input)
+		return err
 	},
 }
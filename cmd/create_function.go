@@ -66,32 +66,66 @@ func init() {
 		&createFuncInput.initializer, "initializer", "i", "", "initializer is the entrypoint for the initializer execution")
 	createFuncCmd.Flags().StringArrayVar(&createFuncInput.environmentVariables, "env", []string{}, "set environment variables. e.g. --env VAR1=val1 --env VAR2=val2")
 	createFuncCmd.Flags().StringArrayVar(&createFuncInput.environmentConfigFiles, "env-file", []string{}, "read in a file of environment variables. e.g. --env-file FILE1 --env-file FILE2")
+	addInputJSONFlags(createFuncCmd)
 }
 
 var createFuncCmd = &cobra.Command{
 	Use:     "create",
 	Aliases: []string{"c"},
 	Short:   "Create function",
-	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		input := fc.NewCreateFunctionInput(createFuncInput.serviceName).
-			WithFunctionName(createFuncInput.functionName).
-			WithDescription(createFuncInput.description).
-			WithMemorySize(createFuncInput.memory).
-			WithTimeout(createFuncInput.timeout).
-			WithInitializationTimeout(createFuncInput.initializationTimeout).
-			WithHandler(createFuncInput.handler).
-			WithInitializer(createFuncInput.initializer).
-			WithRuntime(createFuncInput.runtime)
+	Long: `
+create function, the settings which the flags can not express can be read from the json of the input
+EXAMPLE:
+fcli function create --generate-skeleton > function.json
+fcli function create --input-json function.json -s(--service-name) service_name -f(--function-name) function_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.CreateFunctionInput{})
+		}
+		input := &fc.CreateFunctionInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = &createFuncInput.serviceName
+		}
+		if useFlag(cmd, "function-name") {
+			input.WithFunctionName(createFuncInput.functionName)
+		}
+		if useFlag(cmd, "description") {
+			input.WithDescription(createFuncInput.description)
+		}
+		if useFlag(cmd, "memory") {
+			input.WithMemorySize(createFuncInput.memory)
+		}
+		if useFlag(cmd, "timeout") {
+			input.WithTimeout(createFuncInput.timeout)
+		}
+		if useFlag(cmd, "initializationTimeout") {
+			input.WithInitializationTimeout(createFuncInput.initializationTimeout)
+		}
+		if useFlag(cmd, "handler") {
+			input.WithHandler(createFuncInput.handler)
+		}
+		if useFlag(cmd, "initializer") {
+			input.WithInitializer(createFuncInput.initializer)
+		}
+		if useFlag(cmd, "runtime") {
+			input.WithRuntime(createFuncInput.runtime)
+		}
 
+		// the variables of the flags are added to the ones of --input-json
 		envMap := make(map[string]string)
+		for k, v := range input.EnvironmentVariables {
+			envMap[k] = v
+		}
 
 		if cmd.Flags().Changed("env-file") {
 			for _, envFilePath := range createFuncInput.environmentConfigFiles {
 				_, err := util.GetEnvSetting(envMap, envFilePath)
 				if err != nil {
-					fmt.Printf("Error: %s\n", err)
-					return
+					return err
 				}
 			}
 			input.WithEnvironmentVariables(envMap)
@@ -107,7 +141,7 @@ var createFuncCmd = &cobra.Command{
 			input.WithEnvironmentVariables(envMap)
 		}
 
-		if createFuncInput.runtime == util.RuntimeCustomContainer {
+		if input.Runtime != nil && *input.Runtime == util.RuntimeCustomContainer {
 			input = createFunctionInputWithCustomContainerConfig(cmd.Flags(), input, createFuncInput.customContainerImage,
 				createFuncInput.customContainerCommand, createFuncInput.customContainerArgs)
 		} else {
@@ -115,16 +149,21 @@ var createFuncCmd = &cobra.Command{
 				var data []byte
 				data, err := ioutil.ReadFile(createFuncInput.codeFile)
 				if err != nil {
-					fmt.Printf("Error: %s\n", err)
-					return
+					return err
 				}
 				input.WithCode(fc.NewCode().WithZipFile(data))
 			} else if createFuncInput.codeDir != "" {
 				input.WithCode(fc.NewCode().WithDir(createFuncInput.codeDir))
-			} else {
-				input.WithCode(fc.NewCode().
-					WithOSSBucketName(createFuncInput.codeOSSBucket).
-					WithOSSObjectName(createFuncInput.codeOSSObject))
+			} else if useFlag(cmd, "code-bucket") || useFlag(cmd, "code-object") {
+				if input.Code == nil {
+					input.WithCode(fc.NewCode())
+				}
+				if useFlag(cmd, "code-bucket") {
+					input.Code.WithOSSBucketName(createFuncInput.codeOSSBucket)
+				}
+				if useFlag(cmd, "code-object") {
+					input.Code.WithOSSObjectName(createFuncInput.codeOSSObject)
+				}
 			}
 		}
 
@@ -134,13 +173,10 @@ var createFuncCmd = &cobra.Command{
 
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		_, err = client.CreateFunction(input)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
 
@@ -38,6 +38,7 @@ func init() {
 		"at least one nas server is required to enable the NAS access")
 	createServiceInput.nasMount = createServiceCmd.Flags().StringArrayP("nas-mount-dir", "", []string{},
 		"at least one nas dir is required to enable the NAS access")
+	addInputJSONFlags(createServiceCmd)
 }
 
 // ServiceInput defines service input
@@ -63,44 +64,86 @@ var createServiceCmd = &cobra.Command{
 	Use:     "create [option]",
 	Aliases: []string{"c"},
 	Short:   "create service",
-	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		mountPoints := []fc.NASMountConfig{}
-		if len(*createServiceInput.nasServer) != len(*createServiceInput.nasMount) {
-			fmt.Println("nas server array length must match nas dir array length")
-			return
-		}
-		for i, addr := range *createServiceInput.nasServer {
-			mountPoints = append(mountPoints, fc.NASMountConfig{
-				ServerAddr: addr,
-				MountDir:   (*createServiceInput.nasMount)[i],
-			})
-		}
-		input := fc.NewCreateServiceInput().
-			WithServiceName(*createServiceInput.serviceName).
-			WithDescription(*createServiceInput.description).
-			WithInternetAccess(*createServiceInput.internetAccess).
-			WithLogConfig(fc.NewLogConfig().
-				WithProject(*createServiceInput.logProject).
-				WithLogstore(*createServiceInput.logStore)).
-			WithRole(*createServiceInput.role).
-			WithVPCConfig(fc.NewVPCConfig().
-				WithVPCID(*createServiceInput.vpcID).
-				WithVSwitchIDs(*createServiceInput.vSwitchIDs).
-				WithSecurityGroupID(*createServiceInput.securityGroupID)).
-			WithNASConfig(fc.NewNASConfig().
-				WithUserID(*createServiceInput.nasUserID).
-				WithGroupID(*createServiceInput.nasGroupID).
-				WithMountPoints(mountPoints))
+	Long: `
+create service, the settings which the flags can not express can be read from the json of the input
+EXAMPLE:
+fcli service create --generate-skeleton > service.json
+fcli service create --input-json service.json -s(--service-name) service_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.CreateServiceInput{})
+		}
+		input := fc.NewCreateServiceInput()
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.WithServiceName(*createServiceInput.serviceName)
+		}
+		if useFlag(cmd, "description") {
+			input.WithDescription(*createServiceInput.description)
+		}
+		if useFlag(cmd, "internet-access") {
+			input.WithInternetAccess(*createServiceInput.internetAccess)
+		}
+		if useFlag(cmd, "role-arn") {
+			input.WithRole(*createServiceInput.role)
+		}
+
+		if input.LogConfig == nil && (useFlag(cmd, "log-project") || useFlag(cmd, "log-store")) {
+			input.WithLogConfig(fc.NewLogConfig())
+		}
+		if useFlag(cmd, "log-project") {
+			input.LogConfig.WithProject(*createServiceInput.logProject)
+		}
+		if useFlag(cmd, "log-store") {
+			input.LogConfig.WithLogstore(*createServiceInput.logStore)
+		}
+
+		if input.VPCConfig == nil &&
+			(useFlag(cmd, "vpc-id") || useFlag(cmd, "v-switch-ids") || useFlag(cmd, "security-group-id")) {
+			input.WithVPCConfig(fc.NewVPCConfig())
+		}
+		if useFlag(cmd, "vpc-id") {
+			input.VPCConfig.WithVPCID(*createServiceInput.vpcID)
+		}
+		if useFlag(cmd, "v-switch-ids") {
+			input.VPCConfig.WithVSwitchIDs(*createServiceInput.vSwitchIDs)
+		}
+		if useFlag(cmd, "security-group-id") {
+			input.VPCConfig.WithSecurityGroupID(*createServiceInput.securityGroupID)
+		}
+
+		useMountPoints := useFlag(cmd, "nas-server-addr") || useFlag(cmd, "nas-mount-dir")
+		if input.NASConfig == nil && (useFlag(cmd, "nas-userid") || useFlag(cmd, "nas-groupid") || useMountPoints) {
+			input.WithNASConfig(fc.NewNASConfig())
+		}
+		if useFlag(cmd, "nas-userid") {
+			input.NASConfig.WithUserID(*createServiceInput.nasUserID)
+		}
+		if useFlag(cmd, "nas-groupid") {
+			input.NASConfig.WithGroupID(*createServiceInput.nasGroupID)
+		}
+		if useMountPoints {
+			if len(*createServiceInput.nasServer) != len(*createServiceInput.nasMount) {
+				return util.NewValidationError("nas server array length must match nas dir array length")
+			}
+			mountPoints := []fc.NASMountConfig{}
+			for i, addr := range *createServiceInput.nasServer {
+				mountPoints = append(mountPoints, fc.NASMountConfig{
+					ServerAddr: addr,
+					MountDir:   (*createServiceInput.nasMount)[i],
+				})
+			}
+			input.NASConfig.WithMountPoints(mountPoints)
+		}
 
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		_, err = client.CreateService(input)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
@@ -1,13 +1,17 @@
 package cmd
 
 import (
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
+	"os"
+	"strings"
+	"time"
+
 	"github.com/aliyun/fc-go-sdk"
 	"github.com/aliyun/fcli/util"
 
 	"github.com/spf13/cobra"
-	"github.com/spf13/viper"
 )
 
 type createTriggerInputType struct {
@@ -19,6 +23,10 @@ type createTriggerInputType struct {
 	invocationRole    *string
 	qualifier         *string
 	triggerConfigFile *string
+	sourceBucket      *string
+	sourceLogstore    *string
+	sourceTopic       *string
+	autoRole          *bool
 }
 
 // CreateTriggerCliOutput a wrapper around CreateTriggerOutput in order to add in more information
@@ -38,16 +46,19 @@ func (o CreateTriggerCliOutput) String() string {
 // MarshalJSON define how CreateTriggerCliOutput should be displayed in JSON format
 func (o CreateTriggerCliOutput) MarshalJSON() ([]byte, error) {
 	return json.Marshal(triggerCliOutputDisplay{
-		HTTPTriggerURL:   o.HTTPTriggerURL,
-		Header:           o.Header,
-		TriggerName:      o.TriggerName,
-		SourceARN:        o.SourceARN,
-		TriggerType:      o.TriggerType,
-		InvocationRole:   o.InvocationRole,
-		Qualifier:        o.Qualifier,
-		TriggerConfig:    o.TriggerConfig,
-		CreatedTime:      o.CreatedTime,
-		LastModifiedTime: o.LastModifiedTime,
+		HTTPTriggerURL:     o.HTTPTriggerURL,
+		TableStoreInstance: o.TableStoreInstance,
+		TableStoreTable:    o.TableStoreTable,
+		RDSInstance:        o.RDSInstance,
+		Header:             o.Header,
+		TriggerName:        o.TriggerName,
+		SourceARN:          o.SourceARN,
+		TriggerType:        o.TriggerType,
+		InvocationRole:     o.InvocationRole,
+		Qualifier:          o.Qualifier,
+		TriggerConfig:      o.TriggerConfig,
+		CreatedTime:        o.CreatedTime,
+		LastModifiedTime:   o.LastModifiedTime,
 	})
 }
 
@@ -66,7 +77,7 @@ func init() {
 	createTriggerInput.triggerName = createTriggerCmd.Flags().StringP(
 		"trigger-name", "t", "", "the trigger name")
 	createTriggerInput.triggerType = createTriggerCmd.Flags().String(
-		"type", "", "trigger type, support oss, log, timer, http, cdn_events, mns_topic")
+		"type", "", "trigger type, support "+strings.Join(util.TriggerTypes, ", "))
 	createTriggerInput.sourceARN = createTriggerCmd.Flags().StringP(
 		"source-arn", "a", "", "event source arn,for example, acs:oss:cn-hangzhou:123456:bucket1.timer trigger optional")
 	createTriggerInput.invocationRole = createTriggerCmd.Flags().StringP(
@@ -86,6 +97,15 @@ func init() {
             		            suffix: bar
 }		`)
 	createTriggerInput.qualifier = createTriggerCmd.Flags().StringP("qualifier", "q", "", "service version or alias, optional")
+	createTriggerInput.sourceBucket = createTriggerCmd.Flags().String(
+		"source-bucket", "", "oss bucket, the source arn of the oss trigger is built from it and the endpoint")
+	createTriggerInput.sourceLogstore = createTriggerCmd.Flags().String(
+		"source-logstore", "", "<project>/<logstore>, the source arn of the log trigger is built from it and the endpoint")
+	createTriggerInput.sourceTopic = createTriggerCmd.Flags().String(
+		"source-topic", "", "mns topic, the source arn of the mns_topic trigger is built from it and the endpoint")
+	createTriggerInput.autoRole = createTriggerCmd.Flags().Bool(
+		"auto-role", false, "create or reuse an invocation role which is only allowed to invoke the function")
+	addInputJSONFlags(createTriggerCmd)
 }
 
 var createTriggerCmd = &cobra.Command{
@@ -105,6 +125,17 @@ fcli trigger create -s(service-name)  demo_service
 				   -c(config)        oss_trigger_sample.yaml
 				   -q(qualifier)     LATEST
 
+fcli trigger create -s(service-name)  demo_service
+				   -f(function-name) demo_function
+				   -t(trigger-name)  demo_trigger
+				   -type             oss
+				   --source-bucket   bucket1
+				   --auto-role
+				   -c(config)        oss_trigger_sample.yaml
+
+fcli trigger create --generate-skeleton > trigger.json
+fcli trigger create --input-json trigger.json -t(trigger-name) demo_trigger
+
 oss_trigger_sample.yaml example:
 triggerConfig:
     events: 
@@ -115,8 +146,11 @@ triggerConfig:
             prefix: foo
             suffix: bar
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		prettyPrint(createTriggerRun(cmd))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.CreateTriggerInput{})
+		}
+		return prettyPrint(createTriggerRun(cmd))
 	},
 }
 
@@ -129,11 +163,18 @@ func createTriggerRun(cmd *cobra.Command) (*CreateTriggerCliOutput, error) {
 	if err != nil {
 		return nil, err
 	}
-	createTriggerInput, err := prepareCreateTriggerInput()
+	input, err := prepareCreateTriggerInput(cmd)
 	if err != nil {
 		return nil, err
 	}
-	response, serviceError := client.CreateTrigger(createTriggerInput)
+	if *createTriggerInput.autoRole {
+		roleARN, err := ensureTriggerInvocationRole(input)
+		if err != nil {
+			return nil, err
+		}
+		input.WithInvocationRole(roleARN)
+	}
+	response, serviceError := client.CreateTrigger(input)
 	if serviceError != nil {
 		return nil, serviceError
 	}
@@ -141,31 +182,50 @@ func createTriggerRun(cmd *cobra.Command) (*CreateTriggerCliOutput, error) {
 	output := &CreateTriggerCliOutput{
 		CreateTriggerOutput: *response,
 	}
-	decorateTriggerOutput(createTriggerInput.TriggerType, &output.triggerCliOutputDecorate)
+	decorateTriggerOutput(input.TriggerType, input.SourceARN, &output.triggerCliOutputDecorate)
 
 	return output, nil
 }
 
-func prepareCreateTriggerInput() (*fc.CreateTriggerInput, error) {
-	viper.SetConfigFile(*createTriggerInput.triggerConfigFile)
-	err := viper.ReadInConfig()
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s due to %v", *createTriggerInput.triggerConfigFile, err)
+func prepareCreateTriggerInput(cmd *cobra.Command) (*fc.CreateTriggerInput, error) {
+	input := &fc.CreateTriggerInput{}
+	if err := readInputJSON(cmd, input); err != nil {
+		return nil, err
+	}
+	if useFlag(cmd, "service-name") {
+		input.ServiceName = createTriggerInput.serviceName
 	}
-	triggerConfig, err := util.GetTriggerConfig(*createTriggerInput.triggerType, *createTriggerInput.triggerConfigFile)
+	if useFlag(cmd, "function-name") {
+		input.FunctionName = createTriggerInput.functionName
+	}
+	if useFlag(cmd, "trigger-name") {
+		input.WithTriggerName(*createTriggerInput.triggerName)
+	}
+	if useFlag(cmd, "type") {
+		input.WithTriggerType(*createTriggerInput.triggerType)
+	}
+	triggerType := stringValue(input.TriggerType)
+
+	if useFlag(cmd, "config") {
+		triggerConfig, err := util.GetTriggerConfig(triggerType, *createTriggerInput.triggerConfigFile)
+		if err != nil {
+			return nil, err
+		}
+		input.WithTriggerConfig(triggerConfig)
+	}
+
+	sourceARN, err := getCreateTriggerSourceARN(triggerType, input.TriggerConfig)
 	if err != nil {
 		return nil, err
 	}
 
-	input := fc.NewCreateTriggerInput(*createTriggerInput.serviceName, *createTriggerInput.functionName).
-		WithTriggerName(*createTriggerInput.triggerName).
-		WithTriggerType(*createTriggerInput.triggerType).
-		WithTriggerConfig(triggerConfig)
-
-	if *createTriggerInput.sourceARN != "" {
-		input.WithSourceARN(*createTriggerInput.sourceARN)
+	if sourceARN != "" {
+		input.WithSourceARN(sourceARN)
 	}
 
+	if *createTriggerInput.invocationRole != "" && *createTriggerInput.autoRole {
+		return nil, util.NewValidationError("--role and --auto-role can not be used together")
+	}
 	if *createTriggerInput.invocationRole != "" {
 		input.WithInvocationRole(*createTriggerInput.invocationRole)
 	}
@@ -177,5 +237,94 @@ func prepareCreateTriggerInput() (*fc.CreateTriggerInput, error) {
 	return input, nil
 }
 
-func init() {
+// getCreateTriggerSourceARN returns the source arn specified directly or built from the event source flags.
+func getCreateTriggerSourceARN(triggerType string, triggerConfig interface{}) (string, error) {
+	sources := map[string]*string{
+		"source-bucket":   createTriggerInput.sourceBucket,
+		"source-logstore": createTriggerInput.sourceLogstore,
+		"source-topic":    createTriggerInput.sourceTopic,
+	}
+	sourceTypes := map[string]string{
+		"source-bucket":   fc.TRIGGER_TYPE_OSS,
+		"source-logstore": fc.TRIGGER_TYPE_LOG,
+		"source-topic":    fc.TRIGGER_TYPE_MNS_TOPIC,
+	}
+	flag, source := "", ""
+	for name, value := range sources {
+		if *value == "" {
+			continue
+		}
+		if sourceTypes[name] != triggerType {
+			return "", util.NewValidationError("--%s is only for %s trigger", name, sourceTypes[name])
+		}
+		flag, source = name, *value
+	}
+	if source == "" {
+		return *createTriggerInput.sourceARN, nil
+	}
+	if *createTriggerInput.sourceARN != "" {
+		return "", util.NewValidationError("--source-arn and --%s can not be used together", flag)
+	}
+
+	// the logstore in the source must be the one consumed by the log trigger
+	if logConfig, ok := triggerConfig.(fc.LogTriggerConfig); ok {
+		parts := strings.SplitN(source, "/", 2)
+		if len(parts) == 2 && logConfig.SourceConfig != nil && logConfig.SourceConfig.Logstore != nil &&
+			*logConfig.SourceConfig.Logstore != parts[1] {
+			return "", util.NewValidationError("--source-logstore %s conflicts with the logstore %s in the trigger config",
+				source, *logConfig.SourceConfig.Logstore)
+		}
+	}
+	return util.BuildSourceARN(triggerType, gConfig.Endpoint, source)
+}
+
+// ensureTriggerInvocationRole creates or reuses the invocation role of the trigger,
+// the role is only allowed to invoke the function.
+func ensureTriggerInvocationRole(input *fc.CreateTriggerInput) (string, error) {
+	triggerType := stringValue(input.TriggerType)
+	principal, ok := util.TriggerInvocationPrincipals[triggerType]
+	if !ok {
+		return "", fmt.Errorf("%s trigger does not need an invocation role", triggerType)
+	}
+	uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	region, err := util.GetRegionForEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	service := stringValue(input.ServiceName)
+	function := stringValue(input.FunctionName)
+
+	ramCli, err := util.NewRAMClient(gConfig)
+	if err != nil {
+		return "", err
+	}
+	name := ramName("fcli-" + triggerType + "-" + service + "-" + function)
+	action := `["fc:InvokeFunction"]`
+	resource := fmt.Sprintf(`["acs:fc:%s:%s:services/%s/functions/%s", "acs:fc:%s:%s:services/%s.*/functions/%s"]`,
+		region, uid, service, function, region, uid, service, function)
+	roleARN, err := util.BindRolePolicyIdempotent(ramCli, name, principal, name, action, resource)
+	if err != nil {
+		return "", err
+	}
+	// has latency when attach policy to role, wait to avoid the inconsistent for policy and role.
+	fmt.Fprintf(os.Stderr, "use invocation role %s\n", roleARN)
+	time.Sleep(3 * time.Second)
+	return roleARN, nil
+}
+
+// ramName converts the name to a valid ram role or policy name. A short hash of the name is appended
+// if it has to be changed, so that the different names do not end up with the same role or policy.
+func ramName(name string) string {
+	valid := strings.Replace(name, "_", "-", -1)
+	if valid == name && len(name) <= 64 {
+		return name
+	}
+	hash := fmt.Sprintf("%x", sha1.Sum([]byte(name)))[:8]
+	if len(valid) > 64-len(hash)-1 {
+		valid = valid[:64-len(hash)-1]
+	}
+	return valid + "-" + hash
 }
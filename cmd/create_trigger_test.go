@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"strings"
+)
+
+func (s *FunctionStructsTestSuite) TestRAMName() {
+	assert := s.Require()
+	assert.Equal("fcli-oss-svc-fn", ramName("fcli-oss-svc-fn"))
+
+	underscore := ramName("fcli-oss-a_b-fn")
+	assert.True(strings.HasPrefix(underscore, "fcli-oss-a-b-fn-"))
+	assert.NotEqual(ramName("fcli-oss-a-b-fn"), underscore)
+
+	long := "fcli-oss-" + strings.Repeat("s", 60)
+	assert.Len(ramName(long+"-f1"), 64)
+	assert.NotEqual(ramName(long+"-f1"), ramName(long+"-f2"))
+	assert.Equal(ramName(long+"-f1"), ramName(long+"-f1"))
+}
@@ -34,11 +34,10 @@ var deleteServiceCmd = &cobra.Command{
 	Short:   "Delete service",
 	Long:    ``,
 
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 
 		input := fc.NewDeleteServiceInput(*deleteServiceInput.serviceName)
@@ -47,8 +46,6 @@ var deleteServiceCmd = &cobra.Command{
 		}
 
 		_, err = client.DeleteService(input)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
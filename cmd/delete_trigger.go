@@ -27,8 +27,8 @@ fcli trigger delete -s(--service-name)  service_name
 				   
 You can get etag from trigger get cmd, then delete with condition
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		prettyPrint(deleteTriggerRun(cmd))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(deleteTriggerRun(cmd))
 	},
 }
 
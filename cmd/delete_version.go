@@ -33,15 +33,12 @@ EXAMPLE:
 fcli service version delete -s(--service-name)   service_name
 				-v(--version-id) 1
 				`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		_, err = client.DeleteServiceVersion(&deleteServiceVersionInput)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var eventTriggerInput struct {
+	triggerType *string
+	sourceARN   *string
+	format      *string
+	outFile     *string
+}
+
+func init() {
+	triggerCmd.AddCommand(eventTriggerCmd)
+	eventTriggerCmd.Flags().Bool("help", false, "generate sample trigger event")
+	eventTriggerInput.triggerType = eventTriggerCmd.Flags().String(
+		"type", "", "trigger type, support tablestore, rds")
+	eventTriggerInput.sourceARN = eventTriggerCmd.Flags().StringP(
+		"source-arn", "a", "", "event source arn, the instance and table names in the event are taken from it")
+	eventTriggerInput.format = eventTriggerCmd.Flags().String(
+		"format", "json", "event format, json or cbor. the tablestore trigger sends the event in cbor")
+	eventTriggerInput.outFile = eventTriggerCmd.Flags().String(
+		"out-file", "", "write the event to the file instead of stdout")
+}
+
+var eventTriggerCmd = &cobra.Command{
+	Use:   "event",
+	Short: "generate a sample trigger event for local testing",
+	Long: `
+generate a sample trigger event, which can be used by "fcli function invoke --event-file"
+or the local sandbox to test the function.
+EXAMPLE:
+fcli trigger event --type tablestore
+				-a(--source-arn) acs:ots:cn-hangzhou:123456:instance/ins1/table/table1
+				--format         cbor
+				--out-file       tablestore_event.cbor
+fcli trigger event --type rds --out-file rds_event.json
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := eventTriggerRun(cmd)
+		if err != nil || output == nil {
+			// nothing is printed if the event is written to the file
+			return err
+		}
+		return prettyPrint(*output, nil)
+	},
+}
+
+func eventTriggerRun(cmd *cobra.Command) (*string, error) {
+	event, err := util.GenerateTriggerEvent(*eventTriggerInput.triggerType, *eventTriggerInput.sourceARN, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	var data []byte
+	switch *eventTriggerInput.format {
+	case "json":
+		data, err = json.MarshalIndent(event, "", "  ")
+	case "cbor":
+		if *eventTriggerInput.outFile == "" {
+			return nil, fmt.Errorf("cbor event is binary, please specify the output file by --out-file")
+		}
+		data, err = util.EncodeCBOR(event)
+	default:
+		return nil, util.NewValidationError("unsupported event format, expect json or cbor, actual %s", *eventTriggerInput.format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if *eventTriggerInput.outFile == "" {
+		output := string(data)
+		return &output, nil
+	}
+	if err := ioutil.WriteFile(*eventTriggerInput.outFile, data, 0644); err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(os.Stderr, "the event is written to %s\n", *eventTriggerInput.outFile)
+	return nil, nil
+}
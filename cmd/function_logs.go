@@ -47,11 +47,8 @@ fetch all the function logs within [start, end)
 time format is UTC RFC3339, such as 2017-01-01T01:02:03Z
    		  `,
 
-	Run: func(cmd *cobra.Command, args []string) {
-		err := functionLogsRun(cmd)
-		if err != nil {
-			fmt.Println(err)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return functionLogsRun(cmd)
 	},
 }
 
@@ -81,10 +78,17 @@ func functionLogsRun(cmd *cobra.Command) error {
 		//endpoint := fmt.Sprintf(util.LogEndpointFmt, util.GetRegionNo(basicConfig.Endpoint))
 		project := *smeta.LogConfig.Project
 		logstore := *smeta.LogConfig.Logstore
-		slsProject, err := sls.NewLogProject(project, gConfig.SLSEndpoint, gConfig.AccessKeyID, gConfig.AccessKeySecret)
+		cred, err := gConfig.Credentials()
+		if err != nil {
+			return err
+		}
+		slsProject, err := sls.NewLogProject(project, gConfig.SLSEndpoint, cred.AccessKeyID, cred.AccessKeySecret)
 		if err != nil {
 			return err
 		}
+		if cred.SecurityToken != "" {
+			slsProject.WithToken(cred.SecurityToken)
+		}
 		slsLogstore, err := slsProject.GetLogStore(logstore)
 		if err != nil {
 			return err
@@ -101,7 +105,7 @@ func functionLogsRun(cmd *cobra.Command) error {
 		if cmd.Flags().Changed("start") {
 			startTime, err := time.Parse(util.TimeLayoutInLogs, *logParams.startTime)
 			if err != nil {
-				return fmt.Errorf("start time format error, expect:%s, actual:%s", util.TimeLayoutInLogs, *logParams.startTime)
+				return util.NewValidationError("start time format error, expect:%s, actual:%s", util.TimeLayoutInLogs, *logParams.startTime)
 			}
 			startTimestamp := startTime.Unix()
 			var endTimestamp int64
@@ -110,7 +114,7 @@ func functionLogsRun(cmd *cobra.Command) error {
 			} else {
 				endTime, err := time.Parse(util.TimeLayoutInLogs, *logParams.endTime)
 				if err != nil {
-					return fmt.Errorf("end time format error, expect:%s, actual:%s", util.TimeLayoutInLogs, *logParams.endTime)
+					return util.NewValidationError("end time format error, expect:%s, actual:%s", util.TimeLayoutInLogs, *logParams.endTime)
 				}
 				endTimestamp = endTime.Unix()
 			}
@@ -32,17 +32,16 @@ EXAMPLE:
 fcli alias get -s(--service-name)        service_name
 			-a(--alias-name) alias_name
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		resp, err := client.GetAlias(&getAliasInput)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		} else {
-			fmt.Println(resp)
+			return err
 		}
+		fmt.Println(resp)
+		return nil
 	},
 }
@@ -25,17 +25,16 @@ var getServiceCmd = &cobra.Command{
 	Short:   "Get the information of service",
 	Long:    ``,
 
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		resp, err := client.GetService(&getServiceInput)
-		if err == nil {
-			fmt.Printf("%s\n", resp)
-		} else {
-			fmt.Printf("Error: %s\n", err)
+		if err != nil {
+			return err
 		}
+		fmt.Printf("%s\n", resp)
+		return nil
 	},
 }
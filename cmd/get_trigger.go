@@ -9,16 +9,19 @@ import (
 )
 
 type triggerCliOutputDisplay struct {
-	Header           http.Header
-	HTTPTriggerURL   *string     `json:"httpUrl,omitempty"`
-	TriggerName      *string     `json:"triggerName"`
-	SourceARN        *string     `json:"sourceArn"`
-	TriggerType      *string     `json:"triggerType"`
-	InvocationRole   *string     `json:"invocationRole"`
-	Qualifier        *string     `json:"qualifier"`
-	TriggerConfig    interface{} `json:"triggerConfig"`
-	CreatedTime      *string     `json:"createdTime"`
-	LastModifiedTime *string     `json:"lastModifiedTime"`
+	Header             http.Header
+	HTTPTriggerURL     *string     `json:"httpUrl,omitempty"`
+	TableStoreInstance *string     `json:"tablestoreInstance,omitempty"`
+	TableStoreTable    *string     `json:"tablestoreTable,omitempty"`
+	RDSInstance        *string     `json:"rdsInstance,omitempty"`
+	TriggerName        *string     `json:"triggerName"`
+	SourceARN          *string     `json:"sourceArn"`
+	TriggerType        *string     `json:"triggerType"`
+	InvocationRole     *string     `json:"invocationRole"`
+	Qualifier          *string     `json:"qualifier"`
+	TriggerConfig      interface{} `json:"triggerConfig"`
+	CreatedTime        *string     `json:"createdTime"`
+	LastModifiedTime   *string     `json:"lastModifiedTime"`
 }
 
 // GetTriggerCliOutput is an envelope struct to decorate fc api response with additional information
@@ -38,16 +41,19 @@ func (o GetTriggerCliOutput) String() string {
 // MarshalJSON defines how GetTriggerCliOutput should be displayed in JSON format
 func (o GetTriggerCliOutput) MarshalJSON() ([]byte, error) {
 	return json.Marshal(triggerCliOutputDisplay{
-		HTTPTriggerURL:   o.HTTPTriggerURL,
-		Header:           o.Header,
-		TriggerName:      o.TriggerName,
-		SourceARN:        o.SourceARN,
-		TriggerType:      o.TriggerType,
-		InvocationRole:   o.InvocationRole,
-		Qualifier:        o.Qualifier,
-		TriggerConfig:    o.TriggerConfig,
-		CreatedTime:      o.CreatedTime,
-		LastModifiedTime: o.LastModifiedTime,
+		HTTPTriggerURL:     o.HTTPTriggerURL,
+		TableStoreInstance: o.TableStoreInstance,
+		TableStoreTable:    o.TableStoreTable,
+		RDSInstance:        o.RDSInstance,
+		Header:             o.Header,
+		TriggerName:        o.TriggerName,
+		SourceARN:          o.SourceARN,
+		TriggerType:        o.TriggerType,
+		InvocationRole:     o.InvocationRole,
+		Qualifier:          o.Qualifier,
+		TriggerConfig:      o.TriggerConfig,
+		CreatedTime:        o.CreatedTime,
+		LastModifiedTime:   o.LastModifiedTime,
 	})
 }
 
@@ -66,8 +72,8 @@ fcli trigger get -s(--service-name)  service_name
 				-f(--function-name) function_name 
 				-t(--trigger-name)  trigger_name
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		prettyPrint(getTriggerRun(cmd))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(getTriggerRun(cmd))
 	},
 }
 
@@ -86,7 +92,7 @@ func getTriggerRun(cmd *cobra.Command) (*GetTriggerCliOutput, error) {
 		return nil, serviceError
 	}
 	output := &GetTriggerCliOutput{GetTriggerOutput: *response}
-	decorateTriggerOutput(response.TriggerType, &output.triggerCliOutputDecorate)
+	decorateTriggerOutput(response.TriggerType, response.SourceARN, &output.triggerCliOutputDecorate)
 
 	return output, nil
 }
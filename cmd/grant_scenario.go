@@ -0,0 +1,283 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+)
+
+// grantParam is the parameter of the grant scenario, which is prompted in the shell
+// and specified by --param key=value in the command line.
+type grantParam struct {
+	Key    string
+	Prompt string
+}
+
+// grantTarget is the resource to grant the permission to.
+type grantTarget struct {
+	ServiceName  string
+	FunctionName string
+	TriggerName  string
+	RoleName     string
+	PolicyName   string
+	Params       map[string]string
+}
+
+// grantScenario defines how to grant a kind of permission. The resources are templates in which
+// {uid}, {region}, {service}, {function} and the keys of the params are replaced.
+type grantScenario struct {
+	Name        string
+	Description string
+	Params      []grantParam
+	Principal   string
+	Actions     []string
+	Resources   []string
+	// apply updates the service or the trigger to use the role after the policy is bound, optional
+	apply func(client *util.FClient, target grantTarget, roleARN string) error
+}
+
+// registerGrantScenario adds the scenario to the registry, the scenarios are listed
+// in the order they are registered.
+func registerGrantScenario(s *grantScenario) {
+	if findGrantScenario(s.Name) != nil {
+		panic(fmt.Sprintf("grant scenario %s is registered twice", s.Name))
+	}
+	grantScenarios = append(grantScenarios, s)
+}
+
+func findGrantScenario(name string) *grantScenario {
+	for _, s := range grantScenarios {
+		if s.Name == name {
+			return s
+		}
+	}
+	return nil
+}
+
+func grantScenarioNames() []string {
+	names := []string{}
+	for _, s := range grantScenarios {
+		names = append(names, s.Name)
+	}
+	return names
+}
+
+func setServiceRole(client *util.FClient, target grantTarget, roleARN string) error {
+	_, err := client.UpdateService(fc.NewUpdateServiceInput(target.ServiceName).WithRole(roleARN))
+	return err
+}
+
+func setTriggerInvocationRole(client *util.FClient, target grantTarget, roleARN string) error {
+	if target.TriggerName == "" {
+		fmt.Printf("use the role %s as the invocation role of the trigger\n", roleARN)
+		return nil
+	}
+	_, err := client.UpdateTrigger(fc.NewUpdateTriggerInput(
+		target.ServiceName, target.FunctionName, target.TriggerName).WithInvocationRole(roleARN))
+	return err
+}
+
+var invokeFunctionResources = []string{
+	"acs:fc:{region}:{uid}:services/{service}/functions/{function}",
+	"acs:fc:{region}:{uid}:services/{service}.*/functions/{function}",
+}
+
+// grantScenarios is the registry of the grant scenarios, which is built by registerGrantScenario.
+var grantScenarios []*grantScenario
+
+func init() {
+	for _, s := range builtinGrantScenarios {
+		registerGrantScenario(s)
+	}
+}
+
+// builtinGrantScenarios are the grant scenarios shipped with fcli.
+var builtinGrantScenarios = []*grantScenario{
+	{
+		Name:        "logstore",
+		Description: "Allow FC write function logs to your log store.",
+		Params: []grantParam{
+			{Key: "project", Prompt: "Please input the log project: "},
+			{Key: "logstore", Prompt: "Please input the log store: "},
+		},
+		Principal: rolePrincipal,
+		Actions:   []string{"log:PostLogStoreLogs"},
+		Resources: []string{"acs:log:*:{uid}:project/{project}/logstore/{logstore}"},
+		apply: func(client *util.FClient, target grantTarget, roleARN string) error {
+			project, logstore := target.Params["project"], target.Params["logstore"]
+			input := fc.NewUpdateServiceInput(target.ServiceName).
+				WithLogConfig(&fc.LogConfig{
+					Project:  &project,
+					Logstore: &logstore,
+				}).WithRole(roleARN)
+			_, err := client.UpdateService(input)
+			return err
+		},
+	},
+	{
+		Name:        "oss-code",
+		Description: "Allow FC copy code from your OSS location.",
+		Params: []grantParam{
+			{Key: "path", Prompt: "Please input the OSS path (example: your_bucket/your_directory): "},
+		},
+		Principal: rolePrincipal,
+		Actions:   []string{"oss:GetObject"},
+		Resources: []string{"acs:oss:*:{uid}:{path}/*"},
+		apply:     setServiceRole,
+	},
+	{
+		Name:        "vpc",
+		Description: "Allow FC access your VPC by elastic network interfaces.",
+		Principal:   rolePrincipal,
+		Actions:     util.VPCPolicyStatement().Action,
+		Resources:   []string{"*"},
+		apply:       setServiceRole,
+	},
+	{
+		Name:        "nas",
+		Description: "Allow FC access your NAS file system, which is mounted through the VPC.",
+		Principal:   rolePrincipal,
+		Actions:     util.VPCPolicyStatement().Action,
+		Resources:   []string{"*"},
+		apply:       setServiceRole,
+	},
+	{
+		Name:        "oss-read",
+		Description: "Allow your function code read objects from your OSS bucket.",
+		Params: []grantParam{
+			{Key: "bucket", Prompt: "Please input the OSS bucket: "},
+		},
+		Principal: rolePrincipal,
+		Actions:   []string{"oss:GetObject", "oss:ListObjects"},
+		Resources: []string{"acs:oss:*:{uid}:{bucket}", "acs:oss:*:{uid}:{bucket}/*"},
+		apply:     setServiceRole,
+	},
+	{
+		Name:        "oss-write",
+		Description: "Allow your function code write objects to your OSS bucket.",
+		Params: []grantParam{
+			{Key: "bucket", Prompt: "Please input the OSS bucket: "},
+		},
+		Principal: rolePrincipal,
+		Actions:   []string{"oss:PutObject", "oss:DeleteObject"},
+		Resources: []string{"acs:oss:*:{uid}:{bucket}/*"},
+		apply:     setServiceRole,
+	},
+	{
+		Name:        "mns-trigger",
+		Description: "Allow MNS topic trigger invoke your function.",
+		Params: []grantParam{
+			{Key: "function", Prompt: "Please input the function name: "},
+		},
+		Principal: util.TriggerInvocationPrincipals[fc.TRIGGER_TYPE_MNS_TOPIC],
+		Actions:   []string{"fc:InvokeFunction"},
+		Resources: invokeFunctionResources,
+		apply:     setTriggerInvocationRole,
+	},
+	{
+		Name:        "tablestore-trigger",
+		Description: "Allow Tablestore trigger invoke your function.",
+		Params: []grantParam{
+			{Key: "function", Prompt: "Please input the function name: "},
+		},
+		Principal: util.TriggerInvocationPrincipals[util.TriggerTypeTableStore],
+		Actions:   []string{"fc:InvokeFunction"},
+		Resources: invokeFunctionResources,
+		apply:     setTriggerInvocationRole,
+	},
+}
+
+// missingGrantParams returns the keys of the params which are not specified yet.
+func (s *grantScenario) missingGrantParams(params map[string]string) []string {
+	missing := []string{}
+	for _, p := range s.Params {
+		if params[p.Key] == "" {
+			missing = append(missing, p.Key)
+		}
+	}
+	return missing
+}
+
+// promptGrantParams reads the params which are not specified yet from the reader.
+func (s *grantScenario) promptGrantParams(reader *bufio.Reader, params map[string]string) error {
+	for _, p := range s.Params {
+		if params[p.Key] != "" {
+			continue
+		}
+		fmt.Print(p.Prompt)
+		value, err := reader.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		params[p.Key] = strings.TrimSpace(value)
+	}
+	return nil
+}
+
+// statement builds the policy statement of the scenario for the target.
+func (s *grantScenario) statement(uid, region string, target grantTarget) (ram.PolicyStatement, error) {
+	pairs := []string{"{uid}", uid, "{region}", region, "{service}", target.ServiceName}
+	for _, p := range s.Params {
+		value := target.Params[p.Key]
+		if value == "" {
+			return ram.PolicyStatement{}, util.NewValidationError("%s is required by the grant scenario %s", p.Key, s.Name)
+		}
+		pairs = append(pairs, "{"+p.Key+"}", value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+	statement := ram.PolicyStatement{Effect: "Allow", Action: append([]string{}, s.Actions...)}
+	for _, r := range s.Resources {
+		statement.Resource = append(statement.Resource, replacer.Replace(r))
+	}
+	return statement, nil
+}
+
+// grant binds the policy of the scenario to the role and applies the role to the target.
+func (s *grantScenario) grant(ramCli *ram.Client, client *util.FClient, target grantTarget) (string, error) {
+	if target.Params == nil {
+		target.Params = map[string]string{}
+	}
+	if target.FunctionName == "" {
+		target.FunctionName = target.Params["function"]
+	} else if target.Params["function"] == "" {
+		target.Params["function"] = target.FunctionName
+	}
+	uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	region, err := util.GetRegionForEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return "", err
+	}
+	statement, err := s.statement(uid, region, target)
+	if err != nil {
+		return "", err
+	}
+	action, _ := json.Marshal(statement.Action)
+	resource, _ := json.Marshal(statement.Resource)
+	roleARN, err := util.BindRolePolicyIdempotent(
+		ramCli, target.RoleName, s.Principal, target.PolicyName, string(action), string(resource))
+	if err != nil {
+		return roleARN, err
+	}
+	if s.apply == nil {
+		return roleARN, nil
+	}
+	// has latency when attach policy to role, wait to avoid the inconsistent for policy and role.
+	for i := 0; i < 3; i++ {
+		fmt.Print(".")
+		time.Sleep(time.Second)
+	}
+	fmt.Println()
+	if err := s.apply(client, target, roleARN); err != nil {
+		return roleARN, err
+	}
+	return roleARN, nil
+}
@@ -0,0 +1,48 @@
+package cmd
+
+func (s *FunctionStructsTestSuite) TestGrantScenarioStatement() {
+	assert := s.Require()
+	names := map[string]bool{}
+	for _, scenario := range grantScenarios {
+		assert.False(names[scenario.Name], scenario.Name)
+		names[scenario.Name] = true
+	}
+
+	scenario := findGrantScenario("logstore")
+	assert.NotNil(scenario)
+	statement, err := scenario.statement("123", "cn-hangzhou", grantTarget{
+		ServiceName: "svc",
+		Params:      map[string]string{"project": "p", "logstore": "l"},
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"acs:log:*:123:project/p/logstore/l"}, []string(statement.Resource))
+
+	_, err = scenario.statement("123", "cn-hangzhou", grantTarget{ServiceName: "svc", Params: map[string]string{}})
+	assert.NotNil(err)
+
+	statement, err = findGrantScenario("mns-trigger").statement("123", "cn-hangzhou", grantTarget{
+		ServiceName: "svc",
+		Params:      map[string]string{"function": "fn"},
+	})
+	assert.Nil(err)
+	assert.Equal([]string{
+		"acs:fc:cn-hangzhou:123:services/svc/functions/fn",
+		"acs:fc:cn-hangzhou:123:services/svc.*/functions/fn",
+	}, []string(statement.Resource))
+	assert.Nil(findGrantScenario("unknown"))
+
+	params, err := parseGrantParams([]string{"bucket=my-bucket", "path=a=b"})
+	assert.Nil(err)
+	assert.Equal(map[string]string{"bucket": "my-bucket", "path": "a=b"}, params)
+	_, err = parseGrantParams([]string{"bucket"})
+	assert.NotNil(err)
+}
+
+func (s *FunctionStructsTestSuite) TestMissingGrantParams() {
+	assert := s.Require()
+	scenario := findGrantScenario("logstore")
+	assert.Equal([]string{"project", "logstore"}, scenario.missingGrantParams(map[string]string{}))
+	assert.Equal([]string{"logstore"}, scenario.missingGrantParams(map[string]string{"project": "p"}))
+	assert.Empty(scenario.missingGrantParams(map[string]string{"project": "p", "logstore": "l"}))
+	assert.Len(grantScenarios, len(builtinGrantScenarios))
+}
@@ -1,38 +1,92 @@
 package cmd
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
-	"net/http"
 	"os"
 	"reflect"
 	"strings"
 
 	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
 )
 
-var client *fc.Client
-
-func getClient() (*fc.Client, error) {
-	clientTemp, err := fc.NewClient(
-		gConfig.Endpoint,
-		gConfig.APIVersion,
-		gConfig.AccessKeyID,
-		gConfig.AccessKeySecret,
-		fc.WithSecurityToken(gConfig.SecurityToken),
-	)
-	return clientTemp, err
+var client *util.FClient
+
+func getClient() (*util.FClient, error) {
+	return util.NewFClient(gConfig)
+}
+
+// outputFormatAnnotation marks the --output flags of the output formats, other --output flags are the
+// output files, such as the one of function invoke.
+const outputFormatAnnotation = "fcli_output_format"
+
+// errorEnvelope is the json error printed with --output json.
+type errorEnvelope struct {
+	Error struct {
+		Class      util.ErrorClass `json:"class"`
+		Service    string          `json:"service,omitempty"`
+		HTTPStatus int             `json:"httpStatus,omitempty"`
+		Code       string          `json:"code,omitempty"`
+		Message    string          `json:"message"`
+		RequestID  string          `json:"requestId,omitempty"`
+		Hint       string          `json:"hint,omitempty"`
+		Context    string          `json:"context,omitempty"`
+	} `json:"error"`
+	ExitCode int `json:"exitCode"`
+}
+
+// applyOutputFlag validates the global --output flag.
+func applyOutputFlag(cmd *cobra.Command) error {
+	if globalFlagChanged(cmd, "output") && outputFormat != outputFormatText && outputFormat != outputFormatJSON {
+		return util.NewValidationError("invalid output format %s, expect %s or %s",
+			outputFormat, outputFormatText, outputFormatJSON)
+	}
+	return nil
 }
 
-// ResponseError :
-type ResponseError struct {
-	HTTPStatus   int32  `json:"HttpStatus"`
-	RequestID    string `json:"RequestId"`
-	ErrorCode    string `json:"ErrorCode"`
-	ErrorMessage string `json:"ErrorMessage"`
-	ErrorType    string `json:"errorType"`
-	StackTrace   string `json:"stackTrace"`
+// isJSONOutput returns whether the output format of the command is json by --output json.
+func isJSONOutput(cmd *cobra.Command) bool {
+	f := cmd.Flags().Lookup("output")
+	if f == nil {
+		f = cmd.InheritedFlags().Lookup("output")
+	}
+	if f == nil || !f.Changed || f.Value.String() != outputFormatJSON {
+		return false
+	}
+	_, ok := f.Annotations[outputFormatAnnotation]
+	return ok
+}
+
+// printError prints the error of the command to stderr, and returns the exit code of the error.
+func printError(cmd *cobra.Command, err error) int {
+	err = util.WrapError(err)
+	code := util.ExitCode(err)
+	if !isJSONOutput(cmd) {
+		fmt.Fprintf(os.Stderr, "Error: %s\n", err)
+		if hint := util.ErrorHint(err); hint != "" {
+			fmt.Fprintf(os.Stderr, "Hint: %s\n", hint)
+		}
+		return code
+	}
+	envelope := errorEnvelope{ExitCode: code}
+	envelope.Error.Class = util.ClassOf(err)
+	envelope.Error.Message = err.Error()
+	envelope.Error.Hint = util.ErrorHint(err)
+	if e, ok := err.(*util.APIError); ok {
+		envelope.Error.Service = e.Service
+		envelope.Error.HTTPStatus = e.HTTPStatus
+		envelope.Error.Code = e.Code
+		envelope.Error.Message = e.Message
+		envelope.Error.RequestID = e.RequestID
+		envelope.Error.Context = e.Context
+	}
+	data, _ := json.MarshalIndent(envelope, "", "\t")
+	fmt.Fprintln(os.Stderr, string(data))
+	return code
 }
 
 func printStruct(content interface{}) (string, error) {
@@ -48,43 +102,21 @@ func printStruct(content interface{}) (string, error) {
 	return out.String(), nil
 }
 
-// make error msg for user friendly
-func wrapResponseError(err error) error {
-	var errRes ResponseError
-	errJSON := json.Unmarshal([]byte(err.Error()), &errRes)
-	if errJSON != nil {
-		return err
-	}
-	switch errRes.HTTPStatus {
-	case http.StatusBadRequest:
-		errRes.ErrorMessage += ",please check your region of log project, make sure the sls_endpoint " +
-			"in the config match log's region"
-		return fmt.Errorf("%v", errRes.ErrorMessage)
-	case http.StatusForbidden:
-		errRes.ErrorMessage += ",please check your role's policy has the access for these resources"
-		return fmt.Errorf("%v", errRes.ErrorMessage)
-	default:
-		return err
-	}
-}
-
-func prettyPrint(content interface{}, err error) {
+// prettyPrint prints the content of the command, and returns the error for the command to return.
+func prettyPrint(content interface{}, err error) error {
 	if content != nil {
 		typeInp := reflect.TypeOf(content)
 		if typeInp.Kind() == reflect.Struct {
 			result, err := printStruct(content)
 			if err != nil {
-				fmt.Fprintln(os.Stderr, err)
-				return
+				return err
 			}
 			fmt.Fprintln(os.Stdout, result)
 		} else {
 			fmt.Fprintln(os.Stdout, content)
 		}
 	}
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-	}
+	return err
 }
 
 func prepareCommon() error {
@@ -94,11 +126,18 @@ func prepareCommon() error {
 /** TRIGGER DECORATE HELPER **/
 
 type triggerCliOutputDecorate struct {
-	HTTPTriggerURL *string
+	HTTPTriggerURL     *string
+	TableStoreInstance *string
+	TableStoreTable    *string
+	RDSInstance        *string
 }
 
-func decorateTriggerOutput(triggerType *string, output *triggerCliOutputDecorate) {
-	if *triggerType == fc.TRIGGER_TYPE_HTTP {
+func decorateTriggerOutput(triggerType *string, sourceARN *string, output *triggerCliOutputDecorate) {
+	if triggerType == nil {
+		return
+	}
+	switch *triggerType {
+	case fc.TRIGGER_TYPE_HTTP:
 		temp := strings.Join([]string{
 			gConfig.Endpoint,
 			gConfig.APIVersion,
@@ -107,5 +146,44 @@ func decorateTriggerOutput(triggerType *string, output *triggerCliOutputDecorate
 			functionName,
 		}, "/")
 		output.HTTPTriggerURL = &temp
+	case util.TriggerTypeTableStore:
+		// acs:ots:<region>:<account_id>:instance/<instance>/table/<table>
+		if sourceARN == nil {
+			return
+		}
+		resource := strings.Split(*sourceARN, ":")
+		parts := strings.Split(resource[len(resource)-1], "/")
+		if len(parts) == 4 && parts[0] == "instance" && parts[2] == "table" {
+			output.TableStoreInstance = &parts[1]
+			output.TableStoreTable = &parts[3]
+		}
+	case util.TriggerTypeRDS:
+		// acs:rds:<region>:<account_id>:dbinstance/<instance>
+		if sourceARN == nil {
+			return
+		}
+		resource := strings.Split(*sourceARN, ":")
+		parts := strings.Split(resource[len(resource)-1], "/")
+		if len(parts) == 2 && parts[0] == "dbinstance" {
+			output.RDSInstance = &parts[1]
+		}
+	}
+}
+
+// confirm prints the prompt and waits for the user to input y/n.
+func confirm(reader *bufio.Reader, prompt string) (bool, error) {
+	fmt.Printf("%s [y/n]:\n", prompt)
+	for {
+		s, err := reader.ReadString('\n')
+		if err != nil {
+			return false, err
+		}
+		s = strings.TrimSpace(s)
+		if s == "y" || s == "yes" || s == "Y" || s == "YES" {
+			return true, nil
+		} else if s == "n" || s == "no" || s == "N" || s == "NO" {
+			return false, nil
+		}
+		fmt.Printf("Please input y/n:\n")
 	}
 }
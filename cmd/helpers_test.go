@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+)
+
+// capturePrintError returns the exit code and the stderr of printError.
+func (s *FunctionStructsTestSuite) capturePrintError(err error) (int, string) {
+	assert := s.Require()
+	f, tmpErr := ioutil.TempFile("", "fcli-stderr")
+	assert.Nil(tmpErr)
+	defer os.Remove(f.Name())
+	stderr := os.Stderr
+	os.Stderr = f
+	code := printError(getServiceCmd, err)
+	os.Stderr = stderr
+	f.Close()
+	data, readErr := ioutil.ReadFile(f.Name())
+	assert.Nil(readErr)
+	return code, string(data)
+}
+
+func (s *FunctionStructsTestSuite) TestPrintError() {
+	assert := s.Require()
+	output := RootCmd.PersistentFlags().Lookup("output")
+	defer func() {
+		output.Value.Set(outputFormatText)
+		output.Changed = false
+	}()
+	notFound := &fc.ServiceError{HTTPStatus: 404, ErrorCode: "ServiceNotFound",
+		ErrorMessage: "service 'demo' does not exist", RequestID: "req-1"}
+
+	code, stderr := s.capturePrintError(notFound)
+	assert.Equal(util.ExitCodeNotFound, code)
+	assert.Equal("Error: ServiceNotFound: service 'demo' does not exist (request id: req-1)\n", stderr)
+
+	code, stderr = s.capturePrintError(util.NewValidationError("bad flag").WithHint("see --help"))
+	assert.Equal(util.ExitCodeValidation, code)
+	assert.Equal("Error: bad flag\nHint: see --help\n", stderr)
+
+	RootCmd.PersistentFlags().Set("output", outputFormatJSON)
+	assert.True(isJSONOutput(getServiceCmd))
+	assert.False(isJSONOutput(invokeFuncCmd))
+	code, stderr = s.capturePrintError(&fc.ServiceError{HTTPStatus: 403, ErrorCode: "AccessDenied",
+		ErrorMessage: "denied", RequestID: "req-2"})
+	assert.Equal(util.ExitCodeAuth, code)
+	envelope := errorEnvelope{}
+	assert.Nil(json.Unmarshal([]byte(stderr), &envelope))
+	assert.Equal(util.ErrorClassAuth, envelope.Error.Class)
+	assert.Equal("fc", envelope.Error.Service)
+	assert.Equal(403, envelope.Error.HTTPStatus)
+	assert.Equal("AccessDenied", envelope.Error.Code)
+	assert.Equal("denied", envelope.Error.Message)
+	assert.Equal("req-2", envelope.Error.RequestID)
+	assert.NotEmpty(envelope.Error.Hint)
+	assert.Equal(util.ExitCodeAuth, envelope.ExitCode)
+
+	RootCmd.PersistentFlags().Set("output", "yaml")
+	assert.NotNil(applyOutputFlag(getServiceCmd))
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"reflect"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// the flags of the create and update commands to read the whole fc input from json
+const (
+	inputJSONFlag        = "input-json"
+	generateSkeletonFlag = "generate-skeleton"
+)
+
+// inputJSONStdin is where --input-json - reads from.
+var inputJSONStdin io.Reader = os.Stdin
+
+// addInputJSONFlags adds --input-json and --generate-skeleton to the create or update command.
+func addInputJSONFlags(cmd *cobra.Command) {
+	cmd.Flags().String(inputJSONFlag, "",
+		"read the input from the json file, or stdin if it is -, the flags override the fields of it. "+
+			"Run with --generate-skeleton for a template of the input")
+	cmd.Flags().Bool(generateSkeletonFlag, false, "print a json template of the input for --input-json and exit")
+}
+
+// isGeneratingSkeleton returns whether the command only prints the json template of its input,
+// which does not need the config.
+func isGeneratingSkeleton(cmd *cobra.Command) bool {
+	flag := cmd.Flags().Lookup(generateSkeletonFlag)
+	return flag != nil && flag.Changed && flag.Value.String() == "true"
+}
+
+// readInputJSON unmarshals the json of --input-json into the fc input, it does nothing if the flag is not set.
+func readInputJSON(cmd *cobra.Command, input interface{}) error {
+	if !cmd.Flags().Changed(inputJSONFlag) {
+		return nil
+	}
+	path, _ := cmd.Flags().GetString(inputJSONFlag)
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = ioutil.ReadAll(inputJSONStdin)
+	} else {
+		data, err = ioutil.ReadFile(path)
+	}
+	if err != nil {
+		return util.NewValidationError("can not read --%s %s: %v", inputJSONFlag, path, err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(input); err != nil {
+		return util.NewValidationError("invalid --%s %s: %v", inputJSONFlag, path, err).
+			WithHint(fmt.Sprintf("run '%s --%s' for a template of the input", cmd.CommandPath(), generateSkeletonFlag))
+	}
+	return nil
+}
+
+// useFlag returns whether the value of the flag should be set to the input. The flags override the
+// fields of --input-json only if they are set, otherwise their defaults are used as before.
+func useFlag(cmd *cobra.Command, name string) bool {
+	return cmd.Flags().Changed(name) || !cmd.Flags().Changed(inputJSONFlag)
+}
+
+// printSkeleton prints the json template of the input, whose fields are all filled with the zero values.
+func printSkeleton(input interface{}) error {
+	b, err := json.MarshalIndent(skeleton(reflect.TypeOf(input)).Interface(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(string(b))
+	return nil
+}
+
+// skeleton returns the value of the type with all the pointers, slices and maps filled,
+// so that every field shows up in the json.
+func skeleton(t reflect.Type) reflect.Value {
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Ptr:
+		v.Set(skeleton(t.Elem()).Addr())
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if v.Field(i).CanSet() {
+				v.Field(i).Set(skeleton(t.Field(i).Type))
+			}
+		}
+	case reflect.Slice:
+		// []byte is a base64 string in json
+		if t.Elem().Kind() != reflect.Uint8 {
+			v.Set(reflect.Append(v, skeleton(t.Elem())))
+		}
+	case reflect.Map:
+		key := skeleton(t.Key())
+		if key.Kind() == reflect.String {
+			key.SetString("key")
+		}
+		v.Set(reflect.MakeMap(t))
+		v.SetMapIndex(key, skeleton(t.Elem()))
+	case reflect.Interface:
+		// such as the trigger config, which depends on the trigger type
+		if t.NumMethod() == 0 {
+			v.Set(reflect.ValueOf(map[string]interface{}{}))
+		}
+	}
+	return v
+}
+
+// stringValue returns the string of the field of the fc input, or empty if it is not set.
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+func (s *FunctionStructsTestSuite) TestReadInputJSON() {
+	assert := s.Require()
+	cmd := &cobra.Command{Use: "create"}
+	description := cmd.Flags().String("description", "default", "")
+	addInputJSONFlags(cmd)
+
+	// the defaults of the flags are used without --input-json
+	input := &fc.CreateServiceInput{}
+	assert.Nil(readInputJSON(cmd, input))
+	assert.Nil(input.ServiceName)
+	assert.True(useFlag(cmd, "description"))
+
+	f, err := ioutil.TempFile("", "fcli-input")
+	assert.Nil(err)
+	defer os.Remove(f.Name())
+	f.WriteString(`{"serviceName": "demo", "description": "from json",
+		"nasConfig": {"userId": 10003, "mountPoints": [{"serverAddr": "nas:/", "mountDir": "/mnt"}]}}`)
+	f.Close()
+
+	cmd.Flags().Set(inputJSONFlag, f.Name())
+	assert.Nil(readInputJSON(cmd, input))
+	assert.Equal("demo", *input.ServiceName)
+	assert.Equal(int32(10003), *input.NASConfig.UserID)
+	assert.Equal("/mnt", input.NASConfig.MountPoints[0].MountDir)
+	assert.False(useFlag(cmd, "description"))
+	cmd.Flags().Set("description", "from flag")
+	assert.True(useFlag(cmd, "description"))
+	assert.Equal("from flag", *description)
+
+	stdin := inputJSONStdin
+	defer func() { inputJSONStdin = stdin }()
+	inputJSONStdin = strings.NewReader(`{"versionId": "2", "IfMatch": "etag"}`)
+	cmd.Flags().Set(inputJSONFlag, "-")
+	alias := &fc.UpdateAliasInput{}
+	assert.Nil(readInputJSON(cmd, alias))
+	assert.Equal("2", *alias.VersionID)
+	assert.Equal("etag", *alias.IfMatch)
+
+	inputJSONStdin = strings.NewReader(`{"serviceName": "demo", "unknown": 1}`)
+	err = readInputJSON(cmd, &fc.CreateServiceInput{})
+	assert.Equal(util.ErrorClassValidation, util.ClassOf(err))
+	assert.Contains(err.Error(), "unknown")
+	assert.Contains(util.ErrorHint(err), "--generate-skeleton")
+}
+
+func (s *FunctionStructsTestSuite) TestSkeleton() {
+	assert := s.Require()
+	input := skeleton(reflect.TypeOf(fc.CreateFunctionInput{})).Interface().(fc.CreateFunctionInput)
+	assert.NotNil(input.ServiceName)
+	assert.NotNil(input.FunctionName)
+	assert.NotNil(input.Code)
+	assert.NotNil(input.CustomContainerConfig)
+	assert.Len(input.EnvironmentVariables, 1)
+
+	service := skeleton(reflect.TypeOf(fc.CreateServiceInput{})).Interface().(fc.CreateServiceInput)
+	assert.Len(service.VPCConfig.VSwitchIDs, 1)
+	assert.Len(service.NASConfig.MountPoints, 1)
+
+	trigger := skeleton(reflect.TypeOf(fc.CreateTriggerInput{})).Interface().(fc.CreateTriggerInput)
+	assert.Equal(map[string]interface{}{}, trigger.TriggerConfig)
+}
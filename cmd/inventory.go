@@ -0,0 +1,235 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// the formats of the inventory report
+const (
+	inventoryFormatJSON     = "json"
+	inventoryFormatMarkdown = "markdown"
+)
+
+var inventoryInput struct {
+	regions *regionsInput
+	format  *string
+	file    *string
+}
+
+type inventoryRegion struct {
+	Region   string             `json:"Region"`
+	Error    string             `json:"Error,omitempty"`
+	Services []inventoryService `json:"Services"`
+}
+
+type inventoryService struct {
+	ServiceName string              `json:"ServiceName"`
+	Description string              `json:"Description,omitempty"`
+	Role        string              `json:"Role,omitempty"`
+	Functions   []inventoryFunction `json:"Functions"`
+	Aliases     []inventoryAlias    `json:"Aliases"`
+}
+
+type inventoryFunction struct {
+	FunctionName string             `json:"FunctionName"`
+	Runtime      string             `json:"Runtime"`
+	Handler      string             `json:"Handler"`
+	MemorySize   int32              `json:"MemorySize"`
+	Timeout      int32              `json:"Timeout"`
+	Triggers     []inventoryTrigger `json:"Triggers"`
+}
+
+type inventoryTrigger struct {
+	TriggerName string `json:"TriggerName"`
+	TriggerType string `json:"TriggerType"`
+	Qualifier   string `json:"Qualifier,omitempty"`
+}
+
+type inventoryAlias struct {
+	AliasName string `json:"AliasName"`
+	VersionID string `json:"VersionId"`
+}
+
+func init() {
+	RootCmd.AddCommand(inventoryCmd)
+	inventoryCmd.Flags().Bool("help", false, "report the services, functions, triggers and aliases of all the regions")
+	inventoryInput.regions = &regionsInput{
+		all: new(bool),
+		regions: inventoryCmd.Flags().StringSlice(
+			"regions", nil, "the regions to report, all the regions by default"),
+	}
+	inventoryInput.format = inventoryCmd.Flags().StringP("output", "o", inventoryFormatJSON, "output format, json or markdown")
+	inventoryCmd.Flags().SetAnnotation("output", outputFormatAnnotation, []string{"true"})
+	inventoryInput.file = inventoryCmd.Flags().String("file", "", "write the report to the file instead of stdout")
+}
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "report the services, functions, triggers and aliases of all the regions",
+	Long: `
+report the services -> functions -> triggers and the aliases of the account in all the regions,
+which are listed concurrently. The failed regions are reported with the error.
+EXAMPLE:
+fcli inventory
+fcli inventory --regions cn-hangzhou,cn-shanghai -o markdown --file inventory.md
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		report, err := inventoryRun()
+		if err != nil {
+			return err
+		}
+		if *inventoryInput.file == "" {
+			fmt.Print(report)
+			return nil
+		}
+		if err := ioutil.WriteFile(*inventoryInput.file, []byte(report), 0644); err != nil {
+			return fmt.Errorf("Failed to write file: %s. Error: %v", *inventoryInput.file, err)
+		}
+		return nil
+	},
+}
+
+func inventoryRun() (string, error) {
+	format := *inventoryInput.format
+	if format != inventoryFormatJSON && format != inventoryFormatMarkdown {
+		return "", util.NewValidationError("unsupported output format %q, expect json or markdown", format)
+	}
+	regions, err := inventoryInput.regions.selected()
+	if err != nil {
+		return "", err
+	}
+	if len(regions) == 0 {
+		regions = util.GetRegions()
+	}
+
+	results, failures := forEachRegion(regions, func(client *util.FClient, region string) (interface{}, error) {
+		return regionInventory(client)
+	})
+	report := []inventoryRegion{}
+	for i, region := range regions {
+		r := inventoryRegion{Region: region, Services: []inventoryService{}}
+		if services, ok := results[i].([]inventoryService); ok {
+			r.Services = services
+		}
+		for _, f := range failures {
+			if f.Region == region {
+				r.Error = f.Error
+			}
+		}
+		report = append(report, r)
+	}
+
+	if format == inventoryFormatMarkdown {
+		return inventoryMarkdown(report), nil
+	}
+	b, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b) + "\n", nil
+}
+
+// regionInventory lists the services of the region with their functions, triggers and aliases.
+// The items are decoded from the fc responses, whose keys match the fields case-insensitively.
+func regionInventory(client *util.FClient) ([]inventoryService, error) {
+	items, err := listAllServices(client, "")
+	if err != nil {
+		return nil, err
+	}
+	services := []inventoryService{}
+	if err := convertItems(items, &services); err != nil {
+		return nil, err
+	}
+	for i := range services {
+		s := &services[i]
+		s.Functions, s.Aliases = []inventoryFunction{}, []inventoryAlias{}
+		items, err := listAllFunctions(client, s.ServiceName, "", "")
+		if err != nil {
+			return nil, err
+		}
+		if err := convertItems(items, &s.Functions); err != nil {
+			return nil, err
+		}
+		for j := range s.Functions {
+			f := &s.Functions[j]
+			f.Triggers = []inventoryTrigger{}
+			items, err := listAllTriggers(client, s.ServiceName, f.FunctionName, "")
+			if err != nil {
+				return nil, err
+			}
+			if err := convertItems(items, &f.Triggers); err != nil {
+				return nil, err
+			}
+		}
+		items, err = listAllAliases(client, s.ServiceName, "")
+		if err != nil {
+			return nil, err
+		}
+		if err := convertItems(items, &s.Aliases); err != nil {
+			return nil, err
+		}
+	}
+	return services, nil
+}
+
+// convertItems converts the items of the fc responses to v through json.
+func convertItems(items []interface{}, v interface{}) error {
+	b, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+func inventoryMarkdown(report []inventoryRegion) string {
+	var b bytes.Buffer
+	b.WriteString("# Function Compute Inventory\n")
+	for _, r := range report {
+		fmt.Fprintf(&b, "\n## %s\n\n", r.Region)
+		if r.Error != "" {
+			fmt.Fprintf(&b, "Failed to list the region: %s\n", r.Error)
+			continue
+		}
+		if len(r.Services) == 0 {
+			b.WriteString("No services.\n")
+			continue
+		}
+		for _, s := range r.Services {
+			fmt.Fprintf(&b, "### %s\n\n", s.ServiceName)
+			if s.Description != "" {
+				fmt.Fprintf(&b, "%s\n\n", s.Description)
+			}
+			if s.Role != "" {
+				fmt.Fprintf(&b, "Role: `%s`\n\n", s.Role)
+			}
+			if len(s.Functions) > 0 {
+				b.WriteString("| Function | Runtime | Handler | Memory (MB) | Timeout (s) | Triggers |\n")
+				b.WriteString("| --- | --- | --- | --- | --- | --- |\n")
+				for _, f := range s.Functions {
+					triggers := []string{}
+					for _, t := range f.Triggers {
+						triggers = append(triggers, fmt.Sprintf("%s (%s)", t.TriggerName, t.TriggerType))
+					}
+					fmt.Fprintf(&b, "| %s | %s | %s | %d | %d | %s |\n", f.FunctionName, f.Runtime, f.Handler,
+						f.MemorySize, f.Timeout, strings.Join(triggers, ", "))
+				}
+				b.WriteString("\n")
+			}
+			if len(s.Aliases) > 0 {
+				b.WriteString("Aliases:\n\n")
+				for _, a := range s.Aliases {
+					fmt.Fprintf(&b, "- %s -> version %s\n", a.AliasName, a.VersionID)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestRegionsInput() {
+	assert := s.Require()
+	all, regions := false, []string{}
+	input := &regionsInput{all: &all, regions: &regions}
+	selected, err := input.selected()
+	assert.Nil(err)
+	assert.Empty(selected)
+
+	regions = []string{"cn-hangzhou", "cn-nowhere"}
+	_, err = input.selected()
+	assert.NotNil(err)
+
+	all = true
+	selected, err = input.selected()
+	assert.Nil(err)
+	assert.Contains(selected, "ap-southeast-1")
+}
+
+func (s *FunctionStructsTestSuite) TestForEachRegion() {
+	assert := s.Require()
+	saved := gConfig
+	defer func() { gConfig = saved }()
+	gConfig = util.NewGlobalConfig()
+	gConfig.Endpoint = "https://1.cn-hangzhou.fc.aliyuncs.com"
+	gConfig.AccessKeyID, gConfig.AccessKeySecret = "id", "secret"
+
+	// run with -race, the goroutines of all the regions share the credential provider of gConfig
+	regions := util.GetRegions()
+	results, failures := forEachRegion(regions, func(client *util.FClient, region string) (interface{}, error) {
+		if region == "cn-beijing" {
+			return nil, fmt.Errorf("failed in %s", region)
+		}
+		return client.Endpoint(), nil
+	})
+	for i, region := range regions {
+		if region == "cn-beijing" {
+			assert.Nil(results[i])
+		} else {
+			assert.Equal("https://1."+region+".fc.aliyuncs.com", results[i])
+		}
+	}
+	assert.Equal([]regionError{{Region: "cn-beijing", Error: "failed in cn-beijing"}}, failures)
+	assert.Equal("https://1.cn-hangzhou.fc.aliyuncs.com", gConfig.Endpoint)
+}
+
+func (s *FunctionStructsTestSuite) TestNameOnlyItems() {
+	assert := s.Require()
+	name := "svc"
+	items := []interface{}{&struct {
+		ServiceName *string `json:"serviceName"`
+		Description string  `json:"description"`
+	}{&name, "desc"}}
+	names, err := nameOnlyItems(items, "ServiceName")
+	assert.Nil(err)
+	assert.Equal(map[string]interface{}{"ServiceName": "svc"}, names[0])
+	assert.Equal(map[string]interface{}{"ServiceName": "svc", "Region": "cn-hangzhou"},
+		withRegion("cn-hangzhou", names[0]))
+}
+
+func (s *FunctionStructsTestSuite) TestInventoryMarkdown() {
+	assert := s.Require()
+	services := []inventoryService{}
+	err := convertItems([]interface{}{map[string]interface{}{"serviceName": "svc", "role": "acs:ram::1:role/r"}}, &services)
+	assert.Nil(err)
+	services[0].Functions = []inventoryFunction{{
+		FunctionName: "fn", Runtime: "python3", Handler: "index.handler", MemorySize: 128, Timeout: 60,
+		Triggers: []inventoryTrigger{{TriggerName: "t", TriggerType: "oss"}},
+	}}
+	services[0].Aliases = []inventoryAlias{{AliasName: "prod", VersionID: "1"}}
+
+	md := inventoryMarkdown([]inventoryRegion{
+		{Region: "cn-hangzhou", Services: services},
+		{Region: "cn-shanghai", Services: []inventoryService{}},
+		{Region: "ap-southeast-1", Error: "timeout"},
+	})
+	assert.Contains(md, "## cn-hangzhou\n\n### svc\n\nRole: `acs:ram::1:role/r`")
+	assert.Contains(md, "| fn | python3 | index.handler | 128 | 60 | t (oss) |")
+	assert.Contains(md, "- prod -> version 1")
+	assert.Contains(md, "## cn-shanghai\n\nNo services.")
+	assert.Contains(md, "Failed to list the region: timeout")
+}
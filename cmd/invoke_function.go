@@ -4,7 +4,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
-	"os"
 
 	"github.com/spf13/cobra"
 
@@ -46,11 +45,10 @@ fcli function invoke -s "service_name" -f "function_name"
 		    --event-file "event file"
 		    --event-str  "event_string"
 	            --qualifier  "LATEST"`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		resp, err := invokeFuncRun()
 		if err != nil {
-			fmt.Fprintln(os.Stderr, err)
-			return
+			return err
 		}
 
 		var output []byte
@@ -69,12 +67,12 @@ fcli function invoke -s "service_name" -f "function_name"
 
 		if invocationOutputFile == "" {
 			fmt.Println(string(output))
-			return
+			return nil
 		}
-		err = ioutil.WriteFile(invocationOutputFile, output, 0644)
-		if err != nil {
-			fmt.Fprintf(os.Stderr, "output error:%s\n", err)
+		if err := ioutil.WriteFile(invocationOutputFile, output, 0644); err != nil {
+			return fmt.Errorf("output error: %v", err)
 		}
+		return nil
 	},
 }
 
@@ -24,9 +24,11 @@ func init() {
 		"next-token", "t", "", "continue listing the aliases from the previous point, optional")
 	listAliasesInput.Limit = listAliasesCmd.Flags().Int32P(
 		"limit", "l", 100, "the max number of the returned aliases, optional")
+	listAliasesRegions = addRegionsFlags(listAliasesCmd)
 }
 
 var listAliasesInput fc.ListAliasesInput
+var listAliasesRegions *regionsInput
 
 var listAliasesCmd = &cobra.Command{
 	Use:     "list [option]",
@@ -40,18 +42,29 @@ fcli alias list -s(--service-name)       service_name
 			-k(--start-key)  "list alias start key"
 			-n(--next-token) "next token"
 			-l(--limit)      100
+			--all-regions    false
+			--regions        cn-hangzhou,cn-shanghai
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		regions, err := listAliasesRegions.selected()
+		if err != nil {
+			return err
+		}
+		if len(regions) > 0 {
+			return prettyPrint(printStruct(listInRegions(regions, "Aliases", func(client *util.FClient) ([]interface{}, error) {
+				return listAllAliases(client, *listAliasesInput.ServiceName, *listAliasesInput.Prefix)
+			})))
+		}
+
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		resp, err := client.ListAliases(&listAliasesInput)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		} else {
-			fmt.Println(resp)
+			return err
 		}
+		fmt.Println(resp)
+		return nil
 	},
 }
@@ -30,6 +30,7 @@ func init() {
 		"name-only", true, "display function name only")
 	listFuncInput.qualifier = listFuncCmd.Flags().StringP(
 		"qualifier", "q", "", "service version or alias, optional")
+	listFuncInput.regions = addRegionsFlags(listFuncCmd)
 }
 
 type listFuncInputType struct {
@@ -40,6 +41,7 @@ type listFuncInputType struct {
 	limit       *int32
 	nameOnly    *bool
 	qualifier   *string
+	regions     *regionsInput
 }
 
 var listFuncInput listFuncInputType
@@ -48,13 +50,37 @@ var listFuncCmd = &cobra.Command{
 	Use:     "list [option]",
 	Aliases: []string{"l"},
 	Short:   "List functions belong to the specified service",
-	Long:    ``,
+	Long: `
+List functions belong to the specified service.
+With --all-regions or --regions, the functions of the service are listed in each region concurrently
+and merged with the Region column, the regions which fail are reported in Errors.
+EXAMPLE:
+fcli function list -s(--service-name) service_name
+fcli function list -s(--service-name) service_name --all-regions
+			`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		regions, err := listFuncInput.regions.selected()
+		if err != nil {
+			return err
+		}
+		if len(regions) > 0 {
+			output := listInRegions(regions, "Functions", func(client *util.FClient) ([]interface{}, error) {
+				items, err := listAllFunctions(
+					client, *listFuncInput.serviceName, *listFuncInput.prefix, *listFuncInput.qualifier)
+				if err != nil || !*listFuncInput.nameOnly {
+					return items, err
+				}
+				return nameOnlyItems(items, "FunctionName")
+			})
+			ret, _ := json.MarshalIndent(output, "", "  ")
+			fmt.Printf("%s\n", string(ret))
+			return nil
+		}
 
-	Run: func(cmd *cobra.Command, args []string) {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 
 		input := fc.NewListFunctionsInput(*listFuncInput.serviceName).
@@ -66,8 +92,7 @@ var listFuncCmd = &cobra.Command{
 
 		resp, err := client.ListFunctions(input)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-			return
+			return err
 		}
 
 		if *listFuncInput.nameOnly {
@@ -87,5 +112,6 @@ var listFuncCmd = &cobra.Command{
 			ret, _ := json.MarshalIndent(resp, "", "  ")
 			fmt.Printf("%s\n", string(ret))
 		}
+		return nil
 	},
 }
@@ -19,20 +19,21 @@ var listLogProjectCmd = &cobra.Command{
 	Aliases: []string{"l"},
 	Short:   "List log projects belong to the configured account",
 	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		err := getListLogProject(cmd)
-		if err != nil {
-			fmt.Println(err)
-		}
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return getListLogProject(cmd)
 	},
 }
 
 func getListLogProject(cmd *cobra.Command) error {
+	cred, err := gConfig.Credentials()
+	if err != nil {
+		return err
+	}
 	slsClient := sls.CreateNormalInterface(
 		gConfig.SLSEndpoint,
-		gConfig.AccessKeyID,
-		gConfig.AccessKeySecret,
-		gConfig.SecurityToken,
+		cred.AccessKeyID,
+		cred.AccessKeySecret,
+		cred.SecurityToken,
 	)
 
 	projectNameList, err := slsClient.ListProject()
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/fcli/util"
 	"github.com/spf13/cobra"
 )
 
@@ -26,42 +27,43 @@ var listLogStoreCmd = &cobra.Command{
 	Aliases: []string{"l"},
 	Short:   "SLS store related operations",
 	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
-
+	RunE: func(cmd *cobra.Command, args []string) error {
 		// Check for valid ProjectName because SLS SDK error is not very good
 		if *listLogStoreInput.logProjectName == "" {
-			fmt.Printf("Error: Project name is required but not provided\n")
-			return
+			return util.NewValidationError("Project name is required but not provided")
 		}
 
+		cred, err := gConfig.Credentials()
+		if err != nil {
+			return err
+		}
 		slsProject, err := sls.NewLogProject(
 			*listLogStoreInput.logProjectName,
 			gConfig.SLSEndpoint,
-			gConfig.AccessKeyID,
-			gConfig.AccessKeySecret,
+			cred.AccessKeyID,
+			cred.AccessKeySecret,
 		)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-			return
+			return err
 		}
 
-		// To use Security Token if available in global config
-		if gConfig.SecurityToken != "" {
+		// To use Security Token if available in the credentials
+		if cred.SecurityToken != "" {
 			fmt.Printf("Using SecurityToken instead of AccessKey.\n")
-			slsProject.WithToken(gConfig.SecurityToken)
+			slsProject.WithToken(cred.SecurityToken)
 		}
 
 		// Call Api to get List of names
 		storeNameList, err := slsProject.ListLogStore()
 		if err != nil {
-			fmt.Printf("Error getting list of Stores: %s\n", err)
-			return
+			return err
 		}
 
 		// Print out list of names
 		for _, storeName := range storeNameList {
 			fmt.Printf("%s\n", storeName)
 		}
+		return nil
 	},
 }
 
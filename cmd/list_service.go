@@ -25,6 +25,7 @@ func init() {
 		"limit", "l", 100, "the max number of the returned services")
 	listServiceInput.nameOnly = listServiceCmd.Flags().Bool(
 		"name-only", true, "display service name only")
+	listServiceInput.regions = addRegionsFlags(listServiceCmd)
 }
 
 type listServiceInputType struct {
@@ -33,6 +34,7 @@ type listServiceInputType struct {
 	nextToken *string
 	limit     *int32
 	nameOnly  *bool
+	regions   *regionsInput
 }
 
 var listServiceInput listServiceInputType
@@ -41,13 +43,37 @@ var listServiceCmd = &cobra.Command{
 	Use:     "list [option]",
 	Aliases: []string{"l"},
 	Short:   "List services of the current account",
-	Long:    ``,
+	Long: `
+List services of the current account.
+With --all-regions or --regions, all the pages are listed in each region concurrently and merged
+with the Region column, the regions which fail are reported in Errors.
+EXAMPLE:
+fcli service list
+fcli service list --regions cn-hangzhou,cn-shanghai,ap-southeast-1
+fcli service list --all-regions --name-only=false
+			`,
+
+	RunE: func(cmd *cobra.Command, args []string) error {
+		regions, err := listServiceInput.regions.selected()
+		if err != nil {
+			return err
+		}
+		if len(regions) > 0 {
+			output := listInRegions(regions, "Services", func(client *util.FClient) ([]interface{}, error) {
+				items, err := listAllServices(client, *listServiceInput.prefix)
+				if err != nil || !*listServiceInput.nameOnly {
+					return items, err
+				}
+				return nameOnlyItems(items, "ServiceName")
+			})
+			ret, _ := json.MarshalIndent(output, "", "  ")
+			fmt.Printf("%s\n", string(ret))
+			return nil
+		}
 
-	Run: func(cmd *cobra.Command, args []string) {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 
 		input := fc.NewListServicesInput().
@@ -58,8 +84,7 @@ var listServiceCmd = &cobra.Command{
 
 		resp, err := client.ListServices(input)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-			return
+			return err
 		}
 
 		if *listServiceInput.nameOnly {
@@ -79,5 +104,6 @@ var listServiceCmd = &cobra.Command{
 			ret, _ := json.MarshalIndent(resp, "", "  ")
 			fmt.Printf("%s\n", string(ret))
 		}
+		return nil
 	},
 }
@@ -20,6 +20,7 @@ type QueryParam struct {
 }
 
 var triggerQueryParam QueryParam
+var listTriggerRegions *regionsInput
 var isShowAll bool
 var onlyNames bool
 
@@ -39,9 +40,11 @@ fcli trigger list -s(--service-name)  service_name
 				 -l(--limit) 100
 				 --all false
 				 --only-names false
+				 --all-regions false
+				 --regions cn-hangzhou,cn-shanghai
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		prettyPrint(listTriggerRun(cmd))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(listTriggerRun(cmd))
 	},
 }
 
@@ -50,6 +53,21 @@ func listTriggerRun(cmd *cobra.Command) (interface{}, error) {
 	if err != nil {
 		return nil, err
 	}
+	regions, err := listTriggerRegions.selected()
+	if err != nil {
+		return nil, err
+	}
+	if len(regions) > 0 {
+		output := listInRegions(regions, "Triggers", func(client *util.FClient) ([]interface{}, error) {
+			items, err := listAllTriggers(client, serviceName, functionName, *triggerQueryParam.prefix)
+			if err != nil || !onlyNames {
+				return items, err
+			}
+			return nameOnlyItems(items, "TriggerName")
+		})
+		return printStruct(output)
+	}
+
 	client, err := util.NewFClient(gConfig)
 	if err != nil {
 		return nil, err
@@ -107,4 +125,5 @@ func init() {
 	triggerQueryParam.limit = listTriggerCmd.Flags().Int32P("limit", "l", 100, "limit number")
 	listTriggerCmd.Flags().BoolVar(&isShowAll, "all", false, "get all the trigger list")
 	listTriggerCmd.Flags().BoolVar(&onlyNames, "only-names", false, "get all the trigger list but only show names")
+	listTriggerRegions = addRegionsFlags(listTriggerCmd)
 }
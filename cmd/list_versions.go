@@ -42,18 +42,17 @@ fcli service version list -s(--service-name)      service_name
 				 -l(--limit)      100
 				 -d(--direction)  BACKWARD
 				`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		*listServiceVersionsInput.Direction = strings.ToUpper(*listServiceVersionsInput.Direction)
 		resp, err := client.ListServiceVersions(&listServiceVersionsInput)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		} else {
-			fmt.Println(resp)
+			return err
 		}
+		fmt.Println(resp)
+		return nil
 	},
 }
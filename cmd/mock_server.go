@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/aliyun/fcli/mockserver"
+	"github.com/spf13/cobra"
+)
+
+var mockServerInput struct {
+	port          *int
+	host          *string
+	dataDir       *string
+	invokeCommand *string
+	verbose       *bool
+}
+
+func init() {
+	RootCmd.AddCommand(mockServerCmd)
+
+	mockServerCmd.Flags().Bool("help", false, "run a fake function compute api server")
+	mockServerInput.port = mockServerCmd.Flags().IntP("port", "p", 9000, "the port to listen on")
+	mockServerInput.host = mockServerCmd.Flags().String("host", "127.0.0.1", "the host to listen on")
+	mockServerInput.dataDir = mockServerCmd.Flags().String(
+		"data-dir", "", "the directory to keep the state on disk, the state is in memory by default")
+	mockServerInput.invokeCommand = mockServerCmd.Flags().String(
+		"invoke-command", "", "the shell command to handle the invocations, which reads the event from stdin "+
+			"and writes the payload to stdout, the event is echoed by default")
+	mockServerInput.verbose = mockServerCmd.Flags().BoolP("verbose", "v", false, "log the requests")
+}
+
+var mockServerCmd = &cobra.Command{
+	Use:   "mock-server",
+	Short: "run a fake function compute api server",
+	Long: `
+run a fake function compute api server for the tests, which serves the services, functions, triggers,
+versions, aliases and invocations. Point the endpoint to it to run the commands without the cloud:
+fcli service list --endpoint http://127.0.0.1:9000
+
+The invocations echo the event unless --invoke-command is set, the command gets the environment
+variables FC_SERVICE_NAME, FC_FUNCTION_NAME, FC_QUALIFIER, FC_FUNCTION_HANDLER and the ones of the function.
+EXAMPLE:
+fcli mock-server --port 9000
+fcli mock-server --port 9000 --data-dir ./fc-state --invoke-command 'cat; echo " handled" >&2'
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return mockServerRun()
+	},
+}
+
+func mockServerRun() error {
+	if *mockServerInput.dataDir != "" {
+		if err := os.MkdirAll(*mockServerInput.dataDir, 0755); err != nil {
+			return err
+		}
+	}
+	store, err := mockserver.NewStore(*mockServerInput.dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to load the state: %v", err)
+	}
+	server := mockserver.New(store)
+	if *mockServerInput.invokeCommand != "" {
+		server.DefaultHandler = mockserver.CommandHandler(*mockServerInput.invokeCommand)
+	}
+	if *mockServerInput.verbose {
+		server.Logf = log.Printf
+	}
+	addr := net.JoinHostPort(*mockServerInput.host, strconv.Itoa(*mockServerInput.port))
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("fake function compute api server is listening on http://%s\n", listener.Addr())
+	return http.Serve(listener, server)
+}
@@ -0,0 +1,227 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+// pausedTriggersFile is the local state file which records the triggers disabled by "fcli trigger pause"
+const pausedTriggersFile = "paused_triggers.yaml"
+
+// pausedTrigger is a trigger disabled by "fcli trigger pause".
+type pausedTrigger struct {
+	FunctionName string `yaml:"function_name"`
+	TriggerName  string `yaml:"trigger_name"`
+	TriggerType  string `yaml:"trigger_type"`
+}
+
+// pausedTriggersState maps "<endpoint>/<service>" to the triggers paused in the service.
+type pausedTriggersState map[string][]pausedTrigger
+
+var pauseTriggerInput struct {
+	allFunctions *bool
+	triggerType  *string
+}
+
+func init() {
+	triggerCmd.AddCommand(pauseTriggerCmd)
+	pauseTriggerCmd.Flags().Bool("help", false, "pause triggers")
+	pauseTriggerInput.allFunctions = pauseTriggerCmd.Flags().Bool(
+		"all-functions", false, "pause the triggers of all the functions in the service")
+	pauseTriggerInput.triggerType = pauseTriggerCmd.Flags().String(
+		"type", fc.TRIGGER_TYPE_TIMER, "type of the triggers to pause, only timer is supported now")
+}
+
+var pauseTriggerCmd = &cobra.Command{
+	Use:   "pause",
+	Short: "disable the enabled triggers, which can be restored by resume",
+	Long: `
+disable the enabled triggers of the function, or all the functions in the service.
+the paused triggers are recorded in ~/.fcli/paused_triggers.yaml, and "fcli trigger resume"
+enables exactly these triggers again.
+EXAMPLE:
+fcli trigger pause -s(--service-name)  service_name
+				-f(--function-name) function_name
+fcli trigger pause -s(--service-name)  service_name
+				--all-functions
+				--type              timer
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(pauseTriggerRun(cmd))
+	},
+}
+
+func pauseTriggerRun(cmd *cobra.Command) (*string, error) {
+	if err := checkPauseTriggerArgs(*pauseTriggerInput.allFunctions, *pauseTriggerInput.triggerType); err != nil {
+		return nil, err
+	}
+	client, err := util.NewFClient(gConfig)
+	if err != nil {
+		return nil, err
+	}
+	fn := functionName
+	if *pauseTriggerInput.allFunctions {
+		fn = ""
+	}
+	refs, err := listServiceTriggers(client, serviceName, fn, *pauseTriggerInput.triggerType)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := loadPausedTriggers()
+	if err != nil {
+		return nil, err
+	}
+	key := pausedTriggersKey(serviceName)
+	paused, lines, err := pauseTimerTriggers(client, refs, state[key])
+	state[key] = paused
+	if err != nil {
+		// keep the record of the triggers paused so far
+		if saveErr := savePausedTriggers(state); saveErr != nil {
+			return nil, fmt.Errorf("%v; failed to save the paused triggers: %v", err, saveErr)
+		}
+		return nil, err
+	}
+	if err := savePausedTriggers(state); err != nil {
+		return nil, err
+	}
+	lines = append(lines, fmt.Sprintf("%d trigger(s) paused in service %s", countLines(lines, "paused "), serviceName))
+	output := strings.Join(lines, "\n")
+	return &output, nil
+}
+
+func checkPauseTriggerArgs(allFunctions bool, triggerType string) error {
+	if serviceName == "" {
+		return util.NewValidationError("service name is required")
+	}
+	if !allFunctions && functionName == "" {
+		return util.NewValidationError("function name is required unless --all-functions is specified")
+	}
+	if triggerType != fc.TRIGGER_TYPE_TIMER {
+		return util.NewValidationError("unsupported trigger type %q, only %s triggers can be paused", triggerType, fc.TRIGGER_TYPE_TIMER)
+	}
+	return nil
+}
+
+// timerTriggerClient is the part of the fc client to pause and resume the timer triggers.
+type timerTriggerClient interface {
+	GetTrigger(input *fc.GetTriggerInput) (*fc.GetTriggerOutput, error)
+	UpdateTrigger(input *fc.UpdateTriggerInput) (*fc.UpdateTriggerOutput, error)
+}
+
+// pauseTimerTriggers disables the enabled triggers and appends them to the paused ones, the triggers
+// which are already disabled are skipped, and the ones already recorded are not added again.
+// The paused triggers are returned on the failure too.
+func pauseTimerTriggers(client timerTriggerClient, refs []triggerRef, paused []pausedTrigger) (
+	[]pausedTrigger, []string, error) {
+	var lines []string
+	for _, ref := range refs {
+		changed, err := setTimerTriggerEnable(client, ref.FunctionName, ref.TriggerName, false)
+		if err != nil {
+			return paused, lines, err
+		}
+		if !changed {
+			lines = append(lines, fmt.Sprintf("skip %s/%s: already disabled", ref.FunctionName, ref.TriggerName))
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("paused %s/%s", ref.FunctionName, ref.TriggerName))
+		if isTriggerPaused(paused, ref.FunctionName, ref.TriggerName) {
+			// the trigger was enabled again after it was paused
+			continue
+		}
+		paused = append(paused, pausedTrigger{
+			FunctionName: ref.FunctionName,
+			TriggerName:  ref.TriggerName,
+			TriggerType:  ref.TriggerType,
+		})
+	}
+	return paused, lines, nil
+}
+
+func isTriggerPaused(paused []pausedTrigger, functionName, triggerName string) bool {
+	for _, t := range paused {
+		if t.FunctionName == functionName && t.TriggerName == triggerName {
+			return true
+		}
+	}
+	return false
+}
+
+// countLines returns the number of the lines with the prefix.
+func countLines(lines []string, prefix string) int {
+	n := 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, prefix) {
+			n++
+		}
+	}
+	return n
+}
+
+// setTimerTriggerEnable updates the enable field of the timer trigger with the etag,
+// it returns false if the trigger is already in the expected state.
+func setTimerTriggerEnable(client timerTriggerClient, functionName, triggerName string, enable bool) (bool, error) {
+	resp, err := client.GetTrigger(fc.NewGetTriggerInput(serviceName, functionName, triggerName))
+	if err != nil {
+		return false, err
+	}
+	config, err := util.GetTimeTriggerConfig(resp.TriggerConfig)
+	if err != nil {
+		return false, err
+	}
+	current := config.Enable == nil || *config.Enable
+	if current == enable {
+		return false, nil
+	}
+	config.Enable = &enable
+	input := fc.NewUpdateTriggerInput(serviceName, functionName, triggerName).
+		WithTriggerConfig(config).
+		WithIfMatch(resp.GetEtag())
+	if _, err := client.UpdateTrigger(input); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func pausedTriggersKey(serviceName string) string {
+	return gConfig.Endpoint + "/" + serviceName
+}
+
+func loadPausedTriggers() (pausedTriggersState, error) {
+	state := pausedTriggersState{}
+	data, err := ioutil.ReadFile(path.Join(gConfigDir, pausedTriggersFile))
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := yaml.Unmarshal(data, &state); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", pausedTriggersFile, err)
+	}
+	return state, nil
+}
+
+func savePausedTriggers(state pausedTriggersState) error {
+	for k, v := range state {
+		if len(v) == 0 {
+			delete(state, k)
+		}
+	}
+	data, err := yaml.Marshal(state)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(gConfigDir, 0700); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path.Join(gConfigDir, pausedTriggersFile), data, 0600)
+}
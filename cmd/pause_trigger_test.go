@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+
+	"github.com/aliyun/fc-go-sdk"
+)
+
+// fakeTimerTriggerClient keeps the enable state of the timer triggers by "<function>/<trigger>".
+type fakeTimerTriggerClient struct {
+	enabled    map[string]bool
+	failUpdate map[string]bool
+	missing    map[string]bool
+	updated    []string
+}
+
+func (c *fakeTimerTriggerClient) GetTrigger(input *fc.GetTriggerInput) (*fc.GetTriggerOutput, error) {
+	key := *input.FunctionName + "/" + *input.TriggerName
+	if c.missing[key] {
+		return nil, &fc.ServiceError{HTTPStatus: http.StatusNotFound, ErrorCode: "TriggerNotFound"}
+	}
+	output := &fc.GetTriggerOutput{Header: http.Header{"Etag": []string{"etag-" + key}}}
+	output.TriggerConfig = map[string]interface{}{"cronExpression": "@every 1m", "enable": c.enabled[key]}
+	return output, nil
+}
+
+func (c *fakeTimerTriggerClient) UpdateTrigger(input *fc.UpdateTriggerInput) (*fc.UpdateTriggerOutput, error) {
+	key := *input.FunctionName + "/" + *input.TriggerName
+	if c.failUpdate[key] {
+		return nil, fmt.Errorf("update %s failed", key)
+	}
+	if *input.IfMatch != "etag-"+key {
+		return nil, fmt.Errorf("etag of %s does not match", key)
+	}
+	c.enabled[key] = *input.TriggerConfig.(*fc.TimeTriggerConfig).Enable
+	c.updated = append(c.updated, key)
+	return &fc.UpdateTriggerOutput{}, nil
+}
+
+func (s *FunctionStructsTestSuite) TestPausedTriggersState() {
+	assert := s.Require()
+	dir, err := ioutil.TempDir("", "fcli-paused")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	configDir := gConfigDir
+	defer func() { gConfigDir = configDir }()
+	gConfigDir = dir
+
+	state, err := loadPausedTriggers()
+	assert.Nil(err)
+	assert.Empty(state)
+
+	state["http://fc/demo"] = []pausedTrigger{{FunctionName: "f", TriggerName: "t", TriggerType: fc.TRIGGER_TYPE_TIMER}}
+	state["http://fc/empty"] = nil
+	assert.Nil(savePausedTriggers(state))
+	loaded, err := loadPausedTriggers()
+	assert.Nil(err)
+	assert.Equal(pausedTriggersState{
+		"http://fc/demo": {{FunctionName: "f", TriggerName: "t", TriggerType: fc.TRIGGER_TYPE_TIMER}},
+	}, loaded)
+
+	ioutil.WriteFile(dir+"/"+pausedTriggersFile, []byte("not: [yaml"), 0600)
+	_, err = loadPausedTriggers()
+	assert.NotNil(err)
+}
+
+func (s *FunctionStructsTestSuite) TestPauseAndResumeTimerTriggers() {
+	assert := s.Require()
+	service := serviceName
+	defer func() { serviceName = service }()
+	serviceName = "demo"
+
+	client := &fakeTimerTriggerClient{
+		enabled:    map[string]bool{"f1/a": true, "f1/b": false, "f2/c": true},
+		failUpdate: map[string]bool{},
+	}
+	refs := []triggerRef{
+		{FunctionName: "f1", TriggerName: "a", TriggerType: fc.TRIGGER_TYPE_TIMER},
+		{FunctionName: "f1", TriggerName: "b", TriggerType: fc.TRIGGER_TYPE_TIMER},
+		{FunctionName: "f2", TriggerName: "c", TriggerType: fc.TRIGGER_TYPE_TIMER},
+	}
+	paused, lines, err := pauseTimerTriggers(client, refs, nil)
+	assert.Nil(err)
+	assert.Equal([]pausedTrigger{
+		{FunctionName: "f1", TriggerName: "a", TriggerType: fc.TRIGGER_TYPE_TIMER},
+		{FunctionName: "f2", TriggerName: "c", TriggerType: fc.TRIGGER_TYPE_TIMER},
+	}, paused)
+	assert.Equal("skip f1/b: already disabled", lines[1])
+	assert.Equal(map[string]bool{"f1/a": false, "f1/b": false, "f2/c": false}, client.enabled)
+
+	// resume only the function, then the rest
+	remains, _, err := resumeTimerTriggers(client, paused, "f2", false)
+	assert.Nil(err)
+	assert.Equal(paused[:1], remains)
+	assert.Equal(map[string]bool{"f1/a": false, "f1/b": false, "f2/c": true}, client.enabled)
+	remains, lines, err = resumeTimerTriggers(client, remains, "", true)
+	assert.Nil(err)
+	assert.Empty(remains)
+	assert.Equal([]string{"resumed f1/a"}, lines)
+	// the trigger disabled before pause stays disabled
+	assert.Equal(map[string]bool{"f1/a": true, "f1/b": false, "f2/c": true}, client.enabled)
+	assert.NotContains(client.updated, "f1/b")
+}
+
+func (s *FunctionStructsTestSuite) TestPauseAndResumeTimerTriggersPartialFailure() {
+	assert := s.Require()
+	service := serviceName
+	defer func() { serviceName = service }()
+	serviceName = "demo"
+
+	client := &fakeTimerTriggerClient{
+		enabled:    map[string]bool{"f/a": true, "f/b": true, "f/c": true},
+		failUpdate: map[string]bool{"f/b": true},
+	}
+	refs := []triggerRef{{FunctionName: "f", TriggerName: "a"}, {FunctionName: "f", TriggerName: "b"},
+		{FunctionName: "f", TriggerName: "c"}}
+	// the triggers paused before the failure are kept
+	paused, _, err := pauseTimerTriggers(client, refs, nil)
+	assert.NotNil(err)
+	assert.Equal([]pausedTrigger{{FunctionName: "f", TriggerName: "a"}}, paused)
+	assert.True(client.enabled["f/c"])
+
+	client.failUpdate = map[string]bool{}
+	paused, _, err = pauseTimerTriggers(client, refs[1:], paused)
+	assert.Nil(err)
+	assert.Len(paused, 3)
+
+	// the failed trigger and the ones after it remain paused
+	client.failUpdate = map[string]bool{"f/b": true}
+	remains, lines, err := resumeTimerTriggers(client, paused, "f", false)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "failed to resume f/b")
+	assert.Equal([]string{"resumed f/a"}, lines)
+	assert.Equal(paused[1:], remains)
+	assert.Equal(map[string]bool{"f/a": true, "f/b": false, "f/c": false}, client.enabled)
+
+	client.failUpdate = map[string]bool{}
+	remains, _, err = resumeTimerTriggers(client, remains, "f", false)
+	assert.Nil(err)
+	assert.Empty(remains)
+	assert.Equal(map[string]bool{"f/a": true, "f/b": true, "f/c": true}, client.enabled)
+}
+
+func (s *FunctionStructsTestSuite) TestPauseAgainAndResumeDeletedTriggers() {
+	assert := s.Require()
+	service := serviceName
+	defer func() { serviceName = service }()
+	serviceName = "demo"
+
+	client := &fakeTimerTriggerClient{
+		enabled:    map[string]bool{"f/a": true, "f/b": true},
+		failUpdate: map[string]bool{},
+		missing:    map[string]bool{},
+	}
+	refs := []triggerRef{{FunctionName: "f", TriggerName: "a"}, {FunctionName: "f", TriggerName: "b"}}
+	paused, _, err := pauseTimerTriggers(client, refs, nil)
+	assert.Nil(err)
+	assert.Len(paused, 2)
+
+	// the trigger enabled manually is paused again without a duplicate record
+	client.enabled["f/a"] = true
+	paused, lines, err := pauseTimerTriggers(client, refs, paused)
+	assert.Nil(err)
+	assert.Equal([]string{"paused f/a", "skip f/b: already disabled"}, lines)
+	assert.Equal([]pausedTrigger{{FunctionName: "f", TriggerName: "a"}, {FunctionName: "f", TriggerName: "b"}}, paused)
+
+	// the deleted trigger is dropped and the rest are resumed
+	client.missing["f/a"] = true
+	remains, lines, err := resumeTimerTriggers(client, paused, "f", false)
+	assert.Nil(err)
+	assert.Empty(remains)
+	assert.Equal([]string{"skip f/a: not found", "resumed f/b"}, lines)
+	assert.Equal(1, countLines(lines, "resumed "))
+	assert.True(client.enabled["f/b"])
+}
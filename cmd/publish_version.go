@@ -26,6 +26,7 @@ func init() {
 			"If the specified etag does not match the service's, the publish will fail.")
 	output = publishVersionCmd.Flags().Bool(
 		"output", false, "print raw response body of API invoke.")
+	addInputJSONFlags(publishVersionCmd)
 }
 
 var publishServiceVersionInput fc.PublishServiceVersionInput
@@ -40,21 +41,40 @@ EXAMPLE:
 fcli service version publish -s(--service-name)   service_name
 				-d(--description) description
 				--etag            a198ec37e2a1c2ababbb3717074f29ea
+fcli service version publish --generate-skeleton > version.json
+fcli service version publish --input-json version.json
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.PublishServiceVersionInput{})
+		}
+		input := &fc.PublishServiceVersionInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = publishServiceVersionInput.ServiceName
+		}
+		if useFlag(cmd, "description") {
+			input.Description = publishServiceVersionInput.Description
+		}
+		if useFlag(cmd, "etag") {
+			input.IfMatch = publishServiceVersionInput.IfMatch
+		}
+
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 
-		resp, err := client.PublishServiceVersion(&publishServiceVersionInput)
+		resp, err := client.PublishServiceVersion(input)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
+			return err
 		}
 
 		if *output {
 			fmt.Println(resp)
 		}
+		return nil
 	},
 }
@@ -0,0 +1,135 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	ramOutputJSON = "json"
+	ramOutputYAML = "yaml"
+)
+
+var ramOutputFormat string
+
+func init() {
+	RootCmd.AddCommand(ramCmd)
+	ramCmd.AddCommand(ramRoleCmd)
+	ramCmd.AddCommand(ramPolicyCmd)
+
+	ramCmd.Flags().BoolP("help", "h", true, "Print Usage")
+	ramCmd.PersistentFlags().StringVar(&ramOutputFormat, "format", ramOutputJSON, "format of the result, json or yaml")
+	ramRoleCmd.Flags().BoolP("help", "h", true, "Print Usage")
+	ramPolicyCmd.Flags().BoolP("help", "h", true, "Print Usage")
+}
+
+var ramCmd = &cobra.Command{
+	Use:   "ram",
+	Short: "ram role and policy related operation",
+	Long: `ram role and policy related operation
+
+EXAMPLE:
+  fcli ram role list
+  fcli ram policy create -p my-policy -d policy.json
+  fcli ram attach -r my-role -p my-policy
+  fcli ram simulate -r my-role -a log:PostLogStoreLogs --resource acs:log:*:123456:project/p/logstore/l
+	`,
+	Run: func(cmd *cobra.Command, args []string) {
+
+	},
+}
+
+var ramRoleCmd = &cobra.Command{
+	Use:   "role",
+	Short: "ram role related operation",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+
+	},
+}
+
+var ramPolicyCmd = &cobra.Command{
+	Use:   "policy",
+	Short: "ram policy related operation",
+	Long:  ``,
+	Run: func(cmd *cobra.Command, args []string) {
+
+	},
+}
+
+func newRAMClient() (*ram.Client, error) {
+	return util.NewRAMClient(gConfig)
+}
+
+// ramPrint prints the ram response in the format specified by --format, and returns the error.
+func ramPrint(content interface{}, err error) error {
+	if err != nil {
+		return err
+	}
+	output, err := formatRAMOutput(content, ramOutputFormat)
+	if err != nil {
+		return err
+	}
+	return prettyPrint(output, nil)
+}
+
+func formatRAMOutput(content interface{}, format string) (string, error) {
+	b, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	switch format {
+	case ramOutputJSON:
+		return string(b), nil
+	case ramOutputYAML:
+		// go through json so that the keys are the same as the json output
+		var v interface{}
+		var obj yaml.MapSlice
+		if err := yaml.Unmarshal(b, &obj); err == nil {
+			v = obj
+		} else if err := yaml.Unmarshal(b, &v); err != nil {
+			return "", err
+		}
+		y, err := yaml.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return strings.TrimSuffix(string(y), "\n"), nil
+	default:
+		return "", util.NewValidationError("unsupported --format %q, expect json or yaml", format)
+	}
+}
+
+// readPolicyDocument reads the policy document from the file and compacts it.
+func readPolicyDocument(file string) (string, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return "", fmt.Errorf("failed to read policy document %s due to %v", file, err)
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return "", util.NewValidationError("invalid policy document %s: %v", file, err)
+	}
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// decodePolicyDocument decodes the policy document string in the response, so that it
+// is displayed as a structure. The raw string is returned if it is not valid json.
+func decodePolicyDocument(doc string) interface{} {
+	var v interface{}
+	if err := json.Unmarshal([]byte(doc), &v); err != nil {
+		return doc
+	}
+	return v
+}
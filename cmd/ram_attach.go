@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+type ramAttachInputType struct {
+	roleName   *string
+	policyName *string
+	policyType *string
+}
+
+var ramAttachInput ramAttachInputType
+var ramDetachInput ramAttachInputType
+var ramListForRoleName *string
+
+func init() {
+	ramCmd.AddCommand(ramAttachCmd)
+	ramCmd.AddCommand(ramDetachCmd)
+	ramCmd.AddCommand(ramListForRoleCmd)
+
+	for _, c := range []*cobra.Command{ramAttachCmd, ramDetachCmd, ramListForRoleCmd} {
+		c.Flags().Bool("help", false, c.Short)
+	}
+	for _, input := range []struct {
+		cmd   *cobra.Command
+		input *ramAttachInputType
+	}{{ramAttachCmd, &ramAttachInput}, {ramDetachCmd, &ramDetachInput}} {
+		input.input.roleName = input.cmd.Flags().StringP("role-name", "r", "", "the role name")
+		input.input.policyName = input.cmd.Flags().StringP("policy-name", "p", "", "the policy name")
+		input.input.policyType = input.cmd.Flags().String("type", "Custom", "the policy type, Custom or System")
+	}
+	ramListForRoleName = ramListForRoleCmd.Flags().StringP("role-name", "r", "", "the role name")
+}
+
+var ramAttachCmd = &cobra.Command{
+	Use:   "attach",
+	Short: "attach ram policy to role",
+	Long: `
+attach ram policy to role
+EXAMPLE:
+fcli ram attach -r(--role-name)   role_name
+				-p(--policy-name) policy_name
+				--type            Custom
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramAttachRun(ramAttachInput, true))
+	},
+}
+
+var ramDetachCmd = &cobra.Command{
+	Use:   "detach",
+	Short: "detach ram policy from role",
+	Long: `
+detach ram policy from role
+EXAMPLE:
+fcli ram detach -r(--role-name)   role_name
+				-p(--policy-name) policy_name
+				--type            Custom
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramAttachRun(ramDetachInput, false))
+	},
+}
+
+func ramAttachRun(input ramAttachInputType, attach bool) (interface{}, error) {
+	if *input.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	if *input.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	if attach {
+		return client.AttachPolicyToRole(*input.policyType, *input.policyName, *input.roleName)
+	}
+	return client.DetachPolicyFromRole(*input.policyType, *input.policyName, *input.roleName)
+}
+
+var ramListForRoleCmd = &cobra.Command{
+	Use:   "list-for-role",
+	Short: "list ram policies attached to role",
+	Long: `
+list ram policies attached to role
+EXAMPLE:
+fcli ram list-for-role -r(--role-name) role_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramListForRoleRun())
+	},
+}
+
+func ramListForRoleRun() (interface{}, error) {
+	if *ramListForRoleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.ListPoliciesForRole(*ramListForRoleName)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Policies.Policy, nil
+}
@@ -0,0 +1,462 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// the issues found by the ram audit
+const (
+	ramAuditUnused    = "Unused"
+	ramAuditDangling  = "Dangling"
+	ramAuditOverBroad = "OverBroad"
+	ramAuditUnchecked = "Unchecked"
+	ramAuditForeign   = "CrossAccount"
+)
+
+// the prefix of the roles and policies created by trigger create --auto-role and ram generate-policy
+const fcliRolePrefix = "fcli-"
+
+// the trust principals of the roles which are audited
+var ramAuditPrincipals = []string{rolePrincipal, "oss.aliyuncs.com"}
+
+// the names of the policies created by fcli, such as role config and trigger create --auto-role
+var ramAuditPolicyPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`^fc-.+-logging-\d+$`),
+	regexp.MustCompile(`^fc-.+-oss-\d+$`),
+	regexp.MustCompile(`^fc-.+-policy$`),
+	regexp.MustCompile(`^fcli-`),
+}
+
+var ramAuditInput struct {
+	cleanup *bool
+	yes     *bool
+}
+
+type ramAuditRole struct {
+	Name       string
+	Principals []string
+}
+
+type ramAuditPolicy struct {
+	Name            string
+	AttachmentCount int64
+	Document        *ram.PolicyDocument
+}
+
+// ramAuditFinding is an item reported by the audit.
+type ramAuditFinding struct {
+	Type       string   `json:"Type"`
+	Name       string   `json:"Name"`
+	Issue      string   `json:"Issue"`
+	Detail     string   `json:"Detail"`
+	References []string `json:"References,omitempty"`
+
+	// the role or policy is named by fcli, only these are deleted by the cleanup
+	generated bool
+}
+
+// ramAuditReferences is the services and triggers referencing each role, keyed by the lower case
+// role name for the roles of the account, and by the arn for the roles of other accounts.
+type ramAuditReferences struct {
+	roles   map[string][]string
+	foreign map[string][]string
+}
+
+func init() {
+	ramCmd.AddCommand(ramAuditCmd)
+	ramAuditCmd.Flags().Bool("help", false, "audit the roles and policies created for function compute")
+	ramAuditInput.cleanup = ramAuditCmd.Flags().Bool(
+		"cleanup", false, "detach and delete the unused roles and policies after confirmation")
+	ramAuditInput.yes = ramAuditCmd.Flags().BoolP("yes", "y", false, "clean up without confirmation")
+}
+
+var ramAuditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "audit the roles and policies created for function compute",
+	Long: `
+audit the roles which can be assumed by fc.aliyuncs.com or oss.aliyuncs.com, and the policies
+named by fcli, such as fc-{role}-policy, fc-{role}-logging-{timestamp} and fcli-{trigger}-...
+They are cross-referenced with the role of every service and the invocation role of every trigger,
+and reported as:
+Unused:    the role is not referenced, or the policy is not attached to any role
+Dangling:  the role is referenced by a service or trigger but does not exist
+CrossAccount: the role of another account is referenced, which is not checked
+OverBroad: the policy allows the "*" resource
+Unchecked: the services of the region can not be listed
+
+The services and triggers in all the regions are checked. The cleanup only deletes the unused roles
+and policies named by fcli, fcli-service-{service}, fcli-{trigger type}-{service}-{function} and
+fc-{role}-policy of an existing role, and it is refused if any region is unchecked.
+EXAMPLE:
+fcli ram audit
+fcli ram audit --cleanup
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		findings, err := ramAuditRun()
+		if err := ramPrint(findings, err); err != nil || !*ramAuditInput.cleanup {
+			return err
+		}
+		return ramAuditCleanup(findings)
+	},
+}
+
+func ramAuditRun() ([]ramAuditFinding, error) {
+	ramClient, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	regions := util.GetRegions()
+	results, failures := forEachRegion(regions, func(client *util.FClient, region string) (interface{}, error) {
+		return collectRoleReferences(client, region, uid)
+	})
+	refs := ramAuditReferences{roles: map[string][]string{}, foreign: map[string][]string{}}
+	for _, result := range results {
+		if result == nil {
+			continue
+		}
+		r := result.(ramAuditReferences)
+		for name, references := range r.roles {
+			refs.roles[name] = append(refs.roles[name], references...)
+		}
+		for arn, references := range r.foreign {
+			refs.foreign[arn] = append(refs.foreign[arn], references...)
+		}
+	}
+	allRoles, err := ramClient.ListAllRoles()
+	if err != nil {
+		return nil, err
+	}
+	roleNames := map[string]bool{}
+	roles := []ramAuditRole{}
+	for _, r := range allRoles {
+		roleNames[strings.ToLower(r.RoleName)] = true
+		doc := r.AssumeRolePolicyDocument
+		if doc == "" {
+			// the trust policy is not returned by ListRoles
+			resp, err := ramClient.GetRole(r.RoleName)
+			if err != nil {
+				return nil, err
+			}
+			doc = resp.Role.AssumeRolePolicyDocument
+		}
+		trustPolicy, err := ram.ParseAssumeRolePolicyDocument(doc)
+		if err != nil {
+			continue
+		}
+		role := ramAuditRole{Name: r.RoleName}
+		for _, p := range ramAuditPrincipals {
+			if trustPolicy.HasServicePrincipal(p) {
+				role.Principals = append(role.Principals, p)
+			}
+		}
+		if len(role.Principals) > 0 {
+			roles = append(roles, role)
+		}
+	}
+
+	allPolicies, err := ramClient.ListAllPolicies("Custom")
+	if err != nil {
+		return nil, err
+	}
+	policies := []ramAuditPolicy{}
+	for _, p := range allPolicies {
+		if !isFcliPolicyName(p.PolicyName) {
+			continue
+		}
+		policy := ramAuditPolicy{Name: p.PolicyName, AttachmentCount: p.AttachmentCount}
+		version, err := util.GetDefaultPolicyVersion(ramClient, p.PolicyName, "Custom")
+		if err != nil {
+			return nil, err
+		}
+		doc := &ram.PolicyDocument{}
+		if err := json.Unmarshal([]byte(version.PolicyDocument), doc); err == nil {
+			policy.Document = doc
+		}
+		policies = append(policies, policy)
+	}
+
+	findings := auditRAMResources(roles, policies, roleNames, refs)
+	for _, f := range failures {
+		findings = append(findings, ramAuditFinding{
+			Type:   "Region",
+			Name:   f.Region,
+			Issue:  ramAuditUnchecked,
+			Detail: "failed to list the services and triggers: " + f.Error,
+		})
+	}
+	return findings, nil
+}
+
+func isFcliPolicyName(name string) bool {
+	for _, p := range ramAuditPolicyPatterns {
+		if p.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFcliGeneratedName returns true if the name is generated by trigger create --auto-role or
+// ram generate-policy, which is fcli-service-{service} or fcli-{trigger type}-{service}-{function}.
+func isFcliGeneratedName(name string) bool {
+	kinds := []string{"service"}
+	for t := range util.TriggerInvocationPrincipals {
+		kinds = append(kinds, strings.Replace(t, "_", "-", -1))
+	}
+	for _, kind := range kinds {
+		prefix := fcliRolePrefix + kind + "-"
+		if strings.HasPrefix(name, prefix) && len(name) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRoleARN returns the uid and the role name of the arn acs:ram::{uid}:role/{name}.
+func parseRoleARN(arn string) (uid, name string) {
+	const prefix = "acs:ram::"
+	i := strings.Index(arn, ":role/")
+	if !strings.HasPrefix(arn, prefix) || i < len(prefix) {
+		return "", ""
+	}
+	return arn[len(prefix):i], arn[i+len(":role/"):]
+}
+
+// collectRoleReferences returns the services and triggers of the region referencing each role.
+// The roles of the accounts other than uid are collected separately.
+func collectRoleReferences(client *util.FClient, region, uid string) (ramAuditReferences, error) {
+	refs := ramAuditReferences{roles: map[string][]string{}, foreign: map[string][]string{}}
+	add := func(arn, reference string) {
+		roleUID, name := parseRoleARN(arn)
+		switch {
+		case name == "":
+		case roleUID != uid:
+			refs.foreign[arn] = append(refs.foreign[arn], reference)
+		default:
+			key := strings.ToLower(name)
+			refs.roles[key] = append(refs.roles[key], reference)
+		}
+	}
+	input := fc.NewListServicesInput().WithLimit(100)
+	for {
+		resp, err := client.ListServices(input)
+		if err != nil {
+			return refs, err
+		}
+		for _, svc := range resp.Services {
+			serviceName := *svc.ServiceName
+			if svc.Role != nil {
+				add(*svc.Role, fmt.Sprintf("service %s/%s", region, serviceName))
+			}
+			triggers, err := listServiceTriggers(client, serviceName, "", "")
+			if err != nil {
+				return refs, err
+			}
+			for _, t := range triggers {
+				add(t.InvocationRole, fmt.Sprintf("trigger %s/%s/%s/%s", region, serviceName, t.FunctionName, t.TriggerName))
+			}
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+		input.WithNextToken(*resp.NextToken)
+	}
+	return refs, nil
+}
+
+// auditRAMResources cross-references the roles and policies with the references of the roles.
+func auditRAMResources(roles []ramAuditRole, policies []ramAuditPolicy,
+	roleNames map[string]bool, refs ramAuditReferences) []ramAuditFinding {
+	findings := []ramAuditFinding{}
+	for _, r := range roles {
+		if len(refs.roles[strings.ToLower(r.Name)]) == 0 {
+			findings = append(findings, ramAuditFinding{
+				Type:      "Role",
+				Name:      r.Name,
+				Issue:     ramAuditUnused,
+				Detail:    fmt.Sprintf("trusts %s but is not used by any service or trigger", strings.Join(r.Principals, ", ")),
+				generated: isFcliGeneratedName(r.Name),
+			})
+		}
+	}
+
+	dangling := []string{}
+	for name := range refs.roles {
+		if !roleNames[name] {
+			dangling = append(dangling, name)
+		}
+	}
+	sort.Strings(dangling)
+	for _, name := range dangling {
+		findings = append(findings, ramAuditFinding{
+			Type:       "Role",
+			Name:       name,
+			Issue:      ramAuditDangling,
+			Detail:     "is referenced but does not exist",
+			References: refs.roles[name],
+		})
+	}
+
+	foreign := []string{}
+	for arn := range refs.foreign {
+		foreign = append(foreign, arn)
+	}
+	sort.Strings(foreign)
+	for _, arn := range foreign {
+		uid, _ := parseRoleARN(arn)
+		findings = append(findings, ramAuditFinding{
+			Type:       "Role",
+			Name:       arn,
+			Issue:      ramAuditForeign,
+			Detail:     fmt.Sprintf("belongs to the account %s and is not checked", uid),
+			References: refs.foreign[arn],
+		})
+	}
+
+	for _, p := range policies {
+		if p.AttachmentCount == 0 {
+			findings = append(findings, ramAuditFinding{
+				Type:      "Policy",
+				Name:      p.Name,
+				Issue:     ramAuditUnused,
+				Detail:    "is not attached to any role",
+				generated: isFcliGeneratedName(p.Name) || isManagedPolicyName(p.Name, roleNames),
+			})
+		}
+		if p.Document == nil {
+			continue
+		}
+		for i, s := range p.Document.Statement {
+			if s.Effect != "Allow" {
+				continue
+			}
+			for _, r := range s.Resource {
+				if r == "*" {
+					findings = append(findings, ramAuditFinding{
+						Type:   "Policy",
+						Name:   p.Name,
+						Issue:  ramAuditOverBroad,
+						Detail: fmt.Sprintf("statement %d allows %s on all resources", i, strings.Join(s.Action, ", ")),
+					})
+					break
+				}
+			}
+		}
+	}
+	return findings
+}
+
+// isManagedPolicyName returns true if the name is fc-{role}-policy created by role config for one of the roles.
+func isManagedPolicyName(name string, roleNames map[string]bool) bool {
+	prefix, suffix := "fc-", "-policy"
+	if !strings.HasPrefix(name, prefix) || !strings.HasSuffix(name, suffix) || len(name) <= len(prefix)+len(suffix) {
+		return false
+	}
+	return roleNames[strings.ToLower(name[len(prefix):len(name)-len(suffix)])]
+}
+
+// ramAuditCleanupTargets returns the unused roles and policies named by fcli, which are safe to delete.
+// It fails if any region is unchecked, whose services may use the roles.
+func ramAuditCleanupTargets(findings []ramAuditFinding) ([]ramAuditFinding, error) {
+	unchecked := []string{}
+	unused := []ramAuditFinding{}
+	for _, f := range findings {
+		switch {
+		case f.Issue == ramAuditUnchecked:
+			unchecked = append(unchecked, f.Name)
+		case f.Issue != ramAuditUnused:
+		case f.Type == "Role" && !f.generated:
+			fmt.Fprintf(os.Stderr, "skip role %s: it is not created by fcli, delete it by 'fcli ram role delete'\n", f.Name)
+		case !f.generated:
+			fmt.Fprintf(os.Stderr, "skip policy %s: it is not created by fcli, delete it by 'fcli ram policy delete'\n", f.Name)
+		default:
+			unused = append(unused, f)
+		}
+	}
+	if len(unchecked) > 0 {
+		return nil, fmt.Errorf("refuse to clean up, the roles may be used in the unchecked regions: %s",
+			strings.Join(unchecked, ", "))
+	}
+	return unused, nil
+}
+
+// ramAuditCleanup detaches and deletes the unused roles and policies named by fcli.
+func ramAuditCleanup(findings []ramAuditFinding) error {
+	unused, err := ramAuditCleanupTargets(findings)
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		fmt.Println("nothing to clean up")
+		return nil
+	}
+	if !*ramAuditInput.yes {
+		if !isInteractive() {
+			return util.NewValidationError("--yes is required in non-interactive mode")
+		}
+		fmt.Printf("the following %d roles and policies will be deleted:\n", len(unused))
+		for _, f := range unused {
+			fmt.Printf("  %s %s\n", strings.ToLower(f.Type), f.Name)
+		}
+		ok, err := confirm(bufio.NewReader(os.Stdin), "Do you want to delete them?")
+		if err != nil || !ok {
+			return err
+		}
+	}
+
+	client, err := newRAMClient()
+	if err != nil {
+		return err
+	}
+	for _, f := range unused {
+		if f.Type == "Role" {
+			err = deleteRoleAndDetach(client, f.Name)
+		} else {
+			err = deletePolicyAndVersions(client, f.Name)
+		}
+		if err != nil {
+			return util.WrapErrorf(err, "failed to delete %s %s", strings.ToLower(f.Type), f.Name)
+		}
+		fmt.Printf("deleted %s %s\n", strings.ToLower(f.Type), f.Name)
+	}
+	return nil
+}
+
+func deleteRoleAndDetach(client *ram.Client, roleName string) error {
+	resp, err := client.ListPoliciesForRole(roleName)
+	if err != nil {
+		return err
+	}
+	for _, p := range resp.Policies.Policy {
+		if _, err := client.DetachPolicyFromRole(p.PolicyType, p.PolicyName, roleName); err != nil {
+			return err
+		}
+	}
+	_, err = client.DeleteRole(roleName)
+	return err
+}
+
+func deletePolicyAndVersions(client *ram.Client, policyName string) error {
+	// the non-default versions must be deleted before the policy
+	if err := util.PrunePolicyVersions(client, policyName, 1); err != nil {
+		return err
+	}
+	_, err := client.DeletePolicy(policyName)
+	return err
+}
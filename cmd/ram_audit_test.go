@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestAuditRAMResources() {
+	assert := s.Require()
+	uid, name := parseRoleARN("acs:ram::123456:role/Demo-Role")
+	assert.Equal("123456", uid)
+	assert.Equal("Demo-Role", name)
+	uid, name = parseRoleARN("role/Demo-Role")
+	assert.Equal("", uid)
+	assert.Equal("", name)
+	assert.True(isFcliGeneratedName("fcli-service-svc"))
+	assert.True(isFcliGeneratedName("fcli-cdn-events-svc-fn"))
+	assert.False(isFcliGeneratedName("fcli-my-role"))
+	assert.True(isFcliPolicyName("fc-demo-role-logging-1500000000"))
+	assert.True(isFcliPolicyName("fcli-oss-svc-fn"))
+	assert.False(isFcliPolicyName("my-policy"))
+
+	roles := []ramAuditRole{
+		{Name: "used-role", Principals: []string{"fc.aliyuncs.com"}},
+		{Name: "unused-role", Principals: []string{"oss.aliyuncs.com"}},
+	}
+	policies := []ramAuditPolicy{
+		{Name: "fc-used-role-policy", AttachmentCount: 1, Document: &ram.PolicyDocument{
+			Statement: []ram.PolicyStatement{{Effect: "Allow", Action: []string{"log:*"}, Resource: []string{"*"}}},
+		}},
+		{Name: "fc-gone-role-policy"},
+		{Name: "fc-Unused-Role-policy"},
+		{Name: "fcli-oss-svc-fn"},
+	}
+	roleNames := map[string]bool{"used-role": true, "unused-role": true}
+	refs := ramAuditReferences{
+		roles: map[string][]string{
+			"used-role":    {"service svc"},
+			"missing-role": {"trigger svc/fn/t"},
+		},
+		foreign: map[string][]string{
+			"acs:ram::654321:role/unused-role": {"service other"},
+		},
+	}
+
+	findings := auditRAMResources(roles, policies, roleNames, refs)
+	assert.Equal(7, len(findings))
+	assert.Equal("unused-role", findings[0].Name)
+	assert.Equal(ramAuditUnused, findings[0].Issue)
+	assert.Equal("missing-role", findings[1].Name)
+	assert.Equal(ramAuditDangling, findings[1].Issue)
+	assert.Equal([]string{"trigger svc/fn/t"}, findings[1].References)
+	assert.Equal("acs:ram::654321:role/unused-role", findings[2].Name)
+	assert.Equal(ramAuditForeign, findings[2].Issue)
+	assert.Equal("fc-used-role-policy", findings[3].Name)
+	assert.Equal(ramAuditOverBroad, findings[3].Issue)
+	assert.Equal("fc-gone-role-policy", findings[4].Name)
+	assert.Equal(ramAuditUnused, findings[4].Issue)
+	assert.False(findings[4].generated)
+	assert.Equal("fc-Unused-Role-policy", findings[5].Name)
+	assert.True(findings[5].generated)
+	assert.Equal("fcli-oss-svc-fn", findings[6].Name)
+	assert.True(findings[6].generated)
+}
+
+func (s *FunctionStructsTestSuite) TestRAMAuditCleanupTargets() {
+	assert := s.Require()
+	findings := []ramAuditFinding{
+		{Type: "Role", Name: "fcli-oss-svc-fn", Issue: ramAuditUnused, generated: true},
+		{Type: "Role", Name: "my-oss-role", Issue: ramAuditUnused},
+		{Type: "Role", Name: "missing-role", Issue: ramAuditDangling},
+		{Type: "Role", Name: "acs:ram::654321:role/other", Issue: ramAuditForeign},
+		{Type: "Policy", Name: "fc-my-oss-role-policy", Issue: ramAuditUnused, generated: true},
+		{Type: "Policy", Name: "fc-gone-role-policy", Issue: ramAuditUnused},
+		{Type: "Policy", Name: "fcli-custom", Issue: ramAuditUnused},
+		{Type: "Policy", Name: "fc-used-role-policy", Issue: ramAuditOverBroad},
+	}
+	targets, err := ramAuditCleanupTargets(findings)
+	assert.Nil(err)
+	assert.Equal([]ramAuditFinding{findings[0], findings[4]}, targets)
+
+	findings = append(findings, ramAuditFinding{Type: "Region", Name: "cn-shanghai", Issue: ramAuditUnchecked})
+	_, err = ramAuditCleanupTargets(findings)
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "cn-shanghai")
+}
+
+func (s *FunctionStructsTestSuite) TestRAMAuditCleanupNonInteractive() {
+	assert := s.Require()
+	os.Setenv("CI", "true")
+	defer os.Unsetenv("CI")
+	findings := []ramAuditFinding{{Type: "Role", Name: "fcli-oss-svc-fn", Issue: ramAuditUnused, generated: true}}
+	err := ramAuditCleanup(findings)
+	assert.NotNil(err)
+	assert.Equal(util.ErrorClassValidation, util.ClassOf(err))
+	assert.Contains(err.Error(), "--yes")
+}
@@ -0,0 +1,234 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"time"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var generatePolicyInput struct {
+	serviceName *string
+	apply       *bool
+}
+
+// ramGeneratedPolicy is the policy generated for the service role or the trigger invocation role.
+type ramGeneratedPolicy struct {
+	For        string              `json:"For"`
+	RoleName   string              `json:"RoleName"`
+	RoleArn    string              `json:"RoleArn,omitempty"`
+	Principal  string              `json:"Principal"`
+	PolicyName string              `json:"PolicyName"`
+	Document   *ram.PolicyDocument `json:"PolicyDocument"`
+
+	// set the role of the services or the triggers without one after the policy is applied
+	updates []func(client *util.FClient, roleARN string) error
+}
+
+func init() {
+	ramCmd.AddCommand(ramGeneratePolicyCmd)
+	ramGeneratePolicyCmd.Flags().Bool("help", false, "generate the least privilege policies of the service")
+	generatePolicyInput.serviceName = ramGeneratePolicyCmd.Flags().StringP("service-name", "s", "", "the service name")
+	generatePolicyInput.apply = ramGeneratePolicyCmd.Flags().Bool(
+		"apply", false, "create or update the roles and policies, and set the roles of the service and triggers without one")
+}
+
+var ramGeneratePolicyCmd = &cobra.Command{
+	Use:   "generate-policy",
+	Short: "generate the least privilege policies of the service",
+	Long: `
+generate the least privilege policies for the service role from the log, vpc and nas config
+of the service, and for the invocation role of each trigger of the service.
+The roles in use are kept, otherwise the roles are named as:
+fcli-service-{service}
+fcli-{triggerType}-{service}-{function}
+and the policies are named as the latter ones. The service or trigger using a role of another
+account is skipped.
+EXAMPLE:
+fcli ram generate-policy -s(--service-name) service_name
+fcli ram generate-policy -s(--service-name) service_name --apply
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(generatePolicyRun())
+	},
+}
+
+func generatePolicyRun() (interface{}, error) {
+	serviceName := *generatePolicyInput.serviceName
+	if serviceName == "" {
+		return nil, util.NewValidationError("-s(--service-name) is required")
+	}
+	client, err := util.NewFClient(gConfig)
+	if err != nil {
+		return nil, err
+	}
+	policies, err := generateServicePolicies(client, serviceName)
+	if err != nil {
+		return nil, err
+	}
+	if !*generatePolicyInput.apply {
+		return policies, nil
+	}
+
+	ramClient, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	updated := false
+	for i, p := range policies {
+		roleARN, err := util.ApplyRolePolicy(ramClient, p.RoleName, p.Principal, p.PolicyName, p.Document)
+		if err != nil {
+			return nil, util.WrapErrorf(err, "failed to apply the policy of %s", p.For)
+		}
+		policies[i].RoleArn = roleARN
+		updated = updated || len(p.updates) > 0
+	}
+	if updated {
+		// has latency when attach policy to role, wait to avoid the inconsistent for policy and role.
+		time.Sleep(3 * time.Second)
+	}
+	for _, p := range policies {
+		for _, update := range p.updates {
+			if err := update(client, p.RoleArn); err != nil {
+				return nil, util.WrapErrorf(err, "failed to set the role of %s", p.For)
+			}
+		}
+		if len(p.updates) > 0 {
+			fmt.Fprintf(os.Stderr, "set the role of %s to %s\n", p.For, p.RoleArn)
+		}
+	}
+	return policies, nil
+}
+
+// generateServicePolicies generates the policies of the service role and the trigger invocation roles.
+func generateServicePolicies(client *util.FClient, serviceName string) ([]ramGeneratedPolicy, error) {
+	uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	region, err := util.GetRegionForEndpoint(gConfig.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, err := client.GetService(fc.NewGetServiceInput(serviceName))
+	if err != nil {
+		return nil, err
+	}
+	policies := []ramGeneratedPolicy{}
+	if doc := util.ServicePolicyDocument(uid, svc.LogConfig, svc.VPCConfig, svc.NASConfig); doc != nil {
+		name := ramName("fcli-service-" + serviceName)
+		p := ramGeneratedPolicy{
+			For:        "service " + serviceName,
+			RoleName:   name,
+			Principal:  rolePrincipal,
+			PolicyName: name,
+			Document:   doc,
+		}
+		roleName, ok := accountRoleName(stringValue(svc.Role), uid)
+		if ok && roleName != "" {
+			p.RoleName = roleName
+		} else if ok {
+			p.updates = append(p.updates, func(client *util.FClient, roleARN string) error {
+				_, err := client.UpdateService(fc.NewUpdateServiceInput(serviceName).WithRole(roleARN))
+				return err
+			})
+		}
+		if ok {
+			policies = append(policies, p)
+		} else {
+			fmt.Fprintf(os.Stderr, "skip %s: its role %s is not a role of the account %s\n", p.For, *svc.Role, uid)
+		}
+	}
+
+	triggers, err := listServiceTriggers(client, serviceName, "", "")
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range triggers {
+		principal, ok := util.TriggerInvocationPrincipals[t.TriggerType]
+		if !ok {
+			continue
+		}
+		resp, err := client.GetTrigger(fc.NewGetTriggerInput(serviceName, t.FunctionName, t.TriggerName))
+		if err != nil {
+			return nil, err
+		}
+		doc, err := util.TriggerPolicyDocument(region, uid, serviceName, t.FunctionName, t.TriggerType, resp.TriggerConfig)
+		if err != nil {
+			return nil, err
+		}
+		name := ramName("fcli-" + t.TriggerType + "-" + serviceName + "-" + t.FunctionName)
+		p := ramGeneratedPolicy{
+			For:        fmt.Sprintf("trigger %s/%s/%s", serviceName, t.FunctionName, t.TriggerName),
+			RoleName:   name,
+			Principal:  principal,
+			PolicyName: name,
+			Document:   doc,
+		}
+		roleName, ok := accountRoleName(t.InvocationRole, uid)
+		if !ok {
+			fmt.Fprintf(os.Stderr, "skip %s: its role %s is not a role of the account %s\n", p.For, t.InvocationRole, uid)
+			continue
+		}
+		if roleName != "" {
+			p.RoleName = roleName
+		} else {
+			functionName, triggerName := t.FunctionName, t.TriggerName
+			p.updates = append(p.updates, func(client *util.FClient, roleARN string) error {
+				_, err := client.UpdateTrigger(
+					fc.NewUpdateTriggerInput(serviceName, functionName, triggerName).WithInvocationRole(roleARN))
+				return err
+			})
+		}
+		policies = mergeGeneratedPolicy(policies, p)
+	}
+	if len(policies) == 0 {
+		fmt.Fprintf(os.Stderr, "service %s needs no ram policy\n", serviceName)
+	}
+	return policies, nil
+}
+
+// accountRoleName returns the role name of the arn, or "" if the arn is empty.
+// ok is false if the role does not belong to the account uid, whose policies can not be applied.
+func accountRoleName(arn, uid string) (roleName string, ok bool) {
+	if arn == "" {
+		return "", true
+	}
+	roleUID, roleName := parseRoleARN(arn)
+	if roleName == "" || roleUID != uid {
+		return "", false
+	}
+	return roleName, true
+}
+
+// mergeGeneratedPolicy merges the policy into the one with the same role and policy name,
+// which happens when the function has several triggers of the same type.
+func mergeGeneratedPolicy(policies []ramGeneratedPolicy, p ramGeneratedPolicy) []ramGeneratedPolicy {
+	for i, existing := range policies {
+		if existing.RoleName != p.RoleName || existing.PolicyName != p.PolicyName {
+			continue
+		}
+		for _, statement := range p.Document.Statement {
+			found := false
+			for _, s := range existing.Document.Statement {
+				if reflect.DeepEqual(s, statement) {
+					found = true
+					break
+				}
+			}
+			if !found {
+				policies[i].Document.Statement = append(policies[i].Document.Statement, statement)
+			}
+		}
+		policies[i].For += ", " + p.For
+		policies[i].updates = append(policies[i].updates, p.updates...)
+		return policies
+	}
+	return append(policies, p)
+}
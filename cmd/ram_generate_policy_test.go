@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestMergeGeneratedPolicy() {
+	assert := s.Require()
+	doc1, err := util.TriggerPolicyDocument("cn-hangzhou", "123", "svc", "fn", "log", map[string]interface{}{
+		"logConfig": map[string]string{"project": "p", "logstore": "job1"},
+	})
+	assert.Nil(err)
+	doc2, err := util.TriggerPolicyDocument("cn-hangzhou", "123", "svc", "fn", "log", map[string]interface{}{
+		"logConfig": map[string]string{"project": "p", "logstore": "job2"},
+	})
+	assert.Nil(err)
+	assert.Equal(2, len(doc1.Statement))
+
+	policies := mergeGeneratedPolicy(nil, ramGeneratedPolicy{For: "trigger svc/fn/t1", RoleName: "r", PolicyName: "r", Document: doc1})
+	policies = mergeGeneratedPolicy(policies, ramGeneratedPolicy{For: "trigger svc/fn/t2", RoleName: "r", PolicyName: "r", Document: doc2})
+	assert.Equal(1, len(policies))
+	assert.Equal("trigger svc/fn/t1, trigger svc/fn/t2", policies[0].For)
+	assert.Equal(3, len(policies[0].Document.Statement))
+	assert.Equal([]string{"acs:log:*:123:project/p/logstore/job2"}, []string(policies[0].Document.Statement[2].Resource))
+}
+
+func (s *FunctionStructsTestSuite) TestAccountRoleName() {
+	assert := s.Require()
+	roleName, ok := accountRoleName("acs:ram::123:role/My-Role", "123")
+	assert.True(ok)
+	assert.Equal("My-Role", roleName)
+	roleName, ok = accountRoleName("", "123")
+	assert.True(ok)
+	assert.Equal("", roleName)
+	_, ok = accountRoleName("acs:ram::456:role/My-Role", "123")
+	assert.False(ok)
+	_, ok = accountRoleName("My-Role", "123")
+	assert.False(ok)
+}
@@ -0,0 +1,296 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+type ramPolicyInputType struct {
+	policyName   *string
+	policyType   *string
+	documentFile *string
+	description  *string
+	versionID    *string
+}
+
+var ramPolicyInput ramPolicyInputType
+
+// ramPolicyVersionOutput displays the policy version with the decoded policy document.
+type ramPolicyVersionOutput struct {
+	VersionID        string      `json:"VersionId"`
+	IsDefaultVersion bool        `json:"IsDefaultVersion"`
+	CreateDate       string      `json:"CreateDate"`
+	PolicyDocument   interface{} `json:"PolicyDocument,omitempty"`
+}
+
+func newRAMPolicyVersionOutput(v ram.PolicyVersion) ramPolicyVersionOutput {
+	output := ramPolicyVersionOutput{
+		VersionID:        v.VersionID,
+		IsDefaultVersion: v.IsDefaultVersion,
+		CreateDate:       v.CreateDate,
+	}
+	if v.PolicyDocument != "" {
+		output.PolicyDocument = decodePolicyDocument(v.PolicyDocument)
+	}
+	return output
+}
+
+func init() {
+	ramPolicyCmd.AddCommand(ramPolicyCreateCmd)
+	ramPolicyCmd.AddCommand(ramPolicyGetCmd)
+	ramPolicyCmd.AddCommand(ramPolicyListCmd)
+	ramPolicyCmd.AddCommand(ramPolicyDeleteCmd)
+	ramPolicyCmd.AddCommand(ramPolicyVersionsCmd)
+	ramPolicyCmd.AddCommand(ramPolicySetDefaultCmd)
+	ramPolicyCmd.AddCommand(ramPolicyDeleteVersionCmd)
+
+	for _, c := range []*cobra.Command{
+		ramPolicyCreateCmd, ramPolicyGetCmd, ramPolicyListCmd, ramPolicyDeleteCmd, ramPolicyVersionsCmd,
+		ramPolicySetDefaultCmd, ramPolicyDeleteVersionCmd} {
+		c.Flags().Bool("help", false, c.Short)
+	}
+
+	ramPolicyInput.policyName = ramPolicyCmd.PersistentFlags().StringP("policy-name", "p", "", "the policy name")
+	ramPolicyInput.policyType = ramPolicyCmd.PersistentFlags().String("type", "Custom", "the policy type, Custom or System")
+	ramPolicyInput.documentFile = ramPolicyCreateCmd.Flags().StringP(
+		"document", "d", "", "the policy document file in json format")
+	ramPolicyInput.description = ramPolicyCreateCmd.Flags().String("description", "", "the policy description")
+	ramPolicyInput.versionID = ramPolicyVersionsCmd.Flags().String(
+		"version-id", "", "only show the specified version, such as v1")
+	for _, c := range []*cobra.Command{ramPolicySetDefaultCmd, ramPolicyDeleteVersionCmd} {
+		c.Flags().StringVar(ramPolicyInput.versionID, "version-id", "", "the policy version, such as v1")
+	}
+}
+
+var ramPolicyCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create ram policy",
+	Long: `
+create ram custom policy
+EXAMPLE:
+fcli ram policy create -p(--policy-name) policy_name
+				-d(--document)    policy.json
+				--description     "write logs"
+
+policy.json example:
+{
+    "Version": "1",
+    "Statement": [
+        {
+            "Effect": "Allow",
+            "Action": ["log:PostLogStoreLogs"],
+            "Resource": ["acs:log:*:123456:project/my-project/logstore/my-logstore"]
+        }
+    ]
+}
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyCreateRun())
+	},
+}
+
+func ramPolicyCreateRun() (interface{}, error) {
+	if *ramPolicyInput.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	if *ramPolicyInput.documentFile == "" {
+		return nil, util.NewValidationError("-d(--document) is required")
+	}
+	doc, err := readPolicyDocument(*ramPolicyInput.documentFile)
+	if err != nil {
+		return nil, err
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	desc := *ramPolicyInput.description
+	if desc == "" {
+		desc = fmt.Sprintf("create the policy %s", *ramPolicyInput.policyName)
+	}
+	resp, err := client.CreatePolicy(*ramPolicyInput.policyName, doc, desc)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Policy, nil
+}
+
+var ramPolicyGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "get ram policy",
+	Long: `
+get ram policy and its default version
+EXAMPLE:
+fcli ram policy get -p(--policy-name) policy_name
+fcli ram policy get -p(--policy-name) AliyunLogFullAccess --type System
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyGetRun())
+	},
+}
+
+func ramPolicyGetRun() (interface{}, error) {
+	if *ramPolicyInput.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetPolicy(*ramPolicyInput.policyName, *ramPolicyInput.policyType)
+	if err != nil {
+		return nil, err
+	}
+	output := struct {
+		ram.Policy
+		DefaultPolicyVersion *ramPolicyVersionOutput `json:"DefaultPolicyVersion,omitempty"`
+	}{Policy: resp.Policy}
+	if resp.Policy.DefaultVersion != "" {
+		version, err := client.GetPolicyVersion(
+			*ramPolicyInput.policyName, *ramPolicyInput.policyType, resp.Policy.DefaultVersion)
+		if err != nil {
+			return nil, err
+		}
+		v := newRAMPolicyVersionOutput(version.PolicyVersion)
+		output.DefaultPolicyVersion = &v
+	}
+	return output, nil
+}
+
+var ramPolicyListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list ram policies",
+	Long: `
+list ram policies
+EXAMPLE:
+fcli ram policy list
+fcli ram policy list --type Custom --format yaml
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyListRun(cmd))
+	},
+}
+
+func ramPolicyListRun(cmd *cobra.Command) (interface{}, error) {
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	policyType := ""
+	if cmd.Flags().Changed("type") {
+		policyType = *ramPolicyInput.policyType
+	}
+	return client.ListAllPolicies(policyType)
+}
+
+var ramPolicyDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete ram policy",
+	Long: `
+delete ram custom policy, the policy must be detached from all the roles first
+EXAMPLE:
+fcli ram policy delete -p(--policy-name) policy_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyDeleteRun())
+	},
+}
+
+func ramPolicyDeleteRun() (interface{}, error) {
+	if *ramPolicyInput.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.DeletePolicy(*ramPolicyInput.policyName)
+}
+
+var ramPolicyVersionsCmd = &cobra.Command{
+	Use:   "versions",
+	Short: "list ram policy versions",
+	Long: `
+list the versions of the ram policy with their policy documents
+EXAMPLE:
+fcli ram policy versions -p(--policy-name) policy_name
+fcli ram policy versions -p(--policy-name) policy_name --version-id v2
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyVersionsRun())
+	},
+}
+
+func ramPolicyVersionsRun() (interface{}, error) {
+	if *ramPolicyInput.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	policyName, policyType := *ramPolicyInput.policyName, *ramPolicyInput.policyType
+	if *ramPolicyInput.versionID != "" {
+		resp, err := client.GetPolicyVersion(policyName, policyType, *ramPolicyInput.versionID)
+		if err != nil {
+			return nil, err
+		}
+		return newRAMPolicyVersionOutput(resp.PolicyVersion), nil
+	}
+
+	resp, err := client.ListPolicyVersions(policyName, policyType)
+	if err != nil {
+		return nil, err
+	}
+	versions := []ramPolicyVersionOutput{}
+	for _, v := range resp.PolicyVersions.PolicyVersion {
+		versions = append(versions, newRAMPolicyVersionOutput(v))
+	}
+	return versions, nil
+}
+
+var ramPolicySetDefaultCmd = &cobra.Command{
+	Use:   "set-default",
+	Short: "set the default version of ram policy",
+	Long: `
+set the default version of ram custom policy, which takes effect immediately
+EXAMPLE:
+fcli ram policy set-default -p(--policy-name) policy_name --version-id v1
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyVersionRun(true))
+	},
+}
+
+var ramPolicyDeleteVersionCmd = &cobra.Command{
+	Use:   "delete-version",
+	Short: "delete a version of ram policy",
+	Long: `
+delete a non-default version of ram custom policy
+EXAMPLE:
+fcli ram policy delete-version -p(--policy-name) policy_name --version-id v1
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramPolicyVersionRun(false))
+	},
+}
+
+func ramPolicyVersionRun(setDefault bool) (interface{}, error) {
+	if *ramPolicyInput.policyName == "" {
+		return nil, util.NewValidationError("-p(--policy-name) is required")
+	}
+	if *ramPolicyInput.versionID == "" {
+		return nil, util.NewValidationError("--version-id is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	if setDefault {
+		return client.SetDefaultPolicyVersion(*ramPolicyInput.policyName, *ramPolicyInput.versionID)
+	}
+	return client.DeletePolicyVersion(*ramPolicyInput.policyName, *ramPolicyInput.versionID)
+}
@@ -0,0 +1,264 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+type ramRoleInputType struct {
+	roleName        *string
+	principal       *string
+	trustPolicyFile *string
+	description     *string
+	addPrincipal    *string
+	removePrincipal *string
+	updatePolicy    *string
+}
+
+var ramRoleInput ramRoleInputType
+
+// ramRoleOutput displays the role with the decoded trust policy.
+type ramRoleOutput struct {
+	RoleID                   string      `json:"RoleId"`
+	RoleName                 string      `json:"RoleName"`
+	Arn                      string      `json:"Arn"`
+	Description              string      `json:"Description"`
+	AssumeRolePolicyDocument interface{} `json:"AssumeRolePolicyDocument,omitempty"`
+	CreateDate               string      `json:"CreateDate"`
+}
+
+func newRAMRoleOutput(r ram.Role) ramRoleOutput {
+	output := ramRoleOutput{
+		RoleID:      r.RoleID,
+		RoleName:    r.RoleName,
+		Arn:         r.Arn,
+		Description: r.Description,
+		CreateDate:  r.CreateDate,
+	}
+	if r.AssumeRolePolicyDocument != "" {
+		output.AssumeRolePolicyDocument = decodePolicyDocument(r.AssumeRolePolicyDocument)
+	}
+	return output
+}
+
+func init() {
+	ramRoleCmd.AddCommand(ramRoleCreateCmd)
+	ramRoleCmd.AddCommand(ramRoleGetCmd)
+	ramRoleCmd.AddCommand(ramRoleListCmd)
+	ramRoleCmd.AddCommand(ramRoleDeleteCmd)
+	ramRoleCmd.AddCommand(ramRoleUpdateCmd)
+
+	for _, c := range []*cobra.Command{
+		ramRoleCreateCmd, ramRoleGetCmd, ramRoleListCmd, ramRoleDeleteCmd, ramRoleUpdateCmd} {
+		c.Flags().Bool("help", false, c.Short)
+	}
+
+	ramRoleInput.roleName = ramRoleCmd.PersistentFlags().StringP("role-name", "r", "", "the role name")
+	ramRoleInput.principal = ramRoleCreateCmd.Flags().String(
+		"principal", rolePrincipal, "the service which can assume the role, ignored if --trust-policy is specified")
+	ramRoleInput.trustPolicyFile = ramRoleCreateCmd.Flags().String(
+		"trust-policy", "", "the trust policy document file in json format")
+	ramRoleInput.description = ramRoleCreateCmd.Flags().String("description", "", "the role description")
+	ramRoleInput.updatePolicy = ramRoleUpdateCmd.Flags().String(
+		"trust-policy", "", "replace the trust policy with the document file in json format")
+	ramRoleInput.addPrincipal = ramRoleUpdateCmd.Flags().String(
+		"add-principal", "", "allow the service to assume the role, such as log.aliyuncs.com")
+	ramRoleInput.removePrincipal = ramRoleUpdateCmd.Flags().String(
+		"remove-principal", "", "disallow the service to assume the role")
+}
+
+var ramRoleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "create ram role",
+	Long: `
+create ram role
+EXAMPLE:
+fcli ram role create -r(--role-name) role_name
+				--principal        fc.aliyuncs.com
+fcli ram role create -r(--role-name) role_name
+				--trust-policy     trust_policy.json
+				--description      "the role of my service"
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramRoleCreateRun())
+	},
+}
+
+func ramRoleCreateRun() (interface{}, error) {
+	if *ramRoleInput.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	doc := util.AssumeRolePolicyDocument(*ramRoleInput.principal)
+	if *ramRoleInput.trustPolicyFile != "" {
+		doc, err = readPolicyDocument(*ramRoleInput.trustPolicyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+	desc := *ramRoleInput.description
+	if desc == "" {
+		desc = fmt.Sprintf("create the role %s", *ramRoleInput.roleName)
+	}
+	resp, err := client.CreateRole(*ramRoleInput.roleName, doc, desc)
+	if err != nil {
+		return nil, err
+	}
+	return newRAMRoleOutput(resp.Role), nil
+}
+
+var ramRoleGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "get ram role",
+	Long: `
+get ram role
+EXAMPLE:
+fcli ram role get -r(--role-name) role_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramRoleGetRun())
+	},
+}
+
+func ramRoleGetRun() (interface{}, error) {
+	if *ramRoleInput.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.GetRole(*ramRoleInput.roleName)
+	if err != nil {
+		return nil, err
+	}
+	return newRAMRoleOutput(resp.Role), nil
+}
+
+var ramRoleListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list ram roles",
+	Long: `
+list ram roles
+EXAMPLE:
+fcli ram role list
+fcli ram role list --format yaml
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramRoleListRun())
+	},
+}
+
+func ramRoleListRun() (interface{}, error) {
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	all, err := client.ListAllRoles()
+	if err != nil {
+		return nil, err
+	}
+	roles := []ramRoleOutput{}
+	for _, r := range all {
+		roles = append(roles, newRAMRoleOutput(r))
+	}
+	return roles, nil
+}
+
+var ramRoleDeleteCmd = &cobra.Command{
+	Use:   "delete",
+	Short: "delete ram role",
+	Long: `
+delete ram role, the policies attached to the role must be detached first
+EXAMPLE:
+fcli ram role delete -r(--role-name) role_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramRoleDeleteRun())
+	},
+}
+
+func ramRoleDeleteRun() (interface{}, error) {
+	if *ramRoleInput.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	return client.DeleteRole(*ramRoleInput.roleName)
+}
+
+var ramRoleUpdateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "update the trust policy of ram role",
+	Long: `
+update the trust policy of ram role, which decides who can assume the role
+EXAMPLE:
+fcli ram role update -r(--role-name) role_name
+				--add-principal    log.aliyuncs.com
+fcli ram role update -r(--role-name) role_name
+				--remove-principal log.aliyuncs.com
+fcli ram role update -r(--role-name) role_name
+				--trust-policy     trust_policy.json
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramRoleUpdateRun())
+	},
+}
+
+func ramRoleUpdateRun() (interface{}, error) {
+	if *ramRoleInput.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+
+	var doc string
+	if *ramRoleInput.updatePolicy != "" {
+		doc, err = readPolicyDocument(*ramRoleInput.updatePolicy)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		if *ramRoleInput.addPrincipal == "" && *ramRoleInput.removePrincipal == "" {
+			return nil, util.NewValidationError("one of --trust-policy, --add-principal and --remove-principal is required")
+		}
+		resp, err := client.GetRole(*ramRoleInput.roleName)
+		if err != nil {
+			return nil, err
+		}
+		trustPolicy, err := ram.ParseAssumeRolePolicyDocument(resp.Role.AssumeRolePolicyDocument)
+		if err != nil {
+			return nil, err
+		}
+		changed := false
+		if *ramRoleInput.addPrincipal != "" {
+			changed = trustPolicy.AddServicePrincipal(*ramRoleInput.addPrincipal) || changed
+		}
+		if *ramRoleInput.removePrincipal != "" {
+			changed = trustPolicy.RemoveServicePrincipal(*ramRoleInput.removePrincipal) || changed
+		}
+		if !changed {
+			return newRAMRoleOutput(resp.Role), nil
+		}
+		if len(trustPolicy.Statement) == 0 {
+			return nil, fmt.Errorf("no one can assume the role %s after the update", *ramRoleInput.roleName)
+		}
+		doc = trustPolicy.String()
+	}
+
+	resp, err := client.UpdateRole(*ramRoleInput.roleName, doc)
+	if err != nil {
+		return nil, err
+	}
+	return newRAMRoleOutput(resp.Role), nil
+}
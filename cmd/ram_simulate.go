@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var ramSimulateInput struct {
+	roleName *string
+	action   *string
+	resource *string
+}
+
+// ramSimulateOutput explains the decision of the simulated request.
+type ramSimulateOutput struct {
+	RoleName   string                     `json:"RoleName"`
+	Action     string                     `json:"Action"`
+	Resource   string                     `json:"Resource"`
+	Decision   string                     `json:"Decision"`
+	Reason     string                     `json:"Reason"`
+	Statements []ramSimulateMatchedOutput `json:"MatchedStatements"`
+}
+
+type ramSimulateMatchedOutput struct {
+	PolicyName     string              `json:"PolicyName"`
+	PolicyType     string              `json:"PolicyType"`
+	VersionID      string              `json:"VersionId"`
+	StatementIndex int                 `json:"StatementIndex"`
+	Statement      ram.PolicyStatement `json:"Statement"`
+	NotEvaluated   []string            `json:"NotEvaluated,omitempty"`
+}
+
+func init() {
+	ramCmd.AddCommand(ramSimulateCmd)
+	ramSimulateCmd.Flags().Bool("help", false, "simulate the request against the policies of the role")
+	ramSimulateInput.roleName = ramSimulateCmd.Flags().StringP("role-name", "r", "", "the role name")
+	ramSimulateInput.action = ramSimulateCmd.Flags().StringP("action", "a", "", "the action, such as log:PostLogStoreLogs")
+	ramSimulateInput.resource = ramSimulateCmd.Flags().String(
+		"resource", "", "the resource, such as acs:log:cn-hangzhou:123456:project/my-project/logstore/my-logstore")
+}
+
+var ramSimulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "simulate the request against the policies of the role",
+	Long: `
+simulate the request against the default versions of all the policies attached to the role,
+and explain which statements allow or deny the request. It helps to debug the Forbidden
+errors of the function before deploying. The conditions of the statements are not evaluated,
+the decision is NotEvaluated if it depends on them.
+EXAMPLE:
+fcli ram simulate -r(--role-name) role_name
+				-a(--action)    log:PostLogStoreLogs
+				--resource      acs:log:cn-hangzhou:123456:project/my-project/logstore/my-logstore
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ramPrint(ramSimulateRun())
+	},
+}
+
+func ramSimulateRun() (interface{}, error) {
+	if *ramSimulateInput.roleName == "" {
+		return nil, util.NewValidationError("-r(--role-name) is required")
+	}
+	if *ramSimulateInput.action == "" {
+		return nil, util.NewValidationError("-a(--action) is required")
+	}
+	if *ramSimulateInput.resource == "" {
+		return nil, util.NewValidationError("--resource is required")
+	}
+	client, err := newRAMClient()
+	if err != nil {
+		return nil, err
+	}
+	policies, err := getRolePolicyDocuments(client, *ramSimulateInput.roleName)
+	if err != nil {
+		return nil, err
+	}
+	evaluation := util.EvaluatePolicies(policies, *ramSimulateInput.action, *ramSimulateInput.resource)
+	return newRAMSimulateOutput(*ramSimulateInput.roleName, len(policies), evaluation), nil
+}
+
+// getRolePolicyDocuments gets the default versions of all the policies attached to the role.
+func getRolePolicyDocuments(client *ram.Client, roleName string) ([]util.NamedPolicyDocument, error) {
+	resp, err := client.ListPoliciesForRole(roleName)
+	if err != nil {
+		return nil, util.WrapErrorf(err, "failed to list policies of role %s", roleName)
+	}
+	policies := []util.NamedPolicyDocument{}
+	for _, p := range resp.Policies.Policy {
+		version, err := util.GetDefaultPolicyVersion(client, p.PolicyName, p.PolicyType)
+		if err != nil {
+			return nil, util.WrapErrorf(err, "failed to get policy %s", p.PolicyName)
+		}
+		doc, err := util.ParsePolicyDocument(version.PolicyDocument)
+		if err != nil {
+			return nil, fmt.Errorf("invalid policy document of %s: %s", p.PolicyName, err)
+		}
+		doc.PolicyName, doc.PolicyType, doc.VersionID = p.PolicyName, p.PolicyType, version.VersionID
+		policies = append(policies, doc)
+	}
+	return policies, nil
+}
+
+func newRAMSimulateOutput(roleName string, policyCount int, e *util.PolicyEvaluation) ramSimulateOutput {
+	output := ramSimulateOutput{
+		RoleName:   roleName,
+		Action:     e.Action,
+		Resource:   e.Resource,
+		Decision:   e.Decision,
+		Statements: []ramSimulateMatchedOutput{},
+	}
+	switch e.Decision {
+	case util.PolicyDecisionExplicitDeny:
+		output.Reason = fmt.Sprintf("denied explicitly by %d statement(s), deny overrides allow", len(e.Statements))
+	case util.PolicyDecisionAllow:
+		output.Reason = fmt.Sprintf("allowed by %d statement(s)", len(e.Statements))
+	case util.PolicyDecisionNotEvaluated:
+		output.Reason = fmt.Sprintf("depends on the elements of %d statement(s) which are not evaluated, "+
+			"such as Condition, the request is not regarded as allowed", len(e.Statements))
+	default:
+		output.Reason = fmt.Sprintf("no statement in the %d attached policies allows the request", policyCount)
+	}
+	for _, s := range e.Statements {
+		output.Statements = append(output.Statements, ramSimulateMatchedOutput{
+			PolicyName:     s.PolicyName,
+			PolicyType:     s.PolicyType,
+			VersionID:      s.VersionID,
+			StatementIndex: s.StatementIndex,
+			Statement:      s.Statement,
+			NotEvaluated:   s.Unevaluated,
+		})
+	}
+	return output
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"github.com/aliyun/fcli/ram"
+)
+
+func (s *FunctionStructsTestSuite) TestFormatRAMOutput() {
+	assert := s.Require()
+	role := newRAMRoleOutput(ram.Role{
+		RoleName:                 "fc-role",
+		Arn:                      "acs:ram::123456:role/fc-role",
+		AssumeRolePolicyDocument: `{"Statement":[{"Action":"sts:AssumeRole"}],"Version":"1"}`,
+	})
+
+	output, err := formatRAMOutput(role, ramOutputYAML)
+	assert.Nil(err)
+	assert.Equal(`RoleId: ""
+RoleName: fc-role
+Arn: acs:ram::123456:role/fc-role
+Description: ""
+AssumeRolePolicyDocument:
+  Statement:
+  - Action: sts:AssumeRole
+  Version: "1"
+CreateDate: ""`, output)
+
+	output, err = formatRAMOutput([]string{"a"}, ramOutputYAML)
+	assert.Nil(err)
+	assert.Equal("- a", output)
+
+	_, err = formatRAMOutput(role, "xml")
+	assert.NotNil(err)
+}
+
+func (s *FunctionStructsTestSuite) TestRAMFormatFlag() {
+	assert := s.Require()
+	// the global --output is not shadowed, so the errors of the ram commands can be printed in json
+	assert.NotNil(ramRoleCmd.InheritedFlags().Lookup("format"))
+	assert.True(ramRoleCmd.InheritedFlags().Lookup("output") == RootCmd.PersistentFlags().Lookup("output"))
+}
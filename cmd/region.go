@@ -0,0 +1,133 @@
+package cmd
+
+import (
+	"fmt"
+	"path"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var regionInput struct {
+	network *string
+	url     *string
+}
+
+// regionOutput is a region of the catalog with the endpoints of the configured account.
+type regionOutput struct {
+	Region      string `json:"Region"`
+	FC          bool   `json:"FunctionCompute"`
+	Log         bool   `json:"LogService"`
+	Endpoint    string `json:"Endpoint,omitempty"`
+	SLSEndpoint string `json:"SLSEndpoint,omitempty"`
+}
+
+func init() {
+	RootCmd.AddCommand(regionCmd)
+	regionCmd.AddCommand(regionListCmd)
+	regionCmd.AddCommand(regionRefreshCmd)
+
+	regionCmd.Flags().BoolP("help", "h", true, "Print Usage")
+	regionListCmd.Flags().Bool("help", false, "list the regions and their endpoints")
+	regionRefreshCmd.Flags().Bool("help", false, "refresh the region catalog")
+
+	regionInput.network = regionListCmd.Flags().String(
+		"network", "", "the network of the endpoints, public, internal or vpc, the one of the endpoint by default")
+	regionInput.url = regionRefreshCmd.Flags().String(
+		"url", "", "the http(s) url or the file of the json region catalog, region_catalog_url of the config by default")
+}
+
+var regionCmd = &cobra.Command{
+	Use:   "region",
+	Short: "region catalog related operation",
+	Long: `region catalog related operation
+
+The known regions are from the region catalog, which is cached in ~/.fcli/regions.json
+after it is refreshed, otherwise the catalog bundled in fcli is used.
+
+EXAMPLE:
+  fcli region list
+  fcli region list --network vpc
+  fcli region refresh --url https://example.com/fc-regions.json
+`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var regionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list the regions and their endpoints",
+	Long: `
+list the regions of the catalog, the services available in them and the fc and sls endpoints
+of the configured account. The sls endpoints of the internal and vpc networks are the intranet ones.
+EXAMPLE:
+fcli region list
+fcli region list --network internal
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(regionListRun(*regionInput.network))
+	},
+}
+
+var regionRefreshCmd = &cobra.Command{
+	Use:   "refresh",
+	Short: "refresh the region catalog",
+	Long: `
+refresh the region catalog from the json source, which is in the format of:
+{"regions": [{"id": "cn-hangzhou", "fc": true, "log": true}]}
+The catalog is validated and cached in ~/.fcli/regions.json.
+EXAMPLE:
+fcli region refresh --url https://example.com/fc-regions.json
+fcli config set region_catalog_url https://example.com/fc-regions.json && fcli region refresh
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		source := *regionInput.url
+		if source == "" {
+			source = gConfig.RegionCatalogURL
+		}
+		if source == "" {
+			return util.NewValidationError("the source of the region catalog is required").
+				WithHint("specify it by --url or region_catalog_url of the config")
+		}
+		c, err := util.RefreshRegionCatalog(source, path.Join(gConfigDir, util.RegionCatalogFile))
+		if err != nil {
+			return fmt.Errorf("Failed to refresh the region catalog. Error: %v", err)
+		}
+		fmt.Printf("Refreshed the region catalog with %d regions, %d of them have function compute.\n",
+			len(c.Regions), len(c.FCRegions()))
+		return nil
+	},
+}
+
+func regionListRun(network string) (interface{}, error) {
+	accountID := ""
+	if info, err := util.ParseEndpoint(gConfig.Endpoint); err == nil {
+		accountID = info.AccountID
+		if network == "" {
+			network = info.Network
+		}
+	}
+	switch network {
+	case "":
+		network = util.NetworkPublic
+	case util.NetworkPublic, util.NetworkInternal, util.NetworkVPC:
+	default:
+		return nil, util.NewValidationError("unsupported network %q, expect public, internal or vpc", network)
+	}
+
+	rows := []regionOutput{}
+	for _, r := range util.CurrentRegionCatalog().Regions {
+		row := regionOutput{Region: r.ID, FC: r.FC, Log: r.Log}
+		if r.FC && accountID != "" {
+			row.Endpoint = util.FCEndpoint(accountID, r.ID, network)
+		}
+		if r.Log {
+			row.SLSEndpoint = util.SLSEndpoint(r.ID, network)
+		}
+		rows = append(rows, row)
+	}
+	return struct {
+		Regions []regionOutput `json:"Regions"`
+	}{rows}, nil
+}
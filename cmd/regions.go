@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"encoding/json"
+	"strings"
+	"sync"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// regionsInput is the flags to run a list command in several regions.
+type regionsInput struct {
+	all     *bool
+	regions *[]string
+}
+
+// regionError is the failure in a region, which does not fail the other regions.
+type regionError struct {
+	Region string `json:"Region"`
+	Error  string `json:"Error"`
+}
+
+func addRegionsFlags(cmd *cobra.Command) *regionsInput {
+	return &regionsInput{
+		all: cmd.Flags().Bool("all-regions", false, "list in all the regions concurrently"),
+		regions: cmd.Flags().StringSlice(
+			"regions", nil, "list in the regions concurrently, such as cn-hangzhou,cn-shanghai"),
+	}
+}
+
+// selected returns the regions to list in, it is empty if neither flag is specified.
+func (r *regionsInput) selected() ([]string, error) {
+	if *r.all {
+		return util.GetRegions(), nil
+	}
+	for _, region := range *r.regions {
+		if err := util.ValidateRegion(region); err != nil {
+			return nil, err
+		}
+	}
+	return *r.regions, nil
+}
+
+// forEachRegion runs fn with the fc client of each region concurrently. The results are in the
+// order of the regions and nil for the failed ones.
+func forEachRegion(regions []string, fn func(client *util.FClient, region string) (interface{}, error)) ([]interface{}, []regionError) {
+	results := make([]interface{}, len(regions))
+	errs := make([]error, len(regions))
+	// the configs are built before the goroutines start, they share the credential provider of gConfig
+	configs := make([]*util.GlobalConfig, len(regions))
+	for i, region := range regions {
+		configs[i], errs[i] = gConfig.ForRegion(region)
+	}
+	var wg sync.WaitGroup
+	for i, region := range regions {
+		if errs[i] != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, region string) {
+			defer wg.Done()
+			client, err := util.NewFClient(configs[i])
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			results[i], errs[i] = fn(client, region)
+		}(i, region)
+	}
+	wg.Wait()
+
+	failures := []regionError{}
+	for i, err := range errs {
+		if err != nil {
+			failures = append(failures, regionError{Region: regions[i], Error: util.WrapError(err).Error()})
+		}
+	}
+	return results, failures
+}
+
+// listInRegions lists the items in the regions and merges them with the region column,
+// the output is keyed by the name of the items and Errors for the failed regions.
+func listInRegions(regions []string, key string, list func(client *util.FClient) ([]interface{}, error)) interface{} {
+	results, failures := forEachRegion(regions, func(client *util.FClient, region string) (interface{}, error) {
+		return list(client)
+	})
+	rows := []map[string]interface{}{}
+	for i, result := range results {
+		items, _ := result.([]interface{})
+		for _, item := range items {
+			rows = append(rows, withRegion(regions[i], item))
+		}
+	}
+	output := map[string]interface{}{key: rows}
+	if len(failures) > 0 {
+		output["Errors"] = failures
+	}
+	return output
+}
+
+// nameOnlyItems converts the items to the json objects with only the name field.
+func nameOnlyItems(items []interface{}, field string) ([]interface{}, error) {
+	objects := []map[string]interface{}{}
+	if err := convertItems(items, &objects); err != nil {
+		return nil, err
+	}
+	key := strings.ToLower(field[:1]) + field[1:]
+	names := []interface{}{}
+	for _, o := range objects {
+		names = append(names, map[string]interface{}{field: o[key]})
+	}
+	return names, nil
+}
+
+// withRegion converts the item to a json object with the Region field.
+func withRegion(region string, item interface{}) map[string]interface{} {
+	row := map[string]interface{}{}
+	if b, err := json.Marshal(item); err == nil {
+		json.Unmarshal(b, &row)
+	}
+	row["Region"] = region
+	return row
+}
+
+// listAllServices lists all the pages of the services with the prefix.
+func listAllServices(client *util.FClient, prefix string) ([]interface{}, error) {
+	items := []interface{}{}
+	input := fc.NewListServicesInput().WithPrefix(prefix).WithLimit(100)
+	for {
+		resp, err := client.ListServices(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range resp.Services {
+			items = append(items, s)
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			return items, nil
+		}
+		input.WithNextToken(*resp.NextToken)
+	}
+}
+
+// listAllFunctions lists all the pages of the functions of the service with the prefix.
+func listAllFunctions(client *util.FClient, serviceName, prefix, qualifier string) ([]interface{}, error) {
+	items := []interface{}{}
+	input := fc.NewListFunctionsInput(serviceName).WithPrefix(prefix).WithLimit(100)
+	if qualifier != "" {
+		input.WithQualifier(qualifier)
+	}
+	for {
+		resp, err := client.ListFunctions(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Functions {
+			items = append(items, f)
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			return items, nil
+		}
+		input.WithNextToken(*resp.NextToken)
+	}
+}
+
+// listAllTriggers lists all the pages of the triggers of the function with the prefix.
+func listAllTriggers(client *util.FClient, serviceName, functionName, prefix string) ([]interface{}, error) {
+	items := []interface{}{}
+	input := fc.NewListTriggersInput(serviceName, functionName).WithPrefix(prefix).WithLimit(100)
+	for {
+		resp, err := client.ListTriggers(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, t := range resp.Triggers {
+			items = append(items, t)
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			return items, nil
+		}
+		input.WithNextToken(*resp.NextToken)
+	}
+}
+
+// listAllAliases lists all the pages of the aliases of the service with the prefix.
+func listAllAliases(client *util.FClient, serviceName, prefix string) ([]interface{}, error) {
+	items := []interface{}{}
+	limit := int32(100)
+	input := &fc.ListAliasesInput{ServiceName: &serviceName, Prefix: &prefix, Limit: &limit}
+	for {
+		resp, err := client.ListAliases(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Aliases {
+			items = append(items, a)
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			return items, nil
+		}
+		input.NextToken = resp.NextToken
+	}
+}
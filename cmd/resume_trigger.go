@@ -0,0 +1,93 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var resumeTriggerAllFunctions *bool
+
+func init() {
+	triggerCmd.AddCommand(resumeTriggerCmd)
+	resumeTriggerCmd.Flags().Bool("help", false, "resume triggers")
+	resumeTriggerAllFunctions = resumeTriggerCmd.Flags().Bool(
+		"all-functions", false, "resume the paused triggers of all the functions in the service")
+}
+
+var resumeTriggerCmd = &cobra.Command{
+	Use:   "resume",
+	Short: "enable the triggers disabled by pause",
+	Long: `
+enable the triggers recorded by "fcli trigger pause" again.
+the triggers which were already disabled before pause are left untouched.
+EXAMPLE:
+fcli trigger resume -s(--service-name)  service_name
+				-f(--function-name) function_name
+fcli trigger resume -s(--service-name)  service_name
+				--all-functions
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(resumeTriggerRun(cmd))
+	},
+}
+
+func resumeTriggerRun(cmd *cobra.Command) (*string, error) {
+	if serviceName == "" {
+		return nil, util.NewValidationError("service name is required")
+	}
+	if !*resumeTriggerAllFunctions && functionName == "" {
+		return nil, util.NewValidationError("function name is required unless --all-functions is specified")
+	}
+	client, err := util.NewFClient(gConfig)
+	if err != nil {
+		return nil, err
+	}
+	state, err := loadPausedTriggers()
+	if err != nil {
+		return nil, err
+	}
+
+	key := pausedTriggersKey(serviceName)
+	remains, lines, resumeErr := resumeTimerTriggers(client, state[key], functionName, *resumeTriggerAllFunctions)
+	state[key] = remains
+	if err := savePausedTriggers(state); err != nil {
+		return nil, err
+	}
+	if resumeErr != nil {
+		return nil, resumeErr
+	}
+	lines = append(lines, fmt.Sprintf("%d trigger(s) resumed in service %s", countLines(lines, "resumed "), serviceName))
+	output := strings.Join(lines, "\n")
+	return &output, nil
+}
+
+// resumeTimerTriggers enables the paused triggers of the function, or all the functions, and returns
+// the triggers which remain paused. The triggers deleted after they were paused are dropped. It stops
+// at the first failure, the failed and the rest triggers remain paused so that they can be resumed again.
+func resumeTimerTriggers(client timerTriggerClient, paused []pausedTrigger, functionName string,
+	allFunctions bool) ([]pausedTrigger, []string, error) {
+	var lines []string
+	var remains []pausedTrigger
+	var resumeErr error
+	for _, t := range paused {
+		if resumeErr != nil || (!allFunctions && t.FunctionName != functionName) {
+			remains = append(remains, t)
+			continue
+		}
+		_, err := setTimerTriggerEnable(client, t.FunctionName, t.TriggerName, true)
+		if util.ClassOf(err) == util.ErrorClassNotFound {
+			lines = append(lines, fmt.Sprintf("skip %s/%s: not found", t.FunctionName, t.TriggerName))
+			continue
+		}
+		if err != nil {
+			resumeErr = fmt.Errorf("failed to resume %s/%s: %v", t.FunctionName, t.TriggerName, err)
+			remains = append(remains, t)
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("resumed %s/%s", t.FunctionName, t.TriggerName))
+	}
+	return remains, lines, resumeErr
+}
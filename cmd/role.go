@@ -1,9 +1,11 @@
 package cmd
 
 import (
+	"encoding/json"
 	"strings"
 
 	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
 
 	"fmt"
 
@@ -11,18 +13,18 @@ import (
 )
 
 func init() {
-	//RootCmd.AddCommand(roleCmd)
+	RootCmd.AddCommand(roleCmd)
 }
 
 const (
-	ramEndpoint       = "https://ram.aliyuncs.com"
 	roleType          = "Service"
 	rolePrincipal     = "fc.aliyuncs.com"
 	postLogAction     = "PostLogStoreLogs"
 	getlogStoreAction = "GetLogStore"
 	getObjectAction   = "GetObject"
-	loggingPolicyFmt  = "fc-%s-logging-%v"
-	ossPolicyFmt      = "fc-%s-oss-%v"
+	managedPolicyFmt  = "fc-%s-policy"
+	loggingPolicyFmt  = "fc-%s-logging-"
+	ossPolicyFmt      = "fc-%s-oss-"
 	roleArnFmt        = "acs:ram::%s:role/%s"
 )
 
@@ -34,6 +36,7 @@ var roleCmd = &cobra.Command{
 	
 EXAMPLE:
   fcli role config ......
+  fcli role grant --scenario vpc -s service_name -r role_name
 	`,
 	Run: func(cmd *cobra.Command, args []string) {
 
@@ -45,51 +48,77 @@ func init() {
 }
 
 // Some util functions for role
-func getRAMClient(accessKeyID, accessKeySecret string) (*ram.Client, error) {
-	client, err := ram.NewClient(ramEndpoint, accessKeyID, accessKeySecret)
-	if err != nil {
-		return nil, fmt.Errorf("get ram client err: %s", err)
-	}
-	return client, nil
+func getRAMClient() (*ram.Client, error) {
+	return util.NewRAMClient(gConfig)
 }
 
-func createOSSPolicy(client *ram.Client, uid, accessKeyID, accessKeySecret, policyName, bucket string) error {
+func ossPolicyStatement(uid, bucket string) ram.PolicyStatement {
 	// acs:oss:*:uid:bucketName/*
-	temp := `{"Version": "1", "Statement": [{"Effect": "Allow", "Action": ["oss:%s"], "Resource": ["acs:oss:*:%s:%s/*"]}]}`
-	doc := fmt.Sprintf(temp, getObjectAction, uid, bucket)
-	_, err := client.CreatePolicy(policyName, doc, "fc cli OSS copy code policy")
-	if err != nil {
-		return fmt.Errorf("failed to create policy %s due to %s", policyName, err)
+	return ram.PolicyStatement{
+		Effect:   "Allow",
+		Action:   []string{"oss:" + getObjectAction},
+		Resource: []string{fmt.Sprintf("acs:oss:*:%s:%s/*", uid, bucket)},
 	}
-	return nil
 }
 
-func createLoggingPolicy(client *ram.Client, accessKeyID, accessKeySecret, policyName, uid, project, logstore string) error {
-	// return policyName
+func loggingPolicyStatement(uid, project, logstore string) ram.PolicyStatement {
 	// acs:log:*:uid:project/projectName/logstore/*
 	// acs:log:*:uid:project/projectName/logstore/logstoreName
 	if logstore == "" {
 		logstore = "*"
 	}
-	temp := `{"Version": "1", "Statement": [{"Effect": "Allow", "Action": ["log:%s"], "Resource": ["acs:log:*:%s:project/%s/logstore/%s"]}]}`
-	doc := fmt.Sprintf(temp, postLogAction, uid, project, logstore)
+	return ram.PolicyStatement{
+		Effect:   "Allow",
+		Action:   []string{"log:" + postLogAction},
+		Resource: []string{fmt.Sprintf("acs:log:*:%s:project/%s/logstore/%s", uid, project, logstore)},
+	}
+}
 
-	_, err := client.CreatePolicy(policyName, doc, "fc cli logging policy")
+func createPolicy(client *ram.Client, policyName string, statements []ram.PolicyStatement) error {
+	doc, err := json.Marshal(ram.PolicyDocument{Version: "1", Statement: statements})
+	if err != nil {
+		return err
+	}
+	_, err = client.CreatePolicy(policyName, string(doc), "fc cli role config policy")
 	if err != nil {
-		return fmt.Errorf("failed to create policy %s due to :%s", policyName, err)
+		return util.WrapErrorf(err, "failed to create policy %s", policyName)
 	}
 	return nil
 }
 
+// findManagedPolicy returns the policy created by fcli and attached to the role.
+// The policies created by the old versions of fcli, one for each run, are also recognized.
+func findManagedPolicy(client *ram.Client, roleName string) (string, error) {
+	resp, err := client.ListPoliciesForRole(roleName)
+	if err != nil {
+		return "", util.WrapErrorf(err, "failed to list policies of role %s", roleName)
+	}
+	return pickManagedPolicy(resp.Policies.Policy, roleName), nil
+}
+
+func pickManagedPolicy(policies []ram.Policy, roleName string) string {
+	legacy := ""
+	for _, p := range policies {
+		if p.PolicyType != "Custom" {
+			continue
+		}
+		if p.PolicyName == fmt.Sprintf(managedPolicyFmt, roleName) {
+			return p.PolicyName
+		}
+		if legacy == "" && (strings.HasPrefix(p.PolicyName, fmt.Sprintf(loggingPolicyFmt, roleName)) ||
+			strings.HasPrefix(p.PolicyName, fmt.Sprintf(ossPolicyFmt, roleName))) {
+			legacy = p.PolicyName
+		}
+	}
+	return legacy
+}
+
 func isRoleExist(client *ram.Client, roleName string) (bool, error) {
 	_, err := client.GetRole(roleName)
+	if ram.IsNotFound(err) {
+		return false, nil
+	}
 	if err != nil {
-		if value, ok := err.(ram.ServiceError); ok {
-			if value.ErrorCode == "EntityNotExist.Role" {
-				return false, nil
-			}
-			return false, err
-		}
 		return false, err
 	}
 	return true, nil
@@ -101,7 +130,7 @@ func createRole(client *ram.Client, accessKeyID, accessKeySecret, roleName strin
 	doc := fmt.Sprintf(temp, roleType, rolePrincipal)
 	resp, err := client.CreateRole(roleName, doc, "fc cli role")
 	if err != nil {
-		return "", fmt.Errorf("failed to create role %s due to:%s", roleName, err)
+		return "", util.WrapErrorf(err, "failed to create role %s", roleName)
 	}
 	return resp.Role.Arn, nil
 }
@@ -109,7 +138,7 @@ func createRole(client *ram.Client, accessKeyID, accessKeySecret, roleName strin
 func attachPolicyToRole(client *ram.Client, policyName string, roleName string) error {
 	_, err := client.AttachPolicyToRole("Custom", policyName, roleName)
 	if err != nil {
-		return fmt.Errorf("failed to attach policy %s to %s, due to %s", policyName, roleName, err)
+		return util.WrapErrorf(err, "failed to attach policy %s to %s", policyName, roleName)
 	}
 	return nil
 }
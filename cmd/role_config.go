@@ -2,9 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"time"
 
 	"github.com/aliyun/fcli/ram"
+	"github.com/aliyun/fcli/util"
 
 	"github.com/spf13/cobra"
 )
@@ -43,23 +43,25 @@ roleArn:  acs:ram::1234567:role/demo-role
 -b(--bucket):    oss授权的必选参数
 
 -r(--role-name): 两种授权场景的必选参数，如果role存在则在role上增加新的权限，如果role不存在则创建role并增加新的权限
-每次执行命令时根据场景(logging和oss）生成新的policy
+每次执行命令时根据场景(logging和oss）生成的权限合并到fcli管理的policy中，作为policy的新版本，
+已经授权的资源不会重复添加，policy版本数达到上限时删除最旧的非默认版本
 
-默认生成的policyName的格式为:
-fc-{roleName}-logging-{timestamp}
-fc-{roleName}-oss-{timestamp}
+fcli管理的policyName的格式为:
+fc-{roleName}-policy
+如果role上已经绑定了旧版本fcli生成的 fc-{roleName}-logging-{timestamp} 或 fc-{roleName}-oss-{timestamp}，
+则合并到该policy中
 		`,
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		roleArn, policyNameList, err := roleConfigRun()
 		if err != nil {
-			fmt.Println(err)
-		} else {
-			fmt.Println("roleArn:", roleArn)
-			fmt.Println("policyNameList:")
-			for _, v := range policyNameList {
-				fmt.Println(v)
-			}
+			return err
 		}
+		fmt.Println("roleArn:", roleArn)
+		fmt.Println("policyNameList:")
+		for _, v := range policyNameList {
+			fmt.Println(v)
+		}
+		return nil
 	},
 }
 
@@ -72,81 +74,76 @@ func roleConfigRun() (string, []string, error) {
 		return "", nil, err
 	}
 	if isEmpty(roleConfig.roleName) {
-		return "", nil, fmt.Errorf("-r(--role-name) is required")
+		return "", nil, util.NewValidationError("-r(--role-name) is required")
 	}
 
 	uid := getUserID(gConfig.Endpoint)
 	accessKeyID := gConfig.AccessKeyID
 	accessKeySecret := gConfig.AccessKeySecret
 	roleName := *roleConfig.roleName
-	client, err := getRAMClient(accessKeyID, accessKeySecret)
+	client, err := getRAMClient()
 	if err != nil {
-		return "", nil, fmt.Errorf("get ram client failed due to %s", err)
+		return "", nil, util.WrapErrorf(err, "get ram client failed")
 	}
 
 	roleExist, err := isRoleExist(client, roleName)
 	if err != nil {
-		return "", nil, fmt.Errorf("check role %s exist fail due to %s", roleName, err)
+		return "", nil, util.WrapErrorf(err, "check role %s exist fail", roleName)
 	}
 
 	if !roleExist {
 		roleArn, err = createRole(client, accessKeyID, accessKeySecret, roleName)
 		if err != nil {
-			return "", nil, fmt.Errorf("create role %s fail due to %s", roleName, err)
+			return "", nil, err
 		}
 	} else {
 		// role已经存在
 		roleArn = getRoleArn(uid, roleName)
 	}
 
+	statements := []ram.PolicyStatement{}
 	// logging授权
 	if !isEmpty(roleConfig.project) {
-		loggingPolicy, err := loggingRoleConfig(client, uid, accessKeyID, accessKeySecret, *roleConfig.project, *roleConfig.logstore, roleName)
-		if err != nil {
-			return "", nil, err
-		}
-		policyNameList = append(policyNameList, loggingPolicy)
+		statements = append(statements, loggingPolicyStatement(uid, *roleConfig.project, *roleConfig.logstore))
 	}
-
 	// oss授权
 	if !isEmpty(roleConfig.bucket) {
-		ossCopyCodePolicy, err := ossRoleConfig(client, uid, accessKeyID, accessKeySecret, *roleConfig.bucket, roleName)
-		if err != nil {
-			return "", nil, err
-		}
-		policyNameList = append(policyNameList, ossCopyCodePolicy)
+		statements = append(statements, ossPolicyStatement(uid, *roleConfig.bucket))
 	}
-
-	return roleArn, policyNameList, nil
-}
-
-func ossRoleConfig(client *ram.Client, uid, accessKeyID, accessKeySecret, bucket, roleName string) (string, error) {
-	ossPolicyName := fmt.Sprintf(ossPolicyFmt, roleName, time.Now().Unix())
-	err := createOSSPolicy(client, uid, accessKeyID, accessKeySecret, ossPolicyName, bucket)
-	if err != nil {
-		return "", err
+	if len(statements) == 0 {
+		return roleArn, policyNameList, nil
 	}
 
-	err = attachPolicyToRole(client, ossPolicyName, roleName)
+	policyName, err := managedRoleConfig(client, roleName, statements)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
-	return ossPolicyName, nil
+	policyNameList = append(policyNameList, policyName)
+	return roleArn, policyNameList, nil
 }
 
-func loggingRoleConfig(client *ram.Client, uid, accessKeyID, accessKeySecret, project, logstore, roleName string) (string, error) {
-	loggingPolicyName := fmt.Sprintf(loggingPolicyFmt, roleName, time.Now().Unix())
-	err := createLoggingPolicy(client, accessKeyID, accessKeySecret, loggingPolicyName, uid, project, logstore)
+// managedRoleConfig merges the statements into the fcli managed policy of the role,
+// the policy is created and attached to the role if it does not exist.
+func managedRoleConfig(client *ram.Client, roleName string, statements []ram.PolicyStatement) (string, error) {
+	policyName, err := findManagedPolicy(client, roleName)
 	if err != nil {
 		return "", err
 	}
-
-	err = attachPolicyToRole(client, loggingPolicyName, roleName)
-	if err != nil {
-		return "", err
+	if policyName == "" {
+		policyName = fmt.Sprintf(managedPolicyFmt, roleName)
+		if err := createPolicy(client, policyName, statements); err != nil {
+			return "", err
+		}
+		if err := attachPolicyToRole(client, policyName, roleName); err != nil {
+			return "", err
+		}
+		return policyName, nil
 	}
 
-	return loggingPolicyName, nil
+	if _, err := util.MergePolicyStatements(client, policyName, statements...); err != nil {
+		return "", util.WrapErrorf(err, "failed to update policy %s", policyName)
+	}
+	return policyName, nil
 }
 
 func init() {
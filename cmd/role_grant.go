@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var roleGrantInput struct {
+	scenario     *string
+	serviceName  *string
+	functionName *string
+	triggerName  *string
+	roleName     *string
+	policyName   *string
+	params       *[]string
+}
+
+func init() {
+	roleCmd.AddCommand(roleGrantCmd)
+	roleGrantCmd.Flags().Bool("help", false, "grant the permission of the scenario")
+	roleGrantInput.scenario = roleGrantCmd.Flags().String(
+		"scenario", "", "the grant scenario, one of "+strings.Join(grantScenarioNames(), ", "))
+	roleGrantInput.serviceName = roleGrantCmd.Flags().StringP("service-name", "s", "", "the service name")
+	roleGrantInput.functionName = roleGrantCmd.Flags().StringP(
+		"function-name", "f", "", "the function name, for the trigger scenarios")
+	roleGrantInput.triggerName = roleGrantCmd.Flags().StringP(
+		"trigger-name", "t", "", "the trigger which uses the role as its invocation role, for the trigger scenarios")
+	roleGrantInput.roleName = roleGrantCmd.Flags().StringP("role-name", "r", "", "the role name")
+	roleGrantInput.policyName = roleGrantCmd.Flags().StringP(
+		"policy-name", "p", "", "the policy name, default fcli-{scenario}-{role}")
+	roleGrantInput.params = roleGrantCmd.Flags().StringArray(
+		"param", []string{}, "the param of the scenario in key=value, prompted if it is missing")
+}
+
+var roleGrantCmd = &cobra.Command{
+	Use:     "grant",
+	Aliases: []string{"g"},
+	Short:   "grant the permission of the scenario",
+	Long: `
+grant the permission of the scenario to the role, and set the role of the service,
+or the invocation role of the trigger. The role and the policy are created if they
+do not exist, and the permission is merged into the existing policy.
+EXAMPLE:
+fcli role grant --scenario vpc -s(--service-name) service_name -r(--role-name) role_name
+fcli role grant --scenario logstore -s service_name -r role_name
+				--param project=my-project --param logstore=my-logstore
+fcli role grant --scenario mns-trigger -s service_name -f function_name -t trigger_name
+				-r(--role-name) role_name
+` + grantScenarioUsage(),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		roleARN, err := roleGrantRun()
+		if err != nil {
+			return err
+		}
+		fmt.Println("roleArn:", roleARN)
+		return nil
+	},
+}
+
+func grantScenarioUsage() string {
+	lines := []string{"SCENARIOS:"}
+	for _, s := range grantScenarios {
+		keys := []string{}
+		for _, p := range s.Params {
+			keys = append(keys, p.Key)
+		}
+		line := fmt.Sprintf("%-20s%s", s.Name, s.Description)
+		if len(keys) > 0 {
+			line += " params: " + strings.Join(keys, ", ")
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseGrantParams parses the params in key=value.
+func parseGrantParams(params []string) (map[string]string, error) {
+	values := map[string]string{}
+	for _, p := range params {
+		kv := strings.SplitN(p, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, util.NewValidationError("invalid param %s, expect key=value", p)
+		}
+		values[kv[0]] = kv[1]
+	}
+	return values, nil
+}
+
+func roleGrantRun() (string, error) {
+	scenario := findGrantScenario(*roleGrantInput.scenario)
+	if scenario == nil {
+		return "", util.NewValidationError("invalid --scenario %q, expect one of %s",
+			*roleGrantInput.scenario, strings.Join(grantScenarioNames(), ", "))
+	}
+	if *roleGrantInput.serviceName == "" {
+		return "", util.NewValidationError("-s(--service-name) is required")
+	}
+	if *roleGrantInput.roleName == "" {
+		return "", util.NewValidationError("-r(--role-name) is required")
+	}
+	params, err := parseGrantParams(*roleGrantInput.params)
+	if err != nil {
+		return "", err
+	}
+	if *roleGrantInput.functionName != "" {
+		params["function"] = *roleGrantInput.functionName
+	}
+	if missing := scenario.missingGrantParams(params); len(missing) > 0 && !isInteractive() {
+		flags := []string{}
+		for _, key := range missing {
+			flags = append(flags, "--param "+key+"=<value>")
+		}
+		return "", util.NewValidationError("the grant scenario %s requires %s in the non-interactive mode",
+			scenario.Name, strings.Join(flags, " "))
+	}
+	if err := scenario.promptGrantParams(bufio.NewReader(os.Stdin), params); err != nil {
+		return "", err
+	}
+	policyName := *roleGrantInput.policyName
+	if policyName == "" {
+		policyName = ramName("fcli-" + scenario.Name + "-" + *roleGrantInput.roleName)
+	}
+
+	ramCli, err := newRAMClient()
+	if err != nil {
+		return "", err
+	}
+	client, err := util.NewFClient(gConfig)
+	if err != nil {
+		return "", err
+	}
+	return scenario.grant(ramCli, client, grantTarget{
+		ServiceName:  *roleGrantInput.serviceName,
+		FunctionName: *roleGrantInput.functionName,
+		TriggerName:  *roleGrantInput.triggerName,
+		RoleName:     *roleGrantInput.roleName,
+		PolicyName:   policyName,
+		Params:       params,
+	})
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/aliyun/fcli/ram"
+)
+
+func (s *FunctionStructsTestSuite) TestPickManagedPolicy() {
+	assert := s.Require()
+	policies := []ram.Policy{
+		{PolicyName: "AliyunLogFullAccess", PolicyType: "System"},
+		{PolicyName: "fc-demo-role-logging-1500000000", PolicyType: "Custom"},
+		{PolicyName: "fc-other-role-policy", PolicyType: "Custom"},
+	}
+	assert.Equal("fc-demo-role-logging-1500000000", pickManagedPolicy(policies, "demo-role"))
+
+	policies = append(policies, ram.Policy{PolicyName: "fc-demo-role-policy", PolicyType: "Custom"})
+	assert.Equal("fc-demo-role-policy", pickManagedPolicy(policies, "demo-role"))
+	assert.Equal("", pickManagedPolicy(policies, "new-role"))
+}
@@ -24,8 +24,12 @@ type answer struct {
 func (a *answer) build(config *util.GlobalConfig) {
 	config.AccessKeyID = a.AccessKeyID
 	config.AccessKeySecret = a.AccessKeySecret
-	config.Endpoint = fmt.Sprintf(util.EndpointFmt, a.AccountID, a.Region)
-	config.SLSEndpoint = fmt.Sprintf(util.LogEndpointFmt, util.GetRegionNoForSLSEndpoint(config.Endpoint))
+	network := util.NetworkPublic
+	if info, err := util.ParseEndpoint(config.Endpoint); err == nil {
+		network = info.Network
+	}
+	config.Endpoint = util.FCEndpoint(a.AccountID, a.Region, network)
+	config.SLSEndpoint = util.SLSEndpointForEndpoint(config.Endpoint)
 }
 
 // TODO: Replace all other config with gConfig.
@@ -33,16 +37,100 @@ var gConfig *util.GlobalConfig
 var gConfigDir string
 var gConfigPath string
 
+// the sources of the effective config values, which are displayed by config --display
+const (
+	configSourceDefault = "default"
+	configSourceFile    = "file"
+	configSourceProject = "project file"
+	configSourceEnv     = "env"
+	configSourceFlag    = "flag"
+	configSourceReplay  = "cassette"
+)
+
+// gConfigSources records where each effective config value came from, keyed by the config key.
+var gConfigSources map[string]string
+
+// gProjectConfigPath is the path of the project config file merged into the config.
+var gProjectConfigPath string
+
+// the global flags to target another endpoint for a single command
+var globalInput struct {
+	region    string
+	accountID string
+	endpoint  string
+}
+
+// the global flags to trace, record or replay the http requests
+var debugInput struct {
+	debug  bool
+	file   string
+	record string
+	replay string
+}
+
+// commandStarted is set when the flags and the arguments are parsed, the errors before it are the usage errors.
+var commandStarted bool
+
+// the output format of the errors, the errors are printed as json envelopes with --output json
+var outputFormat string
+
+const (
+	outputFormatText = "text"
+	outputFormatJSON = "json"
+)
+
+// nonInteractive disables the prompts, which is also enabled by CI=true or when stdin is not a terminal.
+var nonInteractive bool
+
 //RootCmd is the root, which is root of all the command variable names
 var RootCmd = &cobra.Command{
 	Use:   "fcli",
 	Short: "fcli: function compute command line tools",
-	Long:  `fcli: function compute command line tools`,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		if !checkConfigCommandWithoutFlags(cmd) && !checkConfigRequredExist() {
-			readConfig()
-			initConfig()
+	Long: `fcli: function compute command line tools
+
+EXIT CODES:
+  0  success
+  1  unknown error
+  2  validation error, such as the invalid flags, arguments or config
+  3  auth error, such as the invalid access key or the missing permissions
+  4  not found, such as the service or the function does not exist
+  5  conflict, such as the resource already exists or the etag does not match
+  6  throttled after the retries
+
+The errors are printed to stderr, as json envelopes with --output json.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		commandStarted = true
+		if err := applyOutputFlag(cmd); err != nil {
+			return err
+		}
+		if err := applyDebugFlags(cmd); err != nil {
+			return err
+		}
+		if err := applyConfigOverrides(cmd); err != nil {
+			return err
+		}
+		if isConfigCommand(cmd) || isGeneratingSkeleton(cmd) {
+			return nil
+		}
+		pickupSecretsFromConfigFile()
+		if checkConfigRequredExist() {
+			return nil
 		}
+		if !isInteractive() {
+			return missingConfigError()
+		}
+		if err := readConfig(); err != nil {
+			return err
+		}
+		// initConfig reloads the config written by readConfig, the flags override it again
+		debug := gConfig.Debug
+		initConfig()
+		gConfig.Debug = debug
+		if err := applyConfigOverrides(cmd); err != nil {
+			return err
+		}
+		pickupSecretsFromConfigFile()
+		return nil
 	},
 	Run: func(cmd *cobra.Command, args []string) {
 		if len(args) == 0 {
@@ -54,19 +142,83 @@ var RootCmd = &cobra.Command{
 
 //Execute method is entrance of this program package
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
-		os.Exit(-1)
+	cmd, err := RootCmd.ExecuteC()
+	if err != nil {
+		if !commandStarted {
+			// the flags or the arguments are invalid
+			err = util.NewValidationError("%v", err).WithHint(fmt.Sprintf("run '%s --help' for the usage", cmd.CommandPath()))
+		}
+		os.Exit(printError(cmd, err))
 	}
 }
 
 func init() {
+	RootCmd.PersistentFlags().BoolVar(&nonInteractive, "non-interactive", false,
+		"never prompt, fail if the required config is missing, also enabled by CI=true")
+	RootCmd.PersistentFlags().StringVar(&globalInput.region, "region", "", "the region of this command, such as cn-shanghai")
+	RootCmd.PersistentFlags().StringVar(&globalInput.accountID, "account-id", "", "the account id of this command")
+	RootCmd.PersistentFlags().StringVar(&globalInput.endpoint, "endpoint", "", "the fc endpoint of this command")
+	RootCmd.PersistentFlags().BoolVar(&debugInput.debug, "debug", false,
+		"log the http requests and responses with the secrets redacted, the debug of the config by default")
+	RootCmd.PersistentFlags().StringVar(&debugInput.file, "debug-file", "", "write the debug log to the file instead of stderr")
+	RootCmd.PersistentFlags().StringVar(&debugInput.record, "record", "",
+		"record the http interactions to the cassette file with the secrets redacted")
+	RootCmd.PersistentFlags().StringVar(&debugInput.replay, "replay", "",
+		"replay the http interactions from the cassette file without the network")
+	RootCmd.PersistentFlags().StringVar(&outputFormat, "output", outputFormatText,
+		"output format, text or json, the errors are printed as json envelopes with json")
+	RootCmd.PersistentFlags().SetAnnotation("output", outputFormatAnnotation, []string{"true"})
+	RootCmd.SilenceErrors = true
+	RootCmd.SilenceUsage = true
 	initConfig()
 }
 
-func readConfig() {
+// isInteractive returns whether fcli can prompt for the input.
+func isInteractive() bool {
+	if nonInteractive {
+		return false
+	}
+	if ci := strings.ToLower(os.Getenv("CI")); ci == "true" || ci == "1" {
+		return false
+	}
+	fi, err := os.Stdin.Stat()
+	if err != nil || fi.Mode()&os.ModeCharDevice == 0 {
+		return false
+	}
+	// the null device is a character device but not a terminal
+	if null, err := os.Stat(os.DevNull); err == nil && os.SameFile(fi, null) {
+		return false
+	}
+	return true
+}
+
+// missingConfigError lists the config keys and the environment variables which are missing.
+func missingConfigError() error {
+	missing := []string{}
+	if !util.HasCredentialSource(gConfig) {
+		if gConfig.AccessKeyID == "" {
+			missing = append(missing, "access_key_id (or ALIBABA_CLOUD_ACCESS_KEY_ID)")
+		}
+		if gConfig.AccessKeySecret == "" {
+			missing = append(missing, "access_key_secret (or ALIBABA_CLOUD_ACCESS_KEY_SECRET)")
+		}
+	}
+	if gConfig.Endpoint == "" {
+		missing = append(missing, "endpoint (or ALIBABA_CLOUD_ACCOUNT_ID and ALIBABA_CLOUD_DEFAULT_REGION)")
+	}
+	return util.NewValidationError("the required config is missing in the non-interactive mode:\n  %s",
+		strings.Join(missing, "\n  ")).
+		WithHint("set them by 'fcli config set <key> <value>' or the environment variables")
+}
+
+func readConfig() error {
+	if !isInteractive() {
+		return util.NewValidationError("can not prompt for the config in the non-interactive mode").
+			WithHint("use 'fcli config set <key> <value>' instead")
+	}
 	config, err := getConfigAways()
 	if err != nil {
-		return
+		return err
 	}
 
 	accountID, _ := util.GetUIDFromEndpoint(config.Endpoint)
@@ -126,47 +278,48 @@ func readConfig() {
 
 	err = survey.Ask(qs, ans)
 	if err != nil {
-		fmt.Println(err.Error())
-		return
+		return err
 	}
 	ans.build(config)
 
-	data, err := yaml.Marshal(config)
-	if err != nil {
-		fmt.Printf("Failed to marshal config: %v. Error: %v\n", data, err)
-		return
-	}
-
-	err = ioutil.WriteFile(gConfigPath, data, 0600)
-	if err != nil {
-		fmt.Printf("Failed to write file: %s. Error: %v\n", gConfigPath, err)
-		return
+	if err := writeConfigFile(config); err != nil {
+		return err
 	}
 
 	fmt.Printf("Store the configuration in: %s\n", gConfigDir)
+	return nil
 }
 
 func initConfig() {
 	gConfig = util.NewGlobalConfig()
+	gConfigSources = map[string]string{}
 
 	home := os.Getenv("HOME")
 	gConfigDir = path.Join(home, ".fcli")
 	gConfigPath = path.Join(gConfigDir, "config.yaml")
 
+	if err := util.LoadRegionCatalogFile(path.Join(gConfigDir, util.RegionCatalogFile)); err != nil {
+		fmt.Fprintf(os.Stderr, "Ignore the cached region catalog: %v\n", err)
+	}
 	pickupConfigFromConfigFile()
+	pickupConfigFromProjectFile()
 	pickupConfigFromEnv()
 	pickupConfigFromOldEnv()
 }
 
-func checkConfigCommandWithoutFlags(cmd *cobra.Command) bool {
-	if cmd.Use == "config" && cmd.Flags().NFlag() == 0 {
-		return true
+// isConfigCommand returns whether the command is config or its sub command, the mock server or
+// the version, which can run without the required config.
+func isConfigCommand(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c == configCmd || c == mockServerCmd || c == versionCmd {
+			return true
+		}
 	}
 	return false
 }
 
 func checkConfigRequredExist() bool {
-	if gConfig.AccessKeyID == "" || gConfig.AccessKeySecret == "" || gConfig.Endpoint == "" {
+	if !util.HasCredentialSource(gConfig) || gConfig.Endpoint == "" {
 		return false
 	}
 	return true
@@ -175,25 +328,184 @@ func checkConfigRequredExist() bool {
 func pickupConfigFromConfigFile() {
 	data, err := ioutil.ReadFile(gConfigPath)
 	if err == nil {
+		// the encrypted secrets are decrypted by pickupSecretsFromConfigFile after the flags are parsed
 		err = yaml.Unmarshal(data, gConfig)
 		if err != nil {
-			fmt.Printf("Failed to unmarshal config: %v. Error: %v\n", gConfigPath, err)
+			fmt.Fprintf(os.Stderr, "Failed to load config: %v. Error: %v\n", gConfigPath, err)
+			return
+		}
+		keys := map[string]interface{}{}
+		yaml.Unmarshal(data, &keys)
+		for key := range keys {
+			setConfigSource(configSourceFile, key)
+		}
+	}
+}
+
+// pickupSecretsFromConfigFile decrypts the encrypted secrets of the config file, which may prompt
+// for the passphrase, so it runs after the flags are parsed. The secrets are cleared on failure.
+func pickupSecretsFromConfigFile() {
+	if err := decryptConfigSecrets(gConfig); err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to decrypt the secrets of config: %v. Error: %v\n", gConfigPath, err)
+	}
+}
+
+// pickupConfigFromProjectFile merges the .fcli.yaml in the current directory or its parents.
+func pickupConfigFromProjectFile() {
+	gProjectConfigPath = ""
+	wd, err := os.Getwd()
+	if err != nil {
+		return
+	}
+	p := util.FindProjectConfig(wd)
+	if p == "" {
+		return
+	}
+	projectConfig, err := util.LoadProjectConfig(p)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to load project config: %v\n", err)
+		return
+	}
+	gProjectConfigPath = p
+	setConfigSource(configSourceProject, projectConfig.Apply(gConfig)...)
+}
+
+func setConfigSource(source string, keys ...string) {
+	for _, key := range keys {
+		gConfigSources[key] = source
+	}
+}
+
+// applyConfigOverrides overrides the config by the endpoint and cassette flags, and sets the defaults
+// of the flags from the config.
+func applyConfigOverrides(cmd *cobra.Command) error {
+	if err := applyEndpointFlags(cmd); err != nil {
+		return err
+	}
+	if err := applyCassetteFlags(cmd); err != nil {
+		return err
+	}
+	applyConfigFlagDefaults(cmd)
+	return nil
+}
+
+// applyEndpointFlags overrides the endpoints of the config by --endpoint, or by --region and --account-id.
+// The config command has its own --endpoint flag to set the config file, which shadows the global one.
+func applyEndpointFlags(cmd *cobra.Command) error {
+	changed := func(name string) bool {
+		return globalFlagChanged(cmd, name)
+	}
+	endpoint := ""
+	if changed("endpoint") {
+		if changed("region") || changed("account-id") {
+			return util.NewValidationError("--endpoint can not be used with --region or --account-id")
+		}
+		endpoint = strings.TrimSpace(globalInput.endpoint)
+	} else if changed("region") || changed("account-id") {
+		var err error
+		endpoint, err = util.BuildEndpoint(gConfig.Endpoint, globalInput.accountID, globalInput.region)
+		if err != nil {
+			return err
+		}
+	}
+	if endpoint == "" {
+		return nil
+	}
+	gConfig.Endpoint = endpoint
+	gConfig.SLSEndpoint = util.SLSEndpointForEndpoint(endpoint)
+	setConfigSource(configSourceFlag, "endpoint", "sls_endpoint")
+	return nil
+}
+
+// globalFlagChanged returns whether the global flag is specified, the flag is shadowed by the local
+// flag of the command with the same name, such as --endpoint of fcli config.
+func globalFlagChanged(cmd *cobra.Command, name string) bool {
+	f := cmd.InheritedFlags().Lookup(name)
+	return f != nil && f.Changed && cmd.Flags().Lookup(name) == f
+}
+
+// applyDebugFlags traces the http requests to stderr or the --debug-file if the debug is enabled
+// by the flags or the config.
+func applyDebugFlags(cmd *cobra.Command) error {
+	debug := gConfig.Debug
+	if globalFlagChanged(cmd, "debug") {
+		debug = debugInput.debug
+	}
+	if globalFlagChanged(cmd, "debug-file") && debugInput.file != "" {
+		debug = true
+	}
+	gConfig.Debug = debug
+	if !debug {
+		return nil
+	}
+	if debugInput.file == "" {
+		util.EnableHTTPTrace(os.Stderr)
+		return nil
+	}
+	f, err := os.OpenFile(debugInput.file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return fmt.Errorf("can not open the debug file: %v", err)
+	}
+	util.EnableHTTPTrace(f)
+	return nil
+}
+
+// applyCassetteFlags records the http interactions to the --record cassette, or replays them from
+// the --replay cassette with its endpoints and the fake credentials, so that the replay does not
+// depend on the config.
+func applyCassetteFlags(cmd *cobra.Command) error {
+	record := globalFlagChanged(cmd, "record") && debugInput.record != ""
+	replay := globalFlagChanged(cmd, "replay") && debugInput.replay != ""
+	switch {
+	case record && replay:
+		return util.NewValidationError("--record can not be used with --replay")
+	case record:
+		return util.EnableHTTPRecord(debugInput.record, gConfig.Endpoint, gConfig.SLSEndpoint)
+	case replay:
+		cassette, err := util.EnableHTTPReplay(debugInput.replay)
+		if err != nil {
+			return err
+		}
+		gConfig.Endpoint, gConfig.SLSEndpoint = cassette.Endpoint, cassette.SLSEndpoint
+		gConfig.AccessKeyID, gConfig.AccessKeySecret = util.ReplayAccessKeyID, util.ReplayAccessKeySecret
+		gConfig.SecurityToken, gConfig.RoleARN, gConfig.ECSRAMRole = "", "", ""
+		setConfigSource(configSourceReplay, "endpoint", "sls_endpoint", "access_key_id", "access_key_secret",
+			"security_token", "role_arn", "ecs_ram_role")
+	}
+	return nil
+}
+
+// applyConfigFlagDefaults sets the --service-name and --qualifier flags which are not specified
+// to the defaults in the config, the flags are not marked as changed.
+func applyConfigFlagDefaults(cmd *cobra.Command) {
+	defaults := map[string]string{
+		"service-name": gConfig.ServiceName,
+		"qualifier":    gConfig.Qualifier,
+	}
+	for name, value := range defaults {
+		f := cmd.Flags().Lookup(name)
+		if f == nil || f.Changed || value == "" || f.Value.String() != "" {
+			continue
 		}
+		f.Value.Set(value)
 	}
 }
 
 func pickupConfigFromEnv() {
 	if accessKey := os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_ID"); accessKey != "" {
 		gConfig.AccessKeyID = accessKey
+		setConfigSource(configSourceEnv, "access_key_id")
 	}
 	if accessKeySecret := os.Getenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET"); accessKeySecret != "" {
 		gConfig.AccessKeySecret = accessKeySecret
+		setConfigSource(configSourceEnv, "access_key_secret")
 	}
 
 	if regionID := os.Getenv("ALIBABA_CLOUD_DEFAULT_REGION"); regionID != "" {
 		if accountID := os.Getenv("ALIBABA_CLOUD_ACCOUNT_ID"); accountID != "" {
 			gConfig.Endpoint = fmt.Sprintf(util.EndpointFmt, accountID, regionID)
-			gConfig.SLSEndpoint = fmt.Sprintf(util.LogEndpointFmt, util.GetRegionNoForSLSEndpoint(gConfig.Endpoint))
+			gConfig.SLSEndpoint = util.SLSEndpointForEndpoint(gConfig.Endpoint)
+			setConfigSource(configSourceEnv, "endpoint", "sls_endpoint")
 
 		}
 	}
@@ -202,9 +514,11 @@ func pickupConfigFromEnv() {
 func pickupConfigFromOldEnv() {
 	if accessKey := os.Getenv("ALIYUN_ACCESS_KEY_ID"); accessKey != "" {
 		gConfig.AccessKeyID = accessKey
+		setConfigSource(configSourceEnv, "access_key_id")
 	}
 	if accessKeySecret := os.Getenv("ALIYUN_ACCESS_KEY_SECRET"); accessKeySecret != "" {
 		gConfig.AccessKeySecret = accessKeySecret
+		setConfigSource(configSourceEnv, "access_key_secret")
 	}
 }
 
@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestApplyEndpointFlags() {
+	assert := s.Require()
+	saved := gConfig
+	defer func() {
+		gConfig = saved
+		for _, name := range []string{"region", "account-id", "endpoint"} {
+			f := RootCmd.PersistentFlags().Lookup(name)
+			f.Value.Set("")
+			f.Changed = false
+		}
+	}()
+	gConfig = util.NewGlobalConfig()
+	gConfig.Endpoint = "https://123.cn-hangzhou.fc.aliyuncs.com"
+
+	RootCmd.PersistentFlags().Set("region", "cn-shanghai")
+	assert.Nil(applyEndpointFlags(getFuncCmd))
+	assert.Equal("https://123.cn-shanghai.fc.aliyuncs.com", gConfig.Endpoint)
+	assert.Equal("cn-shanghai.log.aliyuncs.com", gConfig.SLSEndpoint)
+	assert.Equal(configSourceFlag, gConfigSources["endpoint"])
+
+	RootCmd.PersistentFlags().Set("region", "cn-nowhere")
+	assert.NotNil(applyEndpointFlags(getFuncCmd))
+
+	RootCmd.PersistentFlags().Set("endpoint", "https://123.cn-beijing.fc.aliyuncs.com")
+	assert.NotNil(applyEndpointFlags(getFuncCmd))
+
+	// the config command sets the endpoint of the config file by its own flag
+	RootCmd.PersistentFlags().Lookup("region").Changed = false
+	assert.Nil(applyEndpointFlags(configCmd))
+	assert.Equal("https://123.cn-shanghai.fc.aliyuncs.com", gConfig.Endpoint)
+	assert.Nil(applyEndpointFlags(getFuncCmd))
+	assert.Equal("https://123.cn-beijing.fc.aliyuncs.com", gConfig.Endpoint)
+}
+
+func (s *FunctionStructsTestSuite) TestApplyConfigOverridesAfterReload() {
+	assert := s.Require()
+	saved := gConfig
+	defer func() {
+		gConfig = saved
+		f := RootCmd.PersistentFlags().Lookup("region")
+		f.Value.Set("")
+		f.Changed = false
+	}()
+	RootCmd.PersistentFlags().Set("region", "cn-shanghai")
+
+	// the config is reloaded after it is written by the interactive config
+	for i := 0; i < 2; i++ {
+		gConfig = util.NewGlobalConfig()
+		gConfig.Endpoint = "https://123.cn-hangzhou.fc.aliyuncs.com"
+		assert.Nil(applyConfigOverrides(getFuncCmd))
+		assert.Equal("https://123.cn-shanghai.fc.aliyuncs.com", gConfig.Endpoint)
+	}
+}
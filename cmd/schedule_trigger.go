@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+var scheduleTriggerNext int
+
+func init() {
+	triggerCmd.AddCommand(scheduleTriggerCmd)
+	scheduleTriggerCmd.Flags().Bool("help", false, "show the schedule of the timer trigger")
+	scheduleTriggerCmd.Flags().IntVar(&scheduleTriggerNext, "next", 5, "number of the next fire times to display")
+}
+
+var scheduleTriggerCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "show the schedule of the timer triggers",
+	Long: `
+show the next fire times of the timer trigger, or the schedule of all the timer triggers in the service
+if the trigger name is omitted.
+EXAMPLE:
+fcli trigger schedule -s(--service-name)  service_name
+				-f(--function-name) function_name
+				-t(--trigger-name)  trigger_name
+				--next 10
+fcli trigger schedule -s(--service-name)  service_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(scheduleTriggerRun(cmd))
+	},
+}
+
+// timerTriggerSchedule is the schedule of one timer trigger.
+type timerTriggerSchedule struct {
+	FunctionName   string
+	TriggerName    string
+	CronExpression string
+	Enable         bool
+	FireTimes      []time.Time
+}
+
+// timerTriggerScheduleOutput displays the fire times of a single trigger
+// or the next fire time of every timer trigger in the service.
+type timerTriggerScheduleOutput struct {
+	single    bool
+	schedules []timerTriggerSchedule
+}
+
+func (o timerTriggerScheduleOutput) String() string {
+	var buf bytes.Buffer
+	w := tabwriter.NewWriter(&buf, 0, 0, 2, ' ', 0)
+	if o.single && len(o.schedules) == 1 {
+		s := o.schedules[0]
+		fmt.Fprintf(w, "Trigger:\t%s\n", s.TriggerName)
+		fmt.Fprintf(w, "Cron expression:\t%s\n", s.CronExpression)
+		fmt.Fprintf(w, "Enable:\t%s\n", strconv.FormatBool(s.Enable))
+		fmt.Fprintln(w, "")
+		fmt.Fprintln(w, "#\tUTC\tLOCAL")
+		for i, t := range s.FireTimes {
+			fmt.Fprintf(w, "%d\t%s\t%s\n", i+1, t.UTC().Format(time.RFC3339), t.Local().Format(time.RFC3339))
+		}
+	} else {
+		fmt.Fprintln(w, "FUNCTION\tTRIGGER\tENABLE\tCRON EXPRESSION\tNEXT FIRE (UTC)\tNEXT FIRE (LOCAL)")
+		for _, s := range o.schedules {
+			nextUTC, nextLocal := "-", "-"
+			if s.Enable && len(s.FireTimes) > 0 {
+				nextUTC = s.FireTimes[0].UTC().Format(time.RFC3339)
+				nextLocal = s.FireTimes[0].Local().Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%t\t%s\t%s\t%s\n",
+				s.FunctionName, s.TriggerName, s.Enable, s.CronExpression, nextUTC, nextLocal)
+		}
+	}
+	w.Flush()
+	return buf.String()
+}
+
+func scheduleTriggerRun(cmd *cobra.Command) (*timerTriggerScheduleOutput, error) {
+	if serviceName == "" {
+		return nil, util.NewValidationError("service name is required")
+	}
+	if triggerName != "" && functionName == "" {
+		return nil, util.NewValidationError("function name is required when the trigger name is specified")
+	}
+	if scheduleTriggerNext <= 0 {
+		return nil, fmt.Errorf("--next should be a positive number")
+	}
+	client, err := util.NewFClient(gConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	if triggerName != "" {
+		s, err := getTimerTriggerSchedule(client, functionName, triggerName, scheduleTriggerNext)
+		if err != nil {
+			return nil, err
+		}
+		return &timerTriggerScheduleOutput{single: true, schedules: []timerTriggerSchedule{*s}}, nil
+	}
+
+	refs, err := listServiceTriggers(client, serviceName, functionName, fc.TRIGGER_TYPE_TIMER)
+	if err != nil {
+		return nil, err
+	}
+	output := &timerTriggerScheduleOutput{}
+	for _, ref := range refs {
+		s, err := getTimerTriggerSchedule(client, ref.FunctionName, ref.TriggerName, 1)
+		if err != nil {
+			return nil, err
+		}
+		output.schedules = append(output.schedules, *s)
+	}
+	return output, nil
+}
+
+func getTimerTriggerSchedule(client *util.FClient, functionName, triggerName string, count int) (*timerTriggerSchedule, error) {
+	resp, err := client.GetTrigger(fc.NewGetTriggerInput(serviceName, functionName, triggerName))
+	if err != nil {
+		return nil, err
+	}
+	if *resp.TriggerType != fc.TRIGGER_TYPE_TIMER {
+		return nil, fmt.Errorf("trigger %s is not a timer trigger: %s", triggerName, *resp.TriggerType)
+	}
+	config, err := util.GetTimeTriggerConfig(resp.TriggerConfig)
+	if err != nil {
+		return nil, err
+	}
+	if config.CronExpression == nil {
+		return nil, fmt.Errorf("cronExpression of trigger %s is empty", triggerName)
+	}
+	schedule, err := util.ParseCronExpression(*config.CronExpression)
+	if err != nil {
+		return nil, err
+	}
+	// the fixed interval expression counts from the last time the trigger was updated
+	if every, ok := schedule.(*util.EverySchedule); ok && resp.LastModifiedTime != nil {
+		if anchor, err := time.Parse(time.RFC3339, *resp.LastModifiedTime); err == nil {
+			every.Anchor = anchor
+		}
+	}
+	enable := config.Enable == nil || *config.Enable
+	return &timerTriggerSchedule{
+		FunctionName:   functionName,
+		TriggerName:    triggerName,
+		CronExpression: *config.CronExpression,
+		Enable:         enable,
+		FireTimes:      util.NextFireTimes(schedule, time.Now(), count),
+	}, nil
+}
@@ -21,7 +21,6 @@ import (
 	"github.com/aliyun/fcli/version"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
-	"gopkg.in/yaml.v2"
 )
 
 type shellState struct {
@@ -104,7 +103,7 @@ func parseAbsPath(path string) []string {
 	return ret
 }
 
-func getFCResrc(client *fc.Client, absPath string) (interface{}, error) {
+func getFCResrc(client *util.FClient, absPath string) (interface{}, error) {
 	v := parseAbsPath(absPath)
 	if len(v) == 2 {
 		return nil, nil
@@ -146,11 +145,16 @@ var shellCmd = &cobra.Command{
 			fmt.Printf("Can not create fc client: %s\n", err)
 			return
 		}
-		ramCli, err := util.NewRAMClient(gConfig.AccessKeyID, gConfig.AccessKeySecret)
+		ramCli, err := util.NewRAMClient(gConfig)
 		if err != nil {
 			fmt.Printf("Can not create ram client: %s\n", err)
 		}
-		slsCli := util.NewSLSClient(gConfig)
+		slsCli, err := util.NewSLSClient(gConfig)
+		if err != nil {
+			fmt.Printf("Can not create sls client: %s\n", err)
+		}
+		// slsConfig provides the credentials of slsCli, which are refreshed before each use
+		slsConfig := gConfig
 		state := shellState{
 			resrcName:    "user",
 			resrcAbsPath: fcRootDir,
@@ -524,8 +528,8 @@ var shellCmd = &cobra.Command{
 			flags := pflag.NewFlagSet("upsert-trigger", pflag.ContinueOnError)
 			help := flags.Bool("help", false, "")
 			etag := flags.String("etag", "", "trigger etag for update")
-			triggerType := flags.StringP("type", "t", "oss", "trigger type, support oss, log, timer, http, cdn_events, mns_topic now")
-			sourceARN := flags.StringP("source-arn", "s", "", "event source arn, timer type trigger optional")
+			triggerType := flags.StringP("type", "t", "oss", "trigger type, support "+strings.Join(util.TriggerTypes, ", ")+" now")
+			sourceARN := flags.StringP("source-arn", "s", "", "event source arn, timer and http type trigger optional")
 			invocationRole := flags.StringP("invocation-role", "r", "", "invocation role, timer type trigger optional")
 			triggerConfigFile := flags.StringP("trigger-config", "c", "", "trigger config file")
 			err := flags.Parse(args)
@@ -540,7 +544,7 @@ var shellCmd = &cobra.Command{
 				if *triggerType == "" {
 					return fmt.Errorf("please specify the type parameter")
 				}
-				if *triggerConfigFile == "" {
+				if *triggerConfigFile == "" && *triggerType != util.TriggerTypeTableStore {
 					return fmt.Errorf("please specify the trigger-config parameter")
 				}
 			}
@@ -724,6 +728,10 @@ var shellCmd = &cobra.Command{
 					return
 				}
 
+				if err := util.RefreshSLSClient(slsCli, slsConfig); err != nil {
+					c.Err(err)
+					return
+				}
 				proj, err := slsCli.GetProject(*projName)
 				if proj == nil {
 					proj, err = slsCli.CreateProject(
@@ -965,24 +973,22 @@ var shellCmd = &cobra.Command{
 		}
 
 		listRole := func(c *ishell.Context) {
-			resp, err := ramCli.ListRoles()
-			if err != nil {
-				c.Err(err)
-				return
+			it := ramCli.NewRoleIterator(0)
+			for it.Next() {
+				c.Println(it.Role().RoleName)
 			}
-			for _, r := range resp.Roles.Role {
-				c.Println(r.RoleName)
+			if it.Err() != nil {
+				c.Err(it.Err())
 			}
 		}
 
 		listPolicy := func(c *ishell.Context) {
-			resp, err := ramCli.ListPolicies()
-			if err != nil {
-				c.Err(err)
-				return
+			it := ramCli.NewPolicyIterator("", 0)
+			for it.Next() {
+				c.Println(it.Policy().PolicyName)
 			}
-			for _, r := range resp.Policies.Policy {
-				c.Println(r.PolicyName)
+			if it.Err() != nil {
+				c.Err(it.Err())
 			}
 		}
 
@@ -1228,6 +1234,10 @@ var shellCmd = &cobra.Command{
 					return
 				}
 
+				if err := util.RefreshSLSClient(slsCli, slsConfig); err != nil {
+					c.Err(err)
+					return
+				}
 				slsProject, err := slsCli.GetProject(projName)
 				if err != nil {
 					c.Err(fmt.Errorf("failed to get project %s: %v", projName, err))
@@ -1500,126 +1510,6 @@ var shellCmd = &cobra.Command{
 				}
 			},
 		}
-		bindRolePolicyIdempotent := func(roleName, policyName, action, resource string) (string, error) {
-			var roleARN string
-			gresp, err := ramCli.GetRole(roleName)
-			if gresp != nil {
-				roleARN = gresp.Role.Arn
-			} else {
-				roleARN, err = util.CreateRole(ramCli, roleName, serviceRolePrincipal)
-				if err != nil {
-					return roleARN, err
-				}
-			}
-
-			presp, err := ramCli.GetPolicy(policyName, "Custom")
-			if presp == nil {
-				err = util.CreatePolicy(ramCli, policyName, action, resource)
-				if err != nil {
-					return roleARN, err
-				}
-			}
-
-			// check the policy has the resource permission
-			policyVersion, err := util.GetDefaultPolicyVersion(ramCli, policyName, "Custom")
-			if err != nil {
-				return roleARN, err
-			}
-			hasResourcePermission, _ := util.CheckPolicyResourcePermission(policyVersion, resource)
-			if !hasResourcePermission {
-				err = util.CreatePolicyVersion(ramCli, policyName, action, resource)
-			}
-			if err != nil {
-				return roleARN, err
-			}
-			alreadyAttach := false
-			policies, err := ramCli.ListPoliciesForRole(roleName)
-			if policies != nil {
-				for _, v := range policies.Policies.Policy {
-					if v.PolicyName == policyName {
-						alreadyAttach = true
-						break
-					}
-				}
-			}
-			if !alreadyAttach {
-				err = util.AttachPolicy(ramCli, policyName, roleName)
-				if err != nil {
-					return roleARN, err
-				}
-			}
-			// has latency when attach policy to role , Sleep 3 second to avoid the inconsistent for policy and role.
-			for i := 0; i < 3; i++ {
-				fmt.Print(".")
-				time.Sleep(time.Second)
-			}
-			return roleARN, err
-		}
-		grantWriteLogstore := func(reader *bufio.Reader, serviceName, roleName, policyName string) error {
-			fmt.Printf("Please input the log project: ")
-			logProj, err := reader.ReadString('\n')
-			if err != nil {
-				return err
-			}
-			logProj = strings.TrimSpace(logProj)
-
-			fmt.Printf("Please input the log store: ")
-			logStore, err := reader.ReadString('\n')
-			if err != nil {
-				return err
-			}
-			logStore = strings.TrimSpace(logStore)
-
-			uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
-			if err != nil {
-				return err
-			}
-			// acs:log:*:uid:project/projectName/logstore/*
-			// acs:log:*:uid:project/projectName/logstore/logstoreName
-			action := `["log:PostLogStoreLogs"]`
-			resource := fmt.Sprintf(
-				`["acs:log:*:%s:project/%s/logstore/%s"]`, uid, logProj, logStore)
-			roleARN, err := bindRolePolicyIdempotent(roleName, policyName, action, resource)
-			if err != nil {
-				return err
-			}
-			input := fc.NewUpdateServiceInput(serviceName).
-				WithLogConfig(&fc.LogConfig{
-					Project:  &logProj,
-					Logstore: &logStore,
-				}).WithRole(roleARN)
-			_, err = client.UpdateService(input)
-			if err == nil {
-				fmt.Println("grant success")
-				return nil
-			}
-			return wrapResponseError(err)
-		}
-
-		grantCopyCodeFromOSS := func(reader *bufio.Reader, serviceName, roleName, policyName string) error {
-			fmt.Printf("Please input the OSS path (example: your_bucket/your_directory: ")
-			path, err := reader.ReadString('\n')
-			if err != nil {
-				return err
-			}
-			path = strings.TrimSpace(path)
-
-			uid, err := util.GetUIDFromEndpoint(gConfig.Endpoint)
-			if err != nil {
-				return err
-			}
-			// acs:oss:*:uid:ossPath
-			action := `["oss:GetObject"]`
-			resource := fmt.Sprintf(`["acs:oss:*:%s:%s/*"]`, uid, path)
-			roleARN, err := bindRolePolicyIdempotent(roleName, policyName, action, resource)
-			input := fc.NewUpdateServiceInput(serviceName).WithRole(roleARN)
-			_, err = client.UpdateService(input)
-			if err == nil {
-				fmt.Println("grant success")
-			}
-			return err
-		}
-
 		grant := &ishell.Cmd{
 			Name:     "grant",
 			Help:     "grant the permission",
@@ -1627,6 +1517,7 @@ var shellCmd = &cobra.Command{
 			Func: func(c *ishell.Context) {
 				flags := pflag.NewFlagSet("grant", pflag.ContinueOnError)
 				help := flags.Bool("help", false, "")
+				scenarioName := flags.String("scenario", "", "the grant scenario, one of "+strings.Join(grantScenarioNames(), ", "))
 				err := flags.Parse(c.Args)
 				if err != nil {
 					c.Err(err)
@@ -1637,20 +1528,23 @@ var shellCmd = &cobra.Command{
 					c.Println("usage: grant service/trigger [flags]")
 					c.Println("flags:")
 					c.Println(flags.FlagUsages())
+					c.Println(grantScenarioUsage())
 					return
 				}
-				currPath := findFirstArg(c.Args)
+				currPath := findFirstArg(flags.Args())
 				if !isAbs(currPath) {
 					currPath = path.Join(state.resrcAbsPath, currPath)
 				}
 				resrcList := parseAbsPath(currPath)
-				resrcName := ""
+				target := grantTarget{Params: map[string]string{}}
 				if len(resrcList) == 3 {
 					// /fc/service
-					resrcName = resrcList[2]
+					target.ServiceName = resrcList[2]
 				} else if len(resrcList) == 5 {
-					// /fc/{service}/{function}{trigger}
-					resrcName = resrcList[4]
+					// /fc/{service}/{function}/{trigger}
+					target.ServiceName = resrcList[2]
+					target.FunctionName = resrcList[3]
+					target.TriggerName = resrcList[4]
 				} else {
 					c.Err(fmt.Errorf(
 						"invalid path: %s. Only support "+
@@ -1659,7 +1553,6 @@ var shellCmd = &cobra.Command{
 					return
 				}
 				reader := bufio.NewReader(os.Stdin)
-				numScenarios := 2
 
 				fmt.Println("Please input the role name: ")
 				roleName, err := reader.ReadString('\n')
@@ -1667,7 +1560,7 @@ var shellCmd = &cobra.Command{
 					c.Err(err)
 					return
 				}
-				roleName = strings.TrimSpace(roleName)
+				target.RoleName = strings.TrimSpace(roleName)
 
 				fmt.Println("Please input the policy name: ")
 				policyName, err := reader.ReadString('\n')
@@ -1675,35 +1568,45 @@ var shellCmd = &cobra.Command{
 					c.Err(err)
 					return
 				}
-				policyName = strings.TrimSpace(policyName)
+				target.PolicyName = strings.TrimSpace(policyName)
 
-				fmt.Println("Permission grant scenarios:")
-				fmt.Println("1. Allow FC write function logs to your log store.")
-				fmt.Println("2. Allow FC copy code from your OSS location.")
-				fmt.Printf("Please input your choice [1-%d]:\n", numScenarios)
-				scenario := 0
-				for {
-					s, err := reader.ReadString('\n')
-					if err != nil {
-						c.Err(err)
-						return
+				scenario := findGrantScenario(*scenarioName)
+				if scenario == nil {
+					numScenarios := len(grantScenarios)
+					fmt.Println("Permission grant scenarios:")
+					for i, s := range grantScenarios {
+						fmt.Printf("%d. %s\n", i+1, s.Description)
 					}
-					s = strings.TrimSpace(s)
-					scenario, err = strconv.Atoi(s)
-					if err == nil && scenario <= numScenarios {
-						break
-					} else {
-						c.Printf("Invalid input: %s\n", s)
-						c.Printf("Please input your choice [1-%d]:\n", numScenarios)
+					fmt.Printf("Please input your choice [1-%d]:\n", numScenarios)
+					for {
+						s, err := reader.ReadString('\n')
+						if err != nil {
+							c.Err(err)
+							return
+						}
+						s = strings.TrimSpace(s)
+						choice, err := strconv.Atoi(s)
+						if err == nil && choice >= 1 && choice <= numScenarios {
+							scenario = grantScenarios[choice-1]
+							break
+						} else {
+							c.Printf("Invalid input: %s\n", s)
+							c.Printf("Please input your choice [1-%d]:\n", numScenarios)
+						}
 					}
 				}
-				switch scenario {
-				case 1:
-					err = grantWriteLogstore(reader, resrcName, roleName, policyName)
-				case 2:
-					err = grantCopyCodeFromOSS(reader, resrcName, roleName, policyName)
+				if target.FunctionName != "" {
+					target.Params["function"] = target.FunctionName
 				}
-				c.Err(err)
+				if err := scenario.promptGrantParams(reader, target.Params); err != nil {
+					c.Err(err)
+					return
+				}
+				if _, err := scenario.grant(ramCli, client, target); err != nil {
+					c.Err(err)
+					return
+				}
+				fmt.Println("grant success")
 			},
 		}
 
@@ -1812,9 +1715,11 @@ var shellCmd = &cobra.Command{
 			Func: func(c *ishell.Context) {
 				flags := pflag.NewFlagSet("config", pflag.ContinueOnError)
 				help := flags.Bool("help", false, "")
-				debug := flags.Bool("debug", false, "enable/disable debug mode")
+				debug := flags.Bool("debug", false, "enable/disable debug mode, which logs the http requests")
 				timeout := flags.Uint("timeout", 60, "timeout of the operation")
 				endpoint := flags.String("endpoint", "", "endpoint of the function compute service")
+				region := flags.String("region", "", "region of the endpoint, such as cn-shanghai")
+				accountID := flags.String("account-id", "", "account id of the endpoint")
 				akid := flags.String("access-key-id", "", "access key id")
 				aksecret := flags.String("access-key-secret", "", "access key secret")
 				securityToken := flags.String("security-token", "", "ram security token")
@@ -1837,6 +1742,12 @@ var shellCmd = &cobra.Command{
 				}
 				if flags.Changed("debug") {
 					config.Debug = *debug
+					gConfig.Debug = *debug
+					if *debug {
+						util.EnableHTTPTrace(os.Stderr)
+					} else {
+						util.DisableHTTPTrace()
+					}
 				}
 				if flags.Changed("timeout") {
 					config.Timeout = *timeout
@@ -1844,13 +1755,16 @@ var shellCmd = &cobra.Command{
 				if flags.Changed("endpoint") {
 					config.Endpoint = *endpoint
 					config.Endpoint = strings.TrimSpace(config.Endpoint)
-					config.SLSEndpoint = fmt.Sprintf(
-						util.LogEndpointFmt, util.GetRegionNoForSLSEndpoint(config.Endpoint))
-					ramCli, err = util.NewRAMClient(config.AccessKeyID, config.AccessKeySecret)
+					config.SLSEndpoint = util.SLSEndpointForEndpoint(config.Endpoint)
+					gConfig.Endpoint, gConfig.SLSEndpoint = config.Endpoint, config.SLSEndpoint
+				} else if flags.Changed("region") || flags.Changed("account-id") {
+					// the endpoint of the region is only for this session, the config file is not changed
+					sessionEndpoint, err := util.BuildEndpoint(gConfig.Endpoint, *accountID, *region)
 					if err != nil {
-						fmt.Printf("Can not create ram client: %s\n", err)
+						c.Err(err)
+						return
 					}
-					slsCli = util.NewSLSClient(config)
+					gConfig.Endpoint, gConfig.SLSEndpoint = sessionEndpoint, util.SLSEndpointForEndpoint(sessionEndpoint)
 				}
 
 				if flags.Changed("access-key-id") {
@@ -1862,19 +1776,25 @@ var shellCmd = &cobra.Command{
 				if flags.Changed("security-token") {
 					config.SecurityToken = *securityToken
 				}
-				client, err = util.NewFClient(config)
+				// the clients use the endpoints of this session
+				session := *config
+				session.Endpoint, session.SLSEndpoint = gConfig.Endpoint, gConfig.SLSEndpoint
+				client, err = util.NewFClient(&session)
 				if err != nil {
 					fmt.Printf("Can not create fc client: %s\n", err)
 					return
 				}
-				data, err := yaml.Marshal(config)
+				ramCli, err = util.NewRAMClient(&session)
 				if err != nil {
-					fmt.Printf("Failed to marshal config: %v. Error: %v\n", data, err)
-					return
+					fmt.Printf("Can not create ram client: %s\n", err)
 				}
-				err = ioutil.WriteFile(gConfigPath, data, 0600)
+				slsCli, err = util.NewSLSClient(&session)
 				if err != nil {
-					fmt.Printf("Failed to write file: %s. Error: %v\n", gConfigPath, err)
+					fmt.Printf("Can not create sls client: %s\n", err)
+				}
+				slsConfig = &session
+				if err := writeConfigFile(config); err != nil {
+					fmt.Println(err)
 					return
 				}
 			},
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
+	"github.com/spf13/cobra"
+)
+
+// triggerConfigTemplates are the starter trigger config files of each trigger type
+var triggerConfigTemplates = map[string]string{
+	fc.TRIGGER_TYPE_OSS: `# oss trigger config, the source arn is acs:oss:<region>:<account_id>:<bucket>
+triggerConfig:
+    # required, the oss events which invoke the function
+    events:
+        - oss:ObjectCreated:PutObject
+        - oss:ObjectCreated:PostObject
+    # optional, only the objects matching the prefix and suffix invoke the function
+    filter:
+        key:
+            prefix: source/
+            suffix: .png
+`,
+	fc.TRIGGER_TYPE_LOG: `# log trigger config, the source arn is acs:log:<region>:<account_id>:project/<project>
+triggerConfig:
+    # required, the logstore which the function consumes
+    sourceConfig:
+        logstore: source_logstore
+    # required, how the function is invoked
+    jobConfig:
+        # max retry times when the invocation fails
+        maxRetryTime: 3
+        # interval of the invocations in seconds
+        triggerInterval: 60
+    # optional, passed to the function as event.parameter
+    functionParameter:
+        key: value
+    # required, the logstore where the trigger writes its execution logs
+    logConfig:
+        project: log_project
+        logstore: trigger_logstore
+    # required, whether the trigger is enabled
+    enable: true
+`,
+	fc.TRIGGER_TYPE_TIMER: `# timer trigger config, no source arn is needed
+triggerConfig:
+    # optional, passed to the function as event.payload
+    payload: ""
+    # required, second minute hour day-of-month month day-of-week in UTC, or "@every 5m"
+    cronExpression: "0 0/5 * * * *"
+    # optional, whether the trigger is enabled
+    enable: true
+`,
+	fc.TRIGGER_TYPE_HTTP: `# http trigger config, no source arn is needed
+triggerConfig:
+    # required, anonymous or function
+    authType: anonymous
+    # required, the http methods which invoke the function
+    methods:
+        - GET
+        - POST
+`,
+	fc.TRIGGER_TYPE_CDN_EVENTS: `# cdn events trigger config, the source arn is acs:cdn:*:<account_id>
+triggerConfig:
+    # required, the cdn event name, such as CdnDomainStarted, LogFileCreated
+    eventName: LogFileCreated
+    # required, the version of the event
+    eventVersion: "1.0.0"
+    # required, the description of the trigger
+    notes: cdn log file created
+    # required, the event is triggered only if it matches the filter
+    filter:
+        domain:
+            - www.example.com
+`,
+	fc.TRIGGER_TYPE_MNS_TOPIC: `# mns topic trigger config, the source arn is acs:mns:<region>:<account_id>:/topics/<topic>
+triggerConfig:
+    # optional, STREAM or JSON
+    notifyContentFormat: STREAM
+    # optional, BACKOFF_RETRY or EXPONENTIAL_DECAY_RETRY
+    notifyStrategy: BACKOFF_RETRY
+    # optional, only the messages with the tag invoke the function
+    filterTag: ""
+`,
+	util.TriggerTypeTableStore: `# tablestore trigger config, the source arn is acs:ots:<region>:<account_id>:instance/<instance>/table/<table>
+# the stream of the table must be enabled, and nothing else needs to be configured
+triggerConfig: {}
+`,
+	util.TriggerTypeRDS: `# rds trigger config, the source arn is acs:rds:<region>:<account_id>:dbinstance/<instance>
+triggerConfig:
+    # required, the tables whose changes invoke the function, in the format of <database>.<table>
+    subscriptionObjects:
+        - db1.table1
+    # optional, retry times when the invocation fails
+    retry: 3
+    # optional, the max concurrent invocations
+    concurrency: 1
+    # optional, json or protobuf
+    eventFormat: json
+`,
+}
+
+var templateTriggerType *string
+
+func init() {
+	triggerCmd.AddCommand(templateTriggerCmd)
+	templateTriggerCmd.Flags().Bool("help", false, "print trigger config template")
+	templateTriggerType = templateTriggerCmd.Flags().String(
+		"type", "", "trigger type, support "+strings.Join(triggerTemplateTypes(), ", "))
+}
+
+var templateTriggerCmd = &cobra.Command{
+	Use:   "template",
+	Short: "print a starter trigger config file",
+	Long: `
+print a commented starter trigger config file, which can be used by trigger create and update.
+EXAMPLE:
+fcli trigger template --type oss > oss_trigger.yaml
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return prettyPrint(templateTriggerRun(cmd))
+	},
+}
+
+func templateTriggerRun(cmd *cobra.Command) (*string, error) {
+	template, ok := triggerConfigTemplates[*templateTriggerType]
+	if !ok {
+		return nil, util.NewValidationError("unsupported trigger type, expect %s, actual %q",
+			strings.Join(triggerTemplateTypes(), ", "), *templateTriggerType)
+	}
+	template = strings.TrimSuffix(template, "\n")
+	return &template, nil
+}
+
+func triggerTemplateTypes() []string {
+	types := []string{}
+	for t := range triggerConfigTemplates {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	return types
+}
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/aliyun/fcli/util"
+)
+
+func (s *FunctionStructsTestSuite) TestTriggerTemplatesAreValid() {
+	assert := s.Require()
+	dir, err := ioutil.TempDir("", "fcli")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+
+	for triggerType, template := range triggerConfigTemplates {
+		file := path.Join(dir, triggerType+".yaml")
+		assert.Nil(ioutil.WriteFile(file, []byte(template), 0600))
+		assert.Nil(util.ValidateTriggerConfigFile(triggerType, file), triggerType)
+	}
+}
@@ -3,6 +3,8 @@ package cmd
 import (
 	"fmt"
 
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/util"
 	"github.com/spf13/cobra"
 )
 
@@ -45,3 +47,74 @@ func printTrigger() {
 	fmt.Println("function name:" + functionName)
 	fmt.Println("service  name:" + serviceName)
 }
+
+// triggerRef locates a trigger under the service.
+type triggerRef struct {
+	FunctionName   string
+	TriggerName    string
+	TriggerType    string
+	InvocationRole string
+}
+
+// listFunctionNames list all the function names of the service.
+func listFunctionNames(client *util.FClient, serviceName string) ([]string, error) {
+	names := []string{}
+	input := fc.NewListFunctionsInput(serviceName).WithLimit(100)
+	for {
+		resp, err := client.ListFunctions(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range resp.Functions {
+			names = append(names, *f.FunctionName)
+		}
+		if resp.NextToken == nil || *resp.NextToken == "" {
+			break
+		}
+		input.WithNextToken(*resp.NextToken)
+	}
+	return names, nil
+}
+
+// listServiceTriggers list the triggers of the function, or of all the functions in the service
+// if the function name is empty. Only the triggers of triggerType are returned if it is not empty.
+func listServiceTriggers(client *util.FClient, serviceName, functionName, triggerType string) ([]triggerRef, error) {
+	functionNames := []string{functionName}
+	if functionName == "" {
+		var err error
+		functionNames, err = listFunctionNames(client, serviceName)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	refs := []triggerRef{}
+	for _, fn := range functionNames {
+		input := fc.NewListTriggersInput(serviceName, fn).WithLimit(100)
+		for {
+			resp, err := client.ListTriggers(input)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range resp.Triggers {
+				if triggerType != "" && *t.TriggerType != triggerType {
+					continue
+				}
+				ref := triggerRef{
+					FunctionName: fn,
+					TriggerName:  *t.TriggerName,
+					TriggerType:  *t.TriggerType,
+				}
+				if t.InvocationRole != nil {
+					ref.InvocationRole = *t.InvocationRole
+				}
+				refs = append(refs, ref)
+			}
+			if resp.NextToken == nil || *resp.NextToken == "" {
+				break
+			}
+			input.WithNextToken(*resp.NextToken)
+		}
+	}
+	return refs, nil
+}
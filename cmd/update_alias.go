@@ -27,6 +27,7 @@ func init() {
 	updateAliasInput.IfMatch = updateAliasCmd.Flags().String(
 		"etag", "", "provide etag to do the conditional update. "+
 			"If the specified etag does not match the alias's, the update will fail.")
+	addInputJSONFlags(updateAliasCmd)
 }
 
 var updateRoutes = new([]string)
@@ -46,17 +47,41 @@ fcli alias update -s(--service-name)      service_name
 			-d(--description) description
 			-r(--route)       2=0.05
 			--etag            a198ec37e2a1c2ababbb3717074f29ea
+fcli alias update --generate-skeleton > alias.json
+fcli alias update --input-json alias.json -v(--version-id) 2
 			`,
-	Run: func(cmd *cobra.Command, args []string) {
-		client, err := util.NewFClient(gConfig)
-		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.UpdateAliasInput{})
+		}
+		input := &fc.UpdateAliasInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = updateAliasInput.ServiceName
+		}
+		if useFlag(cmd, "alias-name") {
+			input.AliasName = updateAliasInput.AliasName
+		}
+		if useFlag(cmd, "version-id") {
+			input.VersionID = updateAliasInput.VersionID
 		}
-		updateAliasInput.AdditionalVersionWeight = util.ParseAdditionalVersionWeight(*updateRoutes)
-		_, err = client.UpdateAlias(&updateAliasInput)
+		if useFlag(cmd, "description") {
+			input.Description = updateAliasInput.Description
+		}
+		if useFlag(cmd, "route") {
+			input.AdditionalVersionWeight = util.ParseAdditionalVersionWeight(*updateRoutes)
+		}
+		if useFlag(cmd, "etag") {
+			input.IfMatch = updateAliasInput.IfMatch
+		}
+
+		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: %s\n", err)
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
+		_, err = client.UpdateAlias(input)
+		return err
 	},
 }
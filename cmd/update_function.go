@@ -68,24 +68,45 @@ func init() {
 			"If the specified etag does not match the function's, the update will fail.")
 	updateFuncInput.environmentVariables = updateFuncCmd.Flags().StringArray("env", []string{}, "set environment variables. e.g. --env VAR1=val1 --env VAR2=val2")
 	updateFuncInput.environmentConfigFiles = updateFuncCmd.Flags().StringArray("env-file", []string{}, "read in a file of environment variables. e.g. --env-file FILE1 --env-file FILE2")
+	addInputJSONFlags(updateFuncCmd)
 }
 
 var updateFuncCmd = &cobra.Command{
 	Use:     "update [option]",
 	Aliases: []string{"u"},
 	Short:   "Update function attributes",
-	Long:    ``,
-	Run: func(cmd *cobra.Command, args []string) {
-		input := fc.NewUpdateFunctionInput(*updateFuncInput.serviceName, *updateFuncInput.functionName)
+	Long: `
+update function, the settings which the flags can not express can be read from the json of the input
+EXAMPLE:
+fcli function update --generate-skeleton > function.json
+fcli function update --input-json function.json -s(--service-name) service_name -f(--function-name) function_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.UpdateFunctionInput{})
+		}
+		input := &fc.UpdateFunctionInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = updateFuncInput.serviceName
+		}
+		if useFlag(cmd, "function-name") {
+			input.FunctionName = updateFuncInput.functionName
+		}
 
+		// the variables of the flags are added to the ones of --input-json
 		envMap := make(map[string]string)
+		for k, v := range input.EnvironmentVariables {
+			envMap[k] = v
+		}
 
 		if cmd.Flags().Changed("env-file") {
 			for _, envFilePath := range *updateFuncInput.environmentConfigFiles {
 				_, err := util.GetEnvSetting(envMap, envFilePath)
 				if err != nil {
-					fmt.Printf("Error: %s\n", err)
-					return
+					return err
 				}
 			}
 			input.WithEnvironmentVariables(envMap)
@@ -128,8 +149,7 @@ var updateFuncCmd = &cobra.Command{
 		if cmd.Flags().Changed("code-file") {
 			data, err := ioutil.ReadFile(*updateFuncInput.codeFile)
 			if err != nil {
-				fmt.Printf("Error: %v", err)
-				return
+				return err
 			}
 			input.WithCode(fc.NewCode().WithZipFile(data))
 		} else if cmd.Flags().Changed("code-dir") {
@@ -149,13 +169,10 @@ var updateFuncCmd = &cobra.Command{
 
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		_, err = client.UpdateFunction(input)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
 
@@ -41,6 +41,7 @@ func init() {
 		"at least one nas server is required to enable the NAS access")
 	updateServiceInput.nasMount = updateServiceCmd.Flags().StringArrayP("nas-mount-dir", "", []string{},
 		"at least one nas dir is required to enable the NAS access")
+	addInputJSONFlags(updateServiceCmd)
 }
 
 type updateServiceInputType struct {
@@ -66,10 +67,23 @@ var updateServiceCmd = &cobra.Command{
 	Use:     "update [option]",
 	Aliases: []string{"u"},
 	Short:   "update service",
-	Long:    ``,
-
-	Run: func(cmd *cobra.Command, args []string) {
-		input := fc.NewUpdateServiceInput(*updateServiceInput.serviceName)
+	Long: `
+update service, the settings which the flags can not express can be read from the json of the input
+EXAMPLE:
+fcli service update --generate-skeleton > service.json
+fcli service update --input-json service.json -s(--service-name) service_name
+			`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.UpdateServiceInput{})
+		}
+		input := &fc.UpdateServiceInput{}
+		if err := readInputJSON(cmd, input); err != nil {
+			return err
+		}
+		if useFlag(cmd, "service-name") {
+			input.ServiceName = updateServiceInput.serviceName
+		}
 		if cmd.Flags().Changed("description") {
 			input.WithDescription(*updateServiceInput.description)
 		}
@@ -93,7 +107,10 @@ var updateServiceCmd = &cobra.Command{
 				WithVSwitchIDs(*updateServiceInput.vSwitchIDs).
 				WithSecurityGroupID(*updateServiceInput.securityGroupID))
 		}
-		nasConfig := fc.NewNASConfig()
+		nasConfig := input.NASConfig
+		if nasConfig == nil {
+			nasConfig = fc.NewNASConfig()
+		}
 		if cmd.Flags().Changed("nas-userid") {
 			nasConfig.WithUserID(*updateServiceInput.nasUserID)
 		}
@@ -102,8 +119,7 @@ var updateServiceCmd = &cobra.Command{
 		}
 		if cmd.Flags().Changed("nas-server-addr") || cmd.Flags().Changed("nas-mount-dir") {
 			if len(*updateServiceInput.nasServer) != len(*updateServiceInput.nasMount) {
-				fmt.Println("nas server array length must match nas dir array length")
-				return
+				return util.NewValidationError("nas server array length must match nas dir array length")
 			}
 			mountPoints := []fc.NASMountConfig{}
 			for i, addr := range *updateServiceInput.nasServer {
@@ -117,12 +133,9 @@ var updateServiceCmd = &cobra.Command{
 		input.WithNASConfig(nasConfig)
 		client, err := util.NewFClient(gConfig)
 		if err != nil {
-			fmt.Printf("Error: can not create fc client: %s\n", err)
-			return
+			return fmt.Errorf("can not create fc client: %v", err)
 		}
 		_, err = client.UpdateService(input)
-		if err != nil {
-			fmt.Printf("Error: %s\n", err)
-		}
+		return err
 	},
 }
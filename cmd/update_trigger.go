@@ -32,16 +32,19 @@ func (o UpdateTriggerCliOutput) String() string {
 // MarshalJSON defines how UpdateTriggerCliOutput should be displayed in JSON format
 func (o UpdateTriggerCliOutput) MarshalJSON() ([]byte, error) {
 	return json.Marshal(triggerCliOutputDisplay{
-		HTTPTriggerURL:   o.HTTPTriggerURL,
-		Header:           o.Header,
-		TriggerName:      o.TriggerName,
-		SourceARN:        o.SourceARN,
-		TriggerType:      o.TriggerType,
-		InvocationRole:   o.InvocationRole,
-		Qualifier:        o.Qualifier,
-		TriggerConfig:    o.TriggerConfig,
-		CreatedTime:      o.CreatedTime,
-		LastModifiedTime: o.LastModifiedTime,
+		HTTPTriggerURL:     o.HTTPTriggerURL,
+		TableStoreInstance: o.TableStoreInstance,
+		TableStoreTable:    o.TableStoreTable,
+		RDSInstance:        o.RDSInstance,
+		Header:             o.Header,
+		TriggerName:        o.TriggerName,
+		SourceARN:          o.SourceARN,
+		TriggerType:        o.TriggerType,
+		InvocationRole:     o.InvocationRole,
+		Qualifier:          o.Qualifier,
+		TriggerConfig:      o.TriggerConfig,
+		CreatedTime:        o.CreatedTime,
+		LastModifiedTime:   o.LastModifiedTime,
 	})
 }
 
@@ -67,6 +70,9 @@ fcli trigger update -s(service-name)  demo_service
 				   --trigger-config  oss_trigger_sample.yaml
 				   --q(qualifier)    LATEST
 
+fcli trigger update --generate-skeleton > trigger.json
+fcli trigger update --input-json trigger.json --etag a198ec37e2a1c2ababbb3717074f29ea
+
 oss_trigger_sample.yaml example:
 triggerConfig:
     events: 
@@ -77,8 +83,11 @@ triggerConfig:
             prefix: foo
             suffix: bar
 		`,
-	Run: func(cmd *cobra.Command, args []string) {
-		prettyPrint(updateTriggerRun(cmd))
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if isGeneratingSkeleton(cmd) {
+			return printSkeleton(fc.UpdateTriggerInput{})
+		}
+		return prettyPrint(updateTriggerRun(cmd))
 	},
 }
 
@@ -91,7 +100,19 @@ func updateTriggerRun(cmd *cobra.Command) (*UpdateTriggerCliOutput, error) {
 	if err != nil {
 		return nil, err
 	}
-	updateTriggerInput := fc.NewUpdateTriggerInput(serviceName, functionName, triggerName)
+	updateTriggerInput := &fc.UpdateTriggerInput{}
+	if err := readInputJSON(cmd, updateTriggerInput); err != nil {
+		return nil, err
+	}
+	if useFlag(cmd, "service-name") {
+		updateTriggerInput.ServiceName = &serviceName
+	}
+	if useFlag(cmd, "function-name") {
+		updateTriggerInput.FunctionName = &functionName
+	}
+	if useFlag(cmd, "trigger-name") {
+		updateTriggerInput.TriggerName = &triggerName
+	}
 	if cmd.Flags().Changed("etag") {
 		updateTriggerInput.WithIfMatch(*updateParam.Etag)
 	}
@@ -102,7 +123,11 @@ func updateTriggerRun(cmd *cobra.Command) (*UpdateTriggerCliOutput, error) {
 	triggerType := ""
 	if cmd.Flags().Changed("trigger-config") {
 
-		getTriggerOutput, err := client.GetTrigger(fc.NewGetTriggerInput(serviceName, functionName, triggerName))
+		getTriggerOutput, err := client.GetTrigger(&fc.GetTriggerInput{
+			ServiceName:  updateTriggerInput.ServiceName,
+			FunctionName: updateTriggerInput.FunctionName,
+			TriggerName:  updateTriggerInput.TriggerName,
+		})
 		if err != nil {
 			return nil, err
 		}
@@ -128,7 +153,7 @@ func updateTriggerRun(cmd *cobra.Command) (*UpdateTriggerCliOutput, error) {
 	output := &UpdateTriggerCliOutput{
 		UpdateTriggerOutput: *resp,
 	}
-	decorateTriggerOutput(&triggerType, &output.triggerCliOutputDecorate)
+	decorateTriggerOutput(resp.TriggerType, resp.SourceARN, &output.triggerCliOutputDecorate)
 
 	return output, err
 }
@@ -139,4 +164,5 @@ func init() {
 	updateParam.InvocationRole = updateTriggerCmd.Flags().String("invocation-role", "", "trigger invocation role")
 	updateParam.TriggerConfigFile = updateTriggerCmd.Flags().String("trigger-config", "", "trigger config file")
 	updateParam.Qualifier = updateTriggerCmd.Flags().StringP("qualifier", "q", "", "service version or alias, optional")
+	addInputJSONFlags(updateTriggerCmd)
 }
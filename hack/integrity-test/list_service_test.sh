@@ -7,6 +7,8 @@ expect="{
   \"NextToken\": null
 }"
 
+go run main.go service create -s demo > /dev/null
+
 result=$(go run main.go service list)
 
 if ! [ "${expect}" == "${result}" ]; then
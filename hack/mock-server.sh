@@ -1,9 +1,21 @@
 #!/bin/bash
-
-npm install -g anyproxy
+set -e
 
 mkdir -p ~/.fcli
 
 cp ./.config.yaml ~/.fcli/config.yaml
 
-anyproxy  --rule ./hack/integrity-test/rule.js  &
+# the endpoint of .config.yaml is the fake function compute api server
+go build -o bundles/fcli-mock-server main.go
+
+bundles/fcli-mock-server mock-server --port 8001 &
+
+for i in $(seq 1 30); do
+    if curl -s http://127.0.0.1:8001/2016-08-15/services > /dev/null; then
+        exit 0
+    fi
+    sleep 1
+done
+
+echo "the mock server is not ready"
+false
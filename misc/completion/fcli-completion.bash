@@ -1,27 +1,47 @@
 _fcli_runtime_types="python2.7 python3 nodejs6 nodejs8 java8"
-_fcli_trigger_types="oss log timer http cdn_events"
-_fcli_sub_command="config function help service shell trigger version"
-_fcli_config_args="--access-key-id --access-key-secret --api-version --debug --display --endpoint --help --security-token --timeout"
+_fcli_trigger_types="oss log timer http cdn_events mns_topic tablestore rds"
+_fcli_trigger_event_types="tablestore rds"
+_fcli_inventory_args="--help --regions -o --output --file"
+_fcli_mock_server_args="--help -p --port --host --data-dir --invoke-command -v --verbose"
+_fcli_region_list_args="--help --network"
+_fcli_region_refresh_args="--help --url"
+_fcli_sub_command="config function help inventory mock-server ram region role service shell trigger version"
+_fcli_config_keys="endpoint api_version access_key_id access_key_secret security_token debug timeout sls_endpoint service_name qualifier role_arn role_session_name role_session_duration sts_endpoint ecs_ram_role ecs_metadata_url max_attempts region_catalog_url"
+_fcli_config_args="--access-key-id --access-key-secret --api-version --debug --decrypt --display --ecs-metadata-url --ecs-ram-role --encrypt --endpoint --help --max-attempts --role-arn --role-session-duration --role-session-name --security-token --sts-endpoint --timeout"
 
-_fcli_function_create_args="-b --code-bucket -d --code-dir --code-file -o --code-object --description -f --function-name -h --handler --help -m --memory -t --runtime -s --service-name --timeout"
-_fcli_function_update_args="-b --bucket --code-dir --code-file -d --description --etag -f --function-name -h --handler --help -m --memory -o --object -t --runtime -s --service-name --timeout"
+_fcli_function_create_args="-b --code-bucket -d --code-dir --code-file -o --code-object --description -f --function-name -h --handler --help -m --memory -t --runtime -s --service-name --timeout --input-json --generate-skeleton"
+_fcli_function_update_args="-b --bucket --code-dir --code-file -d --description --etag -f --function-name -h --handler --help -m --memory -o --object -t --runtime -s --service-name --timeout --input-json --generate-skeleton"
 _fcli_function_delete_args="--etag -f --function-name -s --service-name"
-_fcli_function_list_args="--help -l --limit --name-only -t --next-token -p --prefix -s --service-name -k --start-key"
+_fcli_function_list_args="--help -l --limit --name-only -t --next-token -p --prefix -s --service-name -k --start-key --all-regions --regions"
 _fcli_function_get_args="-f --function-name --help -s --service-name"
 _fcli_function_logs_args="--end -f --function-name -h --help -s --service-name --start"
 _fcli_function_invoke_args="-d --debug --event-file --event-str -f --function-name --help --invocation-type -o --output -s --service-name"
 
-_fcli_service_create_args="--description --help -p --log-project -l --log-store -r --role-arn -s --service-name"
-_fcli_service_update_args="--description --etag --help -p --log-project -l --log-store -r --role -s --service-name"
+_fcli_service_create_args="--description --help -p --log-project -l --log-store -r --role-arn -s --service-name --input-json --generate-skeleton"
+_fcli_service_update_args="--description --etag --help -p --log-project -l --log-store -r --role -s --service-name --input-json --generate-skeleton"
 _fcli_service_delete_args="--etag --help -s --service-name"
-_fcli_service_list_args="--help -l --limit --name-only -t --next-token -p --prefix -k --start-key"
+_fcli_service_list_args="--help -l --limit --name-only -t --next-token -p --prefix -k --start-key --all-regions --regions"
 _fcli_service_get_args="--help -s --service-name"
 
-_fcli_trigger_create_args="--help -s --service-name -f --function-name --trigger-name -t -c --config -r --role -a --source-arn --type"
-_fcli_trigger_update_args="--help -s --service-name -f --function-name --trigger-name -t --etag --invocation-role --trigger-config"
+_fcli_ram_role_args="--help -r --role-name --format --principal --trust-policy --description --add-principal --remove-principal"
+_fcli_ram_policy_args="--help -p --policy-name --type --format -d --document --description --version-id"
+_fcli_ram_attach_args="--help -r --role-name -p --policy-name --type --format"
+_fcli_ram_audit_args="--help --cleanup -y --yes --format"
+_fcli_ram_generate_policy_args="--help -s --service-name --apply --format"
+_fcli_role_config_args="--help -r --role-name -p --project -l --logstore -b --bucket"
+_fcli_role_grant_args="--help --scenario -s --service-name -f --function-name -t --trigger-name -r --role-name -p --policy-name --param"
+_fcli_ram_simulate_args="--help -r --role-name -a --action --resource --format"
+
+_fcli_trigger_create_args="--help -s --service-name -f --function-name --trigger-name -t -c --config -r --role -a --source-arn --type --source-bucket --source-logstore --source-topic --auto-role -q --qualifier --input-json --generate-skeleton"
+_fcli_trigger_update_args="--help -s --service-name -f --function-name --trigger-name -t --etag --invocation-role --trigger-config --input-json --generate-skeleton"
 _fcli_trigger_delete_args="--help -s --service-name -f --function-name --trigger-name -t --etag"
-_fcli_trigger_list_args="--help -s --service-name -f --function-name --all --limit -l --next-token -n --only-names --prefix -p --start-key -k"
+_fcli_trigger_list_args="--help -s --service-name -f --function-name --all --limit -l --next-token -n --only-names --prefix -p --start-key -k --all-regions --regions"
 _fcli_trigger_get_args="--help -s --service-name -f --function-name --trigger-name -t"
+_fcli_trigger_schedule_args="--help -s --service-name -f --function-name --trigger-name -t --next"
+_fcli_trigger_pause_args="--help -s --service-name -f --function-name --all-functions --type"
+_fcli_trigger_template_args="--help --type"
+_fcli_trigger_event_args="--help --type -a --source-arn --format --out-file"
+_fcli_trigger_resume_args="--help -s --service-name -f --function-name --all-functions"
 
 function __fcli_dirs() {
 	find . -type d -depth 1 | sed 's:^./::'
@@ -116,7 +136,15 @@ function _fcli() {
 	elif [ $COMP_CWORD -gt 1 ]; then
 		case "$subcommand" in
 			config)
-				opts="$(__fcli_remove_exist_args $_fcli_config_args)"
+				if [ $COMP_CWORD = 2 ]; then
+					opts="set get $(__fcli_remove_exist_args $_fcli_config_args)"
+				elif [ $COMP_CWORD = 3 ] && [[ "${COMP_WORDS[2]}" =~ ^(set|get)$ ]]; then
+					opts="$_fcli_config_keys"
+				elif [[ "${COMP_WORDS[2]}" =~ ^(set|get)$ ]]; then
+					opts=""
+				else
+					opts="$(__fcli_remove_exist_args $_fcli_config_args)"
+				fi
 				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
 				return 0
 				;;
@@ -340,7 +368,7 @@ function _fcli() {
 				;;
 			trigger)
 				if [ $COMP_CWORD = 2 ]; then
-					opts="$(__fcli_remove_exist_args create update get delete list)"
+					opts="$(__fcli_remove_exist_args create update get delete list schedule pause resume template event)"
 					COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
 					return 0
 				fi
@@ -449,7 +477,192 @@ function _fcli() {
 						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
 						return 0
 						;;
+					schedule)
+						local opts
+						case $prev in 
+							--service-name|-s)
+								opts="$(__fcli_get_all_service_name)"
+								;;
+							--function-name|-f)
+								opts="$(__fcli_get_all_function_name)"
+								;;
+							--trigger-name|-t)
+								opts="$(__fcli_get_all_trigger_name)"
+								;;
+							*)
+								opts="$_fcli_trigger_schedule_args"
+								;;
+						esac
+						opts="$(__fcli_remove_exist_args $opts)"
+						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+						return 0
+						;;
+					event)
+						local opts
+						case $prev in 
+							--type)
+								opts="$_fcli_trigger_event_types"
+								;;
+							--format)
+								opts="json cbor"
+								;;
+							--out-file)
+								opts="$(__fcli_dirs)"
+								;;
+							*)
+								opts="$_fcli_trigger_event_args"
+								;;
+						esac
+						opts="$(__fcli_remove_exist_args $opts)"
+						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+						return 0
+						;;
+					template)
+						local opts
+						case $prev in 
+							--type)
+								opts="$_fcli_trigger_types"
+								;;
+							*)
+								opts="$_fcli_trigger_template_args"
+								;;
+						esac
+						opts="$(__fcli_remove_exist_args $opts)"
+						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+						return 0
+						;;
+					pause)
+						local opts
+						case $prev in 
+							--service-name|-s)
+								opts="$(__fcli_get_all_service_name)"
+								;;
+							--function-name|-f)
+								opts="$(__fcli_get_all_function_name)"
+								;;
+							*)
+								opts="$_fcli_trigger_pause_args"
+								;;
+						esac
+						opts="$(__fcli_remove_exist_args $opts)"
+						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+						return 0
+						;;
+					resume)
+						local opts
+						case $prev in 
+							--service-name|-s)
+								opts="$(__fcli_get_all_service_name)"
+								;;
+							--function-name|-f)
+								opts="$(__fcli_get_all_function_name)"
+								;;
+							*)
+								opts="$_fcli_trigger_resume_args"
+								;;
+						esac
+						opts="$(__fcli_remove_exist_args $opts)"
+						COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+						return 0
+						;;
+				esac
+				;;
+			role)
+				if [ $COMP_CWORD = 2 ]; then
+					opts="$(__fcli_remove_exist_args config grant)"
+					COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+					return 0
+				fi
+				case "$prev" in
+					--scenario)
+						opts="logstore oss-code vpc nas oss-read oss-write mns-trigger tablestore-trigger"
+						;;
+					*)
+						if [ "${COMP_WORDS[2]}" = grant ]; then
+							opts="$(__fcli_remove_exist_args $_fcli_role_grant_args)"
+						else
+							opts="$(__fcli_remove_exist_args $_fcli_role_config_args)"
+						fi
+						;;
+				esac
+				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+				return 0
+				;;
+			mock-server)
+				case "$prev" in
+					--data-dir)
+						COMPREPLY=( $(compgen -d -- ${cur}) )
+						return 0
+						;;
+					*)
+						opts="$(__fcli_remove_exist_args $_fcli_mock_server_args)"
+						;;
+				esac
+				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+				return 0
+				;;
+			inventory)
+				case "$prev" in
+					-o|--output)
+						opts="json markdown"
+						;;
+					*)
+						opts="$(__fcli_remove_exist_args $_fcli_inventory_args)"
+						;;
+				esac
+				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+				return 0
+				;;
+			region)
+				if [ $COMP_CWORD = 2 ]; then
+					opts="list refresh"
+				elif [ "$prev" = --network ]; then
+					opts="public internal vpc"
+				elif [ "${COMP_WORDS[2]}" = list ]; then
+					opts="$(__fcli_remove_exist_args $_fcli_region_list_args)"
+				else
+					opts="$(__fcli_remove_exist_args $_fcli_region_refresh_args)"
+				fi
+				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+				return 0
+				;;
+			ram)
+				if [ $COMP_CWORD = 2 ]; then
+					opts="$(__fcli_remove_exist_args role policy attach detach list-for-role simulate audit generate-policy)"
+					COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+					return 0
+				fi
+				r_cmd=${COMP_WORDS[2]}
+				case "$r_cmd" in
+					role)
+						if [ $COMP_CWORD = 3 ]; then
+							opts="create get list delete update"
+						else
+							opts="$(__fcli_remove_exist_args $_fcli_ram_role_args)"
+						fi
+						;;
+					policy)
+						if [ $COMP_CWORD = 3 ]; then
+							opts="create get list delete versions set-default delete-version"
+						else
+							opts="$(__fcli_remove_exist_args $_fcli_ram_policy_args)"
+						fi
+						;;
+					audit)
+						opts="$(__fcli_remove_exist_args $_fcli_ram_audit_args)"
+						;;
+					generate-policy)
+						opts="$(__fcli_remove_exist_args $_fcli_ram_generate_policy_args)"
+						;;
+					simulate)
+						opts="$(__fcli_remove_exist_args $_fcli_ram_simulate_args)"
+						;;
+					*)
+						opts="$(__fcli_remove_exist_args $_fcli_ram_attach_args)"
+						;;
 				esac
+				COMPREPLY=( $(compgen -W "${opts}" -- ${cur}) )
+				return 0
 				;;
 			help)
 				opts="$(__fcli_remove_exist_args $_fcli_sub_command)"
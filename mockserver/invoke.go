@@ -0,0 +1,84 @@
+package mockserver
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Invocation is the invocation of a function.
+type Invocation struct {
+	ServiceName  string
+	FunctionName string
+	Qualifier    string
+	// the function config, such as runtime, handler and environmentVariables
+	Function map[string]interface{}
+	// the code zip of the function
+	Code    []byte
+	Event   []byte
+	Headers http.Header
+	// Async is true for the asynchronous invocations, whose results are discarded.
+	Async bool
+}
+
+// FunctionError is the error thrown by the function, which is returned with the X-Fc-Error-Type
+// header as the fc does.
+type FunctionError struct {
+	Type    string `json:"errorType"`
+	Message string `json:"errorMessage"`
+}
+
+func (e *FunctionError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Message)
+}
+
+// InvokeHandler handles the invocations of the functions.
+type InvokeHandler interface {
+	// Invoke returns the payload of the invocation and the log of the function,
+	// the *FunctionError is the error of the function.
+	Invoke(inv *Invocation) (payload []byte, log string, err error)
+}
+
+// InvokeHandlerFunc is the function of InvokeHandler.
+type InvokeHandlerFunc func(inv *Invocation) ([]byte, string, error)
+
+// Invoke implements InvokeHandler.
+func (f InvokeHandlerFunc) Invoke(inv *Invocation) ([]byte, string, error) {
+	return f(inv)
+}
+
+// EchoHandler returns the event as the payload.
+var EchoHandler = InvokeHandlerFunc(func(inv *Invocation) ([]byte, string, error) {
+	return inv.Event, fmt.Sprintf("invoke %s/%s with %d bytes\n", inv.ServiceName, inv.FunctionName, len(inv.Event)), nil
+})
+
+// CommandHandler runs the command by sh with the event as stdin, and returns the stdout as the
+// payload and the stderr as the log. The service, the function and the handler are passed by the
+// environment variables FC_SERVICE_NAME, FC_FUNCTION_NAME and FC_FUNCTION_HANDLER, the environment
+// variables of the function are passed too. The function fails if the command exits with non-zero.
+func CommandHandler(command string) InvokeHandler {
+	return InvokeHandlerFunc(func(inv *Invocation) ([]byte, string, error) {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Env = append(os.Environ(),
+			"FC_SERVICE_NAME="+inv.ServiceName,
+			"FC_FUNCTION_NAME="+inv.FunctionName,
+			"FC_QUALIFIER="+inv.Qualifier,
+			fmt.Sprintf("FC_FUNCTION_HANDLER=%v", inv.Function["handler"]))
+		if env, ok := inv.Function["environmentVariables"].(map[string]interface{}); ok {
+			for k, v := range env {
+				cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%v", k, v))
+			}
+		}
+		var stdout, stderr bytes.Buffer
+		cmd.Stdin = bytes.NewReader(inv.Event)
+		cmd.Stdout, cmd.Stderr = &stdout, &stderr
+		if err := cmd.Run(); err != nil {
+			return nil, stderr.String(), &FunctionError{Type: "UnhandledInvocationError",
+				Message: strings.TrimSpace(fmt.Sprintf("%v %s", err, stderr.String()))}
+		}
+		return stdout.Bytes(), stderr.String(), nil
+	})
+}
@@ -0,0 +1,817 @@
+// Package mockserver is a fake of the function compute api which fcli uses, the services,
+// functions, triggers, versions and aliases are kept in memory or on disk, and the invocations
+// are handled by the pluggable handlers.
+package mockserver
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash/crc64"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/satori/go.uuid"
+)
+
+// APIVersion is the version of the fc api served by the mock server.
+const APIVersion = "2016-08-15"
+
+// the path of the code zips, which is the url returned by the get function code api
+const codePathPrefix = "/mock/code/"
+
+const defaultListLimit = 100
+
+// Server is the http handler of the fc api.
+type Server struct {
+	store *Store
+
+	mu       sync.Mutex
+	handlers map[string]InvokeHandler
+	// DefaultHandler handles the invocations of the functions without the handlers, EchoHandler by default.
+	DefaultHandler InvokeHandler
+	// Logf logs the requests, it is nil if the requests are not logged.
+	Logf func(format string, args ...interface{})
+}
+
+// New creates the server of the store.
+func New(store *Store) *Server {
+	return &Server{store: store, handlers: map[string]InvokeHandler{}, DefaultHandler: EchoHandler}
+}
+
+// Handle sets the invoke handler of the function, which overrides the default handler.
+func (s *Server) Handle(serviceName, functionName string, h InvokeHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[serviceName+"/"+functionName] = h
+}
+
+func (s *Server) handler(serviceName, functionName string) InvokeHandler {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if h, ok := s.handlers[serviceName+"/"+functionName]; ok {
+		return h
+	}
+	return s.DefaultHandler
+}
+
+// apiError is the error response of the fc api.
+type apiError struct {
+	status  int
+	code    string
+	message string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("%s: %s", e.code, e.message)
+}
+
+func notFound(code, format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusNotFound, code, fmt.Sprintf(format, args...)}
+}
+
+func conflict(code, format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusConflict, code, fmt.Sprintf(format, args...)}
+}
+
+func invalidArgument(format string, args ...interface{}) *apiError {
+	return &apiError{http.StatusBadRequest, "InvalidArgument", fmt.Sprintf(format, args...)}
+}
+
+// request is the parsed api request.
+type request struct {
+	*http.Request
+	id        string
+	segments  []string
+	qualifier string
+	body      resource
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	id := uuid.Must(uuid.NewV4()).String()
+	w.Header().Set("X-Fc-Request-Id", id)
+	if s.Logf != nil {
+		s.Logf("%s %s", r.Method, r.URL.RequestURI())
+	}
+	if strings.HasPrefix(r.URL.Path, codePathPrefix) {
+		s.serveCode(w, r)
+		return
+	}
+	req := &request{Request: r, id: id}
+	status, result, err := s.serve(w, req)
+	if err != nil {
+		e, ok := err.(*apiError)
+		if !ok {
+			e = &apiError{http.StatusInternalServerError, "InternalServerError", err.Error()}
+		}
+		writeJSON(w, e.status, map[string]string{"ErrorCode": e.code, "ErrorMessage": e.message, "RequestId": id})
+		return
+	}
+	switch v := result.(type) {
+	case nil:
+		w.WriteHeader(status)
+	case []byte:
+		w.WriteHeader(status)
+		w.Write(v)
+	default:
+		writeJSON(w, status, v)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, _ := json.Marshal(v)
+	w.Header().Set("Content-Type", "application/json")
+	if r, ok := v.(resource); ok {
+		w.Header().Set("Etag", fmt.Sprintf("%x", md5.Sum([]byte(r.str("lastModifiedTime")+r.str("createdTime")))))
+	}
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+// serve routes the request and saves the store after the changes.
+func (s *Server) serve(w http.ResponseWriter, r *request) (int, interface{}, error) {
+	path := strings.Trim(r.URL.Path, "/")
+	if !strings.HasPrefix(path, APIVersion+"/services") {
+		return 0, nil, notFound("PathNotSupported", "path %s is not supported", r.URL.Path)
+	}
+	r.segments = strings.Split(strings.TrimPrefix(path, APIVersion+"/"), "/")
+	if len(r.segments) > 1 {
+		// the service name is followed by the qualifier, such as demo.LATEST
+		if i := strings.Index(r.segments[1], "."); i > 0 {
+			r.segments[1], r.qualifier = r.segments[1][:i], r.segments[1][i+1:]
+		}
+	}
+	if r.Method == http.MethodPost || r.Method == http.MethodPut {
+		if err := r.readBody(); err != nil {
+			return 0, nil, err
+		}
+	}
+	if len(r.segments) >= 5 && r.segments[2] == "functions" && r.segments[4] == "invocations" {
+		return s.invoke(w, r)
+	}
+
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	status, result, err := s.route(r)
+	if err == nil && r.Method != http.MethodGet {
+		err = s.store.save()
+	}
+	return status, result, err
+}
+
+func (r *request) readBody() error {
+	if strings.HasSuffix(r.URL.Path, "/invocations") {
+		return nil
+	}
+	data, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	r.body = resource{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &r.body); err != nil {
+			return invalidArgument("invalid json body: %v", err)
+		}
+	}
+	return nil
+}
+
+func (s *Server) route(r *request) (int, interface{}, error) {
+	seg := r.segments
+	switch {
+	case len(seg) == 1:
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.listServices, http.MethodPost: s.createService})
+	case len(seg) == 2:
+		return dispatch(r, map[string]handlerFunc{
+			http.MethodGet: s.getService, http.MethodPut: s.updateService, http.MethodDelete: s.deleteService})
+	case seg[2] == "functions" && len(seg) == 3:
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.listFunctions, http.MethodPost: s.createFunction})
+	case seg[2] == "functions" && len(seg) == 4:
+		return dispatch(r, map[string]handlerFunc{
+			http.MethodGet: s.getFunction, http.MethodPut: s.updateFunction, http.MethodDelete: s.deleteFunction})
+	case seg[2] == "functions" && len(seg) == 5 && seg[4] == "code":
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.getFunctionCode})
+	case seg[2] == "functions" && len(seg) == 5 && seg[4] == "triggers":
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.listTriggers, http.MethodPost: s.createTrigger})
+	case seg[2] == "functions" && len(seg) == 6 && seg[4] == "triggers":
+		return dispatch(r, map[string]handlerFunc{
+			http.MethodGet: s.getTrigger, http.MethodPut: s.updateTrigger, http.MethodDelete: s.deleteTrigger})
+	case seg[2] == "versions" && len(seg) == 3:
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.listVersions, http.MethodPost: s.publishVersion})
+	case seg[2] == "versions" && len(seg) == 4:
+		return dispatch(r, map[string]handlerFunc{http.MethodDelete: s.deleteVersion})
+	case seg[2] == "aliases" && len(seg) == 3:
+		return dispatch(r, map[string]handlerFunc{http.MethodGet: s.listAliases, http.MethodPost: s.createAlias})
+	case seg[2] == "aliases" && len(seg) == 4:
+		return dispatch(r, map[string]handlerFunc{
+			http.MethodGet: s.getAlias, http.MethodPut: s.updateAlias, http.MethodDelete: s.deleteAlias})
+	}
+	return 0, nil, notFound("PathNotSupported", "path %s is not supported", r.URL.Path)
+}
+
+type handlerFunc func(r *request) (int, interface{}, error)
+
+func dispatch(r *request, handlers map[string]handlerFunc) (int, interface{}, error) {
+	h, ok := handlers[r.Method]
+	if !ok {
+		return 0, nil, &apiError{http.StatusMethodNotAllowed, "MethodNotAllowed",
+			fmt.Sprintf("method %s is not allowed on %s", r.Method, r.URL.Path)}
+	}
+	return h(r)
+}
+
+func now() string {
+	return time.Now().UTC().Format(time.RFC3339)
+}
+
+// newResource stamps the id and the times of the created resource.
+func newResource(body resource, idKey string) resource {
+	res := resource{}
+	res.merge(body)
+	if idKey != "" {
+		res[idKey] = uuid.Must(uuid.NewV4()).String()
+	}
+	res["createdTime"] = now()
+	res["lastModifiedTime"] = res["createdTime"]
+	return res
+}
+
+// updateResource merges the update except the name and the system fields.
+func updateResource(res, update resource, nameKey string) {
+	for _, k := range []string{nameKey, "createdTime", "lastModifiedTime"} {
+		delete(update, k)
+	}
+	res.merge(update)
+	res["lastModifiedTime"] = now()
+}
+
+// listPage is the page of the names of the listing query.
+func listPage(r *request, names []string) ([]string, string, error) {
+	q := r.URL.Query()
+	limit := defaultListLimit
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil || n <= 0 {
+			return nil, "", invalidArgument("invalid limit %s", l)
+		}
+		limit = n
+	}
+	items, next := page(names, q.Get("prefix"), q.Get("startKey"), q.Get("nextToken"), limit)
+	return items, next, nil
+}
+
+func listResult(key string, items []interface{}, next string) map[string]interface{} {
+	result := map[string]interface{}{key: items}
+	if next != "" {
+		result["nextToken"] = next
+	}
+	return result
+}
+
+// ---- services
+
+func (s *Server) service(r *request) (*serviceState, error) {
+	name := r.segments[1]
+	service, ok := s.store.Services[name]
+	if !ok {
+		return nil, notFound("ServiceNotFound", "service '%s' does not exist", name)
+	}
+	return service, checkQualifier(service, r.qualifier)
+}
+
+// checkQualifier checks the qualifier is LATEST, a version or an alias of the service.
+func checkQualifier(service *serviceState, qualifier string) error {
+	if qualifier == "" || qualifier == "LATEST" {
+		return nil
+	}
+	if _, ok := service.Versions[qualifier]; ok {
+		return nil
+	}
+	if _, ok := service.Aliases[qualifier]; ok {
+		return nil
+	}
+	if _, err := strconv.Atoi(qualifier); err == nil {
+		return notFound("VersionNotFound", "version '%s' does not exist", qualifier)
+	}
+	return notFound("AliasNotFound", "alias '%s' does not exist", qualifier)
+}
+
+func (s *Server) listServices(r *request) (int, interface{}, error) {
+	names := []string{}
+	for name := range s.store.Services {
+		names = append(names, name)
+	}
+	names, next, err := listPage(r, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	items := []interface{}{}
+	for _, name := range names {
+		items = append(items, s.store.Services[name].Service)
+	}
+	return http.StatusOK, listResult("services", items, next), nil
+}
+
+func (s *Server) createService(r *request) (int, interface{}, error) {
+	name := r.body.str("serviceName")
+	if name == "" {
+		return 0, nil, invalidArgument("serviceName is required")
+	}
+	if !namePattern.MatchString(name) {
+		return 0, nil, invalidArgument("invalid serviceName '%s', which must match %s", name, namePattern)
+	}
+	if _, ok := s.store.Services[name]; ok {
+		return 0, nil, conflict("ServiceAlreadyExists", "service '%s' already exists", name)
+	}
+	service := &serviceState{
+		Service:   newResource(r.body, "serviceId"),
+		Functions: map[string]*functionState{},
+		Versions:  map[string]resource{},
+		Aliases:   map[string]resource{},
+	}
+	s.store.Services[name] = service
+	return http.StatusOK, service.Service, nil
+}
+
+func (s *Server) getService(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, service.Service, nil
+}
+
+func (s *Server) updateService(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	updateResource(service.Service, r.body, "serviceName")
+	return http.StatusOK, service.Service, nil
+}
+
+func (s *Server) deleteService(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(service.Functions) > 0 {
+		return 0, nil, &apiError{http.StatusPreconditionFailed, "ServiceNotEmpty",
+			fmt.Sprintf("service '%s' is not empty", r.segments[1])}
+	}
+	delete(s.store.Services, r.segments[1])
+	return http.StatusNoContent, nil, nil
+}
+
+// ---- functions
+
+func (s *Server) function(r *request) (*serviceState, *functionState, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	name := r.segments[3]
+	function, ok := service.Functions[name]
+	if !ok {
+		return nil, nil, notFound("FunctionNotFound", "function '%s' does not exist", name)
+	}
+	return service, function, nil
+}
+
+// setCode decodes the code zip of the function input, the oss code is not fetched.
+func setCode(function *functionState, body resource) error {
+	code, ok := body["code"].(map[string]interface{})
+	delete(body, "code")
+	if !ok {
+		return nil
+	}
+	function.Code = nil
+	if zipFile, ok := code["zipFile"].(string); ok {
+		b, err := base64.StdEncoding.DecodeString(zipFile)
+		if err != nil {
+			return invalidArgument("invalid code.zipFile: %v", err)
+		}
+		function.Code = b
+	}
+	function.Function["codeSize"] = len(function.Code)
+	function.Function["codeChecksum"] = strconv.FormatUint(crc64.Checksum(function.Code, crc64.MakeTable(crc64.ECMA)), 10)
+	return nil
+}
+
+func (s *Server) listFunctions(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	names := []string{}
+	for name := range service.Functions {
+		names = append(names, name)
+	}
+	names, next, err := listPage(r, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	items := []interface{}{}
+	for _, name := range names {
+		items = append(items, service.Functions[name].Function)
+	}
+	return http.StatusOK, listResult("functions", items, next), nil
+}
+
+func (s *Server) createFunction(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	name := r.body.str("functionName")
+	if name == "" || r.body.str("runtime") == "" || r.body.str("handler") == "" {
+		return 0, nil, invalidArgument("functionName, runtime and handler are required")
+	}
+	if !namePattern.MatchString(name) {
+		return 0, nil, invalidArgument("invalid functionName '%s', which must match %s", name, namePattern)
+	}
+	if _, ok := service.Functions[name]; ok {
+		return 0, nil, conflict("FunctionAlreadyExists", "function '%s' already exists", name)
+	}
+	function := &functionState{Triggers: map[string]resource{}}
+	function.Function = resource{"timeout": 3, "memorySize": 128}
+	if err := setCode(function, r.body); err != nil {
+		return 0, nil, err
+	}
+	function.Function.merge(newResource(r.body, "functionId"))
+	service.Functions[name] = function
+	return http.StatusOK, function.Function, nil
+}
+
+func (s *Server) getFunction(r *request) (int, interface{}, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, function.Function, nil
+}
+
+func (s *Server) updateFunction(r *request) (int, interface{}, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := setCode(function, r.body); err != nil {
+		return 0, nil, err
+	}
+	updateResource(function.Function, r.body, "functionName")
+	return http.StatusOK, function.Function, nil
+}
+
+func (s *Server) deleteFunction(r *request) (int, interface{}, error) {
+	service, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(function.Triggers) > 0 {
+		return 0, nil, &apiError{http.StatusPreconditionFailed, "FunctionNotEmpty",
+			fmt.Sprintf("function '%s' still has triggers", r.segments[3])}
+	}
+	delete(service.Functions, r.segments[3])
+	return http.StatusNoContent, nil, nil
+}
+
+func (s *Server) getFunctionCode(r *request) (int, interface{}, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, map[string]interface{}{
+		"url":      fmt.Sprintf("http://%s%s%s/%s.zip", r.Host, codePathPrefix, r.segments[1], r.segments[3]),
+		"checksum": function.Function["codeChecksum"],
+	}, nil
+}
+
+// serveCode serves the code zip of /mock/code/<service>/<function>.zip
+func (s *Server) serveCode(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, codePathPrefix), ".zip"), "/")
+	s.store.mu.Lock()
+	defer s.store.mu.Unlock()
+	if len(parts) == 2 {
+		if service, ok := s.store.Services[parts[0]]; ok {
+			if function, ok := service.Functions[parts[1]]; ok {
+				w.Header().Set("Content-Type", "application/zip")
+				w.Write(function.Code)
+				return
+			}
+		}
+	}
+	http.NotFound(w, r)
+}
+
+// invoke calls the handler of the function without the lock of the store.
+func (s *Server) invoke(w http.ResponseWriter, r *request) (int, interface{}, error) {
+	if r.Method != http.MethodPost {
+		return dispatch(r, nil)
+	}
+	event, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return 0, nil, err
+	}
+	s.store.mu.Lock()
+	_, function, err := s.function(r)
+	var inv *Invocation
+	if err == nil {
+		inv = &Invocation{
+			ServiceName:  r.segments[1],
+			FunctionName: r.segments[3],
+			Qualifier:    r.qualifier,
+			Function:     map[string]interface{}{},
+			Code:         function.Code,
+			Event:        event,
+			Headers:      r.Header,
+			Async:        strings.EqualFold(r.Header.Get("X-Fc-Invocation-Type"), "Async"),
+		}
+		resource(inv.Function).merge(function.Function)
+	}
+	s.store.mu.Unlock()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	h := s.handler(inv.ServiceName, inv.FunctionName)
+	if inv.Async {
+		go h.Invoke(inv)
+		return http.StatusAccepted, nil, nil
+	}
+	payload, log, err := h.Invoke(inv)
+	if strings.EqualFold(r.Header.Get("X-Fc-Log-Type"), "Tail") {
+		w.Header().Set("X-Fc-Log-Result", base64.StdEncoding.EncodeToString([]byte(log)))
+	}
+	if fe, ok := err.(*FunctionError); ok {
+		w.Header().Set("X-Fc-Error-Type", fe.Type)
+		b, _ := json.Marshal(fe)
+		return http.StatusOK, b, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, payload, nil
+}
+
+// ---- triggers
+
+func (s *Server) listTriggers(r *request) (int, interface{}, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	names := []string{}
+	for name := range function.Triggers {
+		names = append(names, name)
+	}
+	names, next, err := listPage(r, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	items := []interface{}{}
+	for _, name := range names {
+		items = append(items, function.Triggers[name])
+	}
+	return http.StatusOK, listResult("triggers", items, next), nil
+}
+
+func (s *Server) createTrigger(r *request) (int, interface{}, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	name := r.body.str("triggerName")
+	if name == "" || r.body.str("triggerType") == "" {
+		return 0, nil, invalidArgument("triggerName and triggerType are required")
+	}
+	if _, ok := function.Triggers[name]; ok {
+		return 0, nil, conflict("TriggerAlreadyExists", "trigger '%s' already exists", name)
+	}
+	function.Triggers[name] = newResource(r.body, "")
+	return http.StatusOK, function.Triggers[name], nil
+}
+
+func (s *Server) trigger(r *request) (*functionState, resource, error) {
+	_, function, err := s.function(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	trigger, ok := function.Triggers[r.segments[5]]
+	if !ok {
+		return nil, nil, notFound("TriggerNotFound", "trigger '%s' does not exist", r.segments[5])
+	}
+	return function, trigger, nil
+}
+
+func (s *Server) getTrigger(r *request) (int, interface{}, error) {
+	_, trigger, err := s.trigger(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, trigger, nil
+}
+
+func (s *Server) updateTrigger(r *request) (int, interface{}, error) {
+	_, trigger, err := s.trigger(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	delete(r.body, "triggerType")
+	updateResource(trigger, r.body, "triggerName")
+	return http.StatusOK, trigger, nil
+}
+
+func (s *Server) deleteTrigger(r *request) (int, interface{}, error) {
+	function, _, err := s.trigger(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	delete(function.Triggers, r.segments[5])
+	return http.StatusNoContent, nil, nil
+}
+
+// ---- versions
+
+func (s *Server) listVersions(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	ids := []int{}
+	for id := range service.Versions {
+		n, _ := strconv.Atoi(id)
+		ids = append(ids, n)
+	}
+	forward := strings.EqualFold(r.URL.Query().Get("direction"), "FORWARD")
+	sort.Slice(ids, func(i, j int) bool {
+		if forward {
+			return ids[i] < ids[j]
+		}
+		return ids[i] > ids[j]
+	})
+	q := r.URL.Query()
+	start := q.Get("startKey")
+	if q.Get("nextToken") != "" {
+		start = q.Get("nextToken")
+	}
+	limit := defaultListLimit
+	if l, err := strconv.Atoi(q.Get("limit")); err == nil && l > 0 {
+		limit = l
+	}
+	items, next := []interface{}{}, ""
+	started := start == ""
+	for _, id := range ids {
+		key := strconv.Itoa(id)
+		if !started {
+			started = key == start
+			if !started {
+				continue
+			}
+		}
+		if len(items) == limit {
+			next = key
+			break
+		}
+		items = append(items, service.Versions[key])
+	}
+	result := listResult("versions", items, next)
+	if forward {
+		result["direction"] = "FORWARD"
+	} else {
+		result["direction"] = "BACKWARD"
+	}
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) publishVersion(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	service.LastVersion++
+	id := strconv.Itoa(service.LastVersion)
+	version := newResource(resource{"description": r.body["description"]}, "")
+	version["versionId"] = id
+	service.Versions[id] = version
+	return http.StatusOK, version, nil
+}
+
+func (s *Server) deleteVersion(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	id := r.segments[3]
+	if _, ok := service.Versions[id]; !ok {
+		return 0, nil, notFound("VersionNotFound", "version '%s' does not exist", id)
+	}
+	for name, alias := range service.Aliases {
+		if alias.str("versionId") == id {
+			return 0, nil, &apiError{http.StatusPreconditionFailed, "VersionReferenced",
+				fmt.Sprintf("version '%s' is referenced by alias '%s'", id, name)}
+		}
+	}
+	delete(service.Versions, id)
+	return http.StatusNoContent, nil, nil
+}
+
+// ---- aliases
+
+func (s *Server) listAliases(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	names := []string{}
+	for name := range service.Aliases {
+		names = append(names, name)
+	}
+	names, next, err := listPage(r, names)
+	if err != nil {
+		return 0, nil, err
+	}
+	items := []interface{}{}
+	for _, name := range names {
+		items = append(items, service.Aliases[name])
+	}
+	return http.StatusOK, listResult("aliases", items, next), nil
+}
+
+func checkAliasVersion(service *serviceState, body resource) error {
+	id, ok := body["versionId"]
+	if !ok {
+		return nil
+	}
+	if _, exists := service.Versions[fmt.Sprint(id)]; !exists {
+		return notFound("VersionNotFound", "version '%v' does not exist", id)
+	}
+	return nil
+}
+
+func (s *Server) createAlias(r *request) (int, interface{}, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	name := r.body.str("aliasName")
+	if name == "" || r.body["versionId"] == nil {
+		return 0, nil, invalidArgument("aliasName and versionId are required")
+	}
+	if err := checkAliasVersion(service, r.body); err != nil {
+		return 0, nil, err
+	}
+	if _, ok := service.Aliases[name]; ok {
+		return 0, nil, conflict("AliasAlreadyExists", "alias '%s' already exists", name)
+	}
+	service.Aliases[name] = newResource(r.body, "")
+	return http.StatusOK, service.Aliases[name], nil
+}
+
+func (s *Server) alias(r *request) (*serviceState, resource, error) {
+	service, err := s.service(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	alias, ok := service.Aliases[r.segments[3]]
+	if !ok {
+		return nil, nil, notFound("AliasNotFound", "alias '%s' does not exist", r.segments[3])
+	}
+	return service, alias, nil
+}
+
+func (s *Server) getAlias(r *request) (int, interface{}, error) {
+	_, alias, err := s.alias(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, alias, nil
+}
+
+func (s *Server) updateAlias(r *request) (int, interface{}, error) {
+	service, alias, err := s.alias(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := checkAliasVersion(service, r.body); err != nil {
+		return 0, nil, err
+	}
+	updateResource(alias, r.body, "aliasName")
+	return http.StatusOK, alias, nil
+}
+
+func (s *Server) deleteAlias(r *request) (int, interface{}, error) {
+	service, _, err := s.alias(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	delete(service.Aliases, r.segments[3])
+	return http.StatusNoContent, nil, nil
+}
@@ -0,0 +1,262 @@
+package mockserver
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+)
+
+type MockServerTestSuite struct {
+	suite.Suite
+	dir    string
+	server *Server
+	http   *httptest.Server
+}
+
+func (s *MockServerTestSuite) SetupTest() {
+	dir, err := ioutil.TempDir("", "fcli-mock-server")
+	s.Require().Nil(err)
+	s.dir = dir
+	store, err := NewStore(dir)
+	s.Require().Nil(err)
+	s.server = New(store)
+	s.http = httptest.NewServer(s.server)
+}
+
+func (s *MockServerTestSuite) TearDownTest() {
+	s.http.Close()
+	os.RemoveAll(s.dir)
+}
+
+// call sends the api request and decodes the json response.
+func (s *MockServerTestSuite) call(method, path string, body interface{}, header map[string]string) (
+	*http.Response, map[string]interface{}) {
+	var data []byte
+	switch b := body.(type) {
+	case nil:
+	case []byte:
+		data = b
+	default:
+		data, _ = json.Marshal(b)
+	}
+	req, err := http.NewRequest(method, s.http.URL+"/"+APIVersion+path, bytes.NewReader(data))
+	s.Require().Nil(err)
+	for k, v := range header {
+		req.Header.Set(k, v)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	s.Require().Nil(err)
+	defer resp.Body.Close()
+	raw, err := ioutil.ReadAll(resp.Body)
+	s.Require().Nil(err)
+	result := map[string]interface{}{}
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, &result); err != nil {
+			result["raw"] = string(raw)
+		}
+	}
+	return resp, result
+}
+
+func (s *MockServerTestSuite) TestServices() {
+	assert := s.Require()
+	for _, name := range []string{"demo-b", "demo-a", "other"} {
+		resp, result := s.call(http.MethodPost, "/services", map[string]interface{}{
+			"serviceName": name, "description": "the " + name}, nil)
+		assert.Equal(http.StatusOK, resp.StatusCode)
+		assert.NotEmpty(result["serviceId"])
+		assert.NotEmpty(resp.Header.Get("X-Fc-Request-Id"))
+	}
+	resp, result := s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo-a"}, nil)
+	assert.Equal(http.StatusConflict, resp.StatusCode)
+	assert.Equal("ServiceAlreadyExists", result["ErrorCode"])
+
+	_, result = s.call(http.MethodGet, "/services?prefix=demo&limit=1", nil, nil)
+	assert.Len(result["services"], 1)
+	assert.Equal("demo-a", result["services"].([]interface{})[0].(map[string]interface{})["serviceName"])
+	assert.Equal("demo-b", result["nextToken"])
+	_, result = s.call(http.MethodGet, "/services?prefix=demo&nextToken=demo-b", nil, nil)
+	assert.Len(result["services"], 1)
+	assert.Nil(result["nextToken"])
+
+	_, result = s.call(http.MethodPut, "/services/demo-a", map[string]interface{}{"description": "updated"}, nil)
+	assert.Equal("updated", result["description"])
+	assert.Equal("demo-a", result["serviceName"])
+
+	resp, result = s.call(http.MethodGet, "/services/missing", nil, nil)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+	assert.Equal("ServiceNotFound", result["ErrorCode"])
+	resp, _ = s.call(http.MethodDelete, "/services/demo-a", nil, nil)
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *MockServerTestSuite) TestFunctionsAndTriggers() {
+	assert := s.Require()
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	code := []byte("PK\x03\x04 zip")
+	resp, result := s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{
+		"functionName": "hello", "runtime": "nodejs8", "handler": "index.handler",
+		"code": map[string]interface{}{"zipFile": base64.StdEncoding.EncodeToString(code)}}, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(float64(len(code)), result["codeSize"])
+	assert.Nil(result["code"])
+	resp, result = s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{"functionName": "bad"}, nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("InvalidArgument", result["ErrorCode"])
+
+	_, result = s.call(http.MethodGet, "/services/demo/functions/hello/code", nil, nil)
+	codeResp, err := http.Get(result["url"].(string))
+	assert.Nil(err)
+	downloaded, _ := ioutil.ReadAll(codeResp.Body)
+	codeResp.Body.Close()
+	assert.Equal(code, downloaded)
+
+	resp, _ = s.call(http.MethodPost, "/services/demo/functions/hello/triggers", map[string]interface{}{
+		"triggerName": "timer", "triggerType": "timer", "triggerConfig": map[string]interface{}{"enable": true}}, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	_, result = s.call(http.MethodGet, "/services/demo/functions/hello/triggers", nil, nil)
+	assert.Len(result["triggers"], 1)
+
+	resp, result = s.call(http.MethodDelete, "/services/demo/functions/hello", nil, nil)
+	assert.Equal(http.StatusPreconditionFailed, resp.StatusCode)
+	assert.Equal("FunctionNotEmpty", result["ErrorCode"])
+	resp, _ = s.call(http.MethodDelete, "/services/demo/functions/hello/triggers/timer", nil, nil)
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+	_, result = s.call(http.MethodDelete, "/services/demo", nil, nil)
+	assert.Equal("ServiceNotEmpty", result["ErrorCode"])
+}
+
+func (s *MockServerTestSuite) TestVersionsAndAliases() {
+	assert := s.Require()
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	for i := 0; i < 3; i++ {
+		s.call(http.MethodPost, "/services/demo/versions", map[string]interface{}{"description": "v"}, nil)
+	}
+	_, result := s.call(http.MethodGet, "/services/demo/versions?limit=2", nil, nil)
+	versions := result["versions"].([]interface{})
+	assert.Equal("3", versions[0].(map[string]interface{})["versionId"])
+	assert.Equal("1", result["nextToken"])
+
+	resp, result := s.call(http.MethodPost, "/services/demo/aliases", map[string]interface{}{
+		"aliasName": "prod", "versionId": "9"}, nil)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+	assert.Equal("VersionNotFound", result["ErrorCode"])
+	resp, _ = s.call(http.MethodPost, "/services/demo/aliases", map[string]interface{}{
+		"aliasName": "prod", "versionId": "2"}, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	_, result = s.call(http.MethodPut, "/services/demo/aliases/prod", map[string]interface{}{"versionId": "3"}, nil)
+	assert.Equal("3", result["versionId"])
+
+	resp, _ = s.call(http.MethodGet, "/services/demo.prod", nil, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	_, result = s.call(http.MethodGet, "/services/demo.staging", nil, nil)
+	assert.Equal("AliasNotFound", result["ErrorCode"])
+	resp, _ = s.call(http.MethodDelete, "/services/demo/versions/3", nil, nil)
+	assert.Equal(http.StatusPreconditionFailed, resp.StatusCode)
+	resp, _ = s.call(http.MethodDelete, "/services/demo/versions/1", nil, nil)
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+}
+
+func (s *MockServerTestSuite) TestInvoke() {
+	assert := s.Require()
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	for _, name := range []string{"echo", "shell", "fail"} {
+		s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{
+			"functionName": name, "runtime": "custom", "handler": "index.handler",
+			"environmentVariables": map[string]interface{}{"GREETING": "hi"}}, nil)
+	}
+	s.server.Handle("demo", "shell", CommandHandler(`echo "$GREETING $FC_FUNCTION_NAME $(cat)"; echo logged >&2`))
+	s.server.Handle("demo", "fail", CommandHandler(`exit 3`))
+
+	resp, result := s.call(http.MethodPost, "/services/demo/functions/echo/invocations", []byte(`{"a":1}`), nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal(float64(1), result["a"])
+
+	resp, result = s.call(http.MethodPost, "/services/demo/functions/shell/invocations", []byte("event"),
+		map[string]string{"X-Fc-Log-Type": "Tail"})
+	assert.Equal("hi shell event\n", result["raw"])
+	log, _ := base64.StdEncoding.DecodeString(resp.Header.Get("X-Fc-Log-Result"))
+	assert.Equal("logged\n", string(log))
+
+	resp, result = s.call(http.MethodPost, "/services/demo/functions/fail/invocations", nil, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	assert.Equal("UnhandledInvocationError", resp.Header.Get("X-Fc-Error-Type"))
+	assert.Equal("UnhandledInvocationError", result["errorType"])
+
+	resp, _ = s.call(http.MethodPost, "/services/demo/functions/echo/invocations", nil,
+		map[string]string{"X-Fc-Invocation-Type": "Async"})
+	assert.Equal(http.StatusAccepted, resp.StatusCode)
+	resp, result = s.call(http.MethodPost, "/services/demo/functions/missing/invocations", nil, nil)
+	assert.Equal(http.StatusNotFound, resp.StatusCode)
+	assert.Equal("FunctionNotFound", result["ErrorCode"])
+}
+
+func (s *MockServerTestSuite) TestStoreOnDisk() {
+	assert := s.Require()
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{
+		"functionName": "hello", "runtime": "python3", "handler": "index.handler",
+		"code": map[string]interface{}{"zipFile": base64.StdEncoding.EncodeToString([]byte("zip"))}}, nil)
+
+	store, err := NewStore(s.dir)
+	assert.Nil(err)
+	assert.Contains(store.Services, "demo")
+	function := store.Services["demo"].Functions["hello"]
+	assert.Equal("python3", function.Function.str("runtime"))
+	assert.Equal([]byte("zip"), function.Code)
+}
+
+func (s *MockServerTestSuite) TestInvalidNames() {
+	assert := s.Require()
+	resp, result := s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "../demo"}, nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("InvalidArgument", result["ErrorCode"])
+
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	resp, result = s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{
+		"functionName": "../../hello", "runtime": "python3", "handler": "index.handler"}, nil)
+	assert.Equal(http.StatusBadRequest, resp.StatusCode)
+	assert.Equal("InvalidArgument", result["ErrorCode"])
+}
+
+func (s *MockServerTestSuite) TestStoreSavesChanges() {
+	assert := s.Require()
+	s.call(http.MethodPost, "/services", map[string]interface{}{"serviceName": "demo"}, nil)
+	s.call(http.MethodPost, "/services/demo/functions", map[string]interface{}{
+		"functionName": "hello", "runtime": "python3", "handler": "index.handler",
+		"code": map[string]interface{}{"zipFile": base64.StdEncoding.EncodeToString([]byte("zip"))}}, nil)
+
+	// the unchanged code zip is not written again
+	codePath := s.server.store.codePath("demo", "hello")
+	assert.Nil(ioutil.WriteFile(codePath, []byte("marker"), 0644))
+	resp, _ := s.call(http.MethodPut, "/services/demo", map[string]interface{}{"description": "updated"}, nil)
+	assert.Equal(http.StatusOK, resp.StatusCode)
+	data, err := ioutil.ReadFile(codePath)
+	assert.Nil(err)
+	assert.Equal("marker", string(data))
+
+	resp, _ = s.call(http.MethodDelete, "/services/demo/functions/hello", nil, nil)
+	assert.Equal(http.StatusNoContent, resp.StatusCode)
+	_, err = os.Stat(codePath)
+	assert.True(os.IsNotExist(err))
+
+	// no temporary file is left
+	files, err := ioutil.ReadDir(s.dir)
+	assert.Nil(err)
+	names := []string{}
+	for _, f := range files {
+		names = append(names, f.Name())
+	}
+	assert.Equal([]string{"code", stateFile}, names)
+}
+
+func TestMockServerTestSuite(t *testing.T) {
+	suite.Run(t, new(MockServerTestSuite))
+}
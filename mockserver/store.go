@@ -0,0 +1,198 @@
+package mockserver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const stateFile = "state.json"
+
+// the valid service and function names, which are used as the paths of the code zips
+var namePattern = regexp.MustCompile(`^[_a-zA-Z][-_a-zA-Z0-9]{0,127}$`)
+
+// resource is the json object of a service, function, trigger, version or alias. The fields sent by
+// the clients are kept as is, so the fields which are not known by the server are returned too.
+type resource map[string]interface{}
+
+func (r resource) str(key string) string {
+	s, _ := r[key].(string)
+	return s
+}
+
+// merge sets the fields of the update which are not null.
+func (r resource) merge(update resource) {
+	for k, v := range update {
+		if v != nil {
+			r[k] = v
+		}
+	}
+}
+
+type serviceState struct {
+	Service     resource                  `json:"service"`
+	Functions   map[string]*functionState `json:"functions"`
+	Versions    map[string]resource       `json:"versions"`
+	Aliases     map[string]resource       `json:"aliases"`
+	LastVersion int                       `json:"lastVersion"`
+}
+
+type functionState struct {
+	Function resource            `json:"function"`
+	Triggers map[string]resource `json:"triggers"`
+	// the code zip, which is stored in the code directory when the state is on disk
+	Code []byte `json:"-"`
+}
+
+// Store is the state of the mock server, which is kept in memory and written to the directory
+// after each change if it is not empty.
+type Store struct {
+	mu       sync.Mutex
+	dir      string
+	Services map[string]*serviceState `json:"services"`
+
+	// the state and the checksums of the code zips on disk, only the changes are written
+	state []byte
+	codes map[string][sha256.Size]byte
+}
+
+// NewStore creates the store in memory if dir is empty, otherwise the store is loaded from the dir.
+func NewStore(dir string) (*Store, error) {
+	s := &Store{dir: dir, Services: map[string]*serviceState{}, codes: map[string][sha256.Size]byte{}}
+	if dir == "" {
+		return s, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Join(dir, stateFile))
+	if os.IsNotExist(err) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, err
+	}
+	s.state = data
+	for serviceName, service := range s.Services {
+		if !namePattern.MatchString(serviceName) {
+			return nil, fmt.Errorf("invalid service name '%s' in %s", serviceName, stateFile)
+		}
+		for functionName, function := range service.Functions {
+			if !namePattern.MatchString(functionName) {
+				return nil, fmt.Errorf("invalid function name '%s' in %s", functionName, stateFile)
+			}
+			p := s.codePath(serviceName, functionName)
+			function.Code, err = ioutil.ReadFile(p)
+			if err != nil && !os.IsNotExist(err) {
+				return nil, err
+			}
+			if err == nil {
+				s.codes[p] = sha256.Sum256(function.Code)
+			}
+		}
+	}
+	return s, nil
+}
+
+func (s *Store) codePath(serviceName, functionName string) string {
+	return filepath.Join(s.dir, "code", serviceName, functionName+".zip")
+}
+
+// save writes the changed code zips and state to the directory, the lock is held by the caller.
+func (s *Store) save() error {
+	if s.dir == "" {
+		return nil
+	}
+	codes := map[string][sha256.Size]byte{}
+	for serviceName, service := range s.Services {
+		for functionName, function := range service.Functions {
+			p := s.codePath(serviceName, functionName)
+			codes[p] = sha256.Sum256(function.Code)
+			if sum, ok := s.codes[p]; ok && sum == codes[p] {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(p), 0755); err != nil {
+				return err
+			}
+			if err := writeFileAtomic(p, function.Code); err != nil {
+				return err
+			}
+			s.codes[p] = codes[p]
+		}
+	}
+	for p := range s.codes {
+		if _, ok := codes[p]; ok {
+			continue
+		}
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(s.codes, p)
+		// the directory of the service is removed once it is empty
+		os.Remove(filepath.Dir(p))
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if bytes.Equal(data, s.state) {
+		return nil
+	}
+	if err := writeFileAtomic(filepath.Join(s.dir, stateFile), data); err != nil {
+		return err
+	}
+	s.state = data
+	return nil
+}
+
+// writeFileAtomic writes the data to a temporary file in the same directory and renames it to the path,
+// so that the file is never left partially written.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := ioutil.TempFile(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Chmod(f.Name(), 0644); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// page returns the page of the sorted names with the prefix, which starts from the start key or the
+// next token, and the next token of the following page.
+func page(names []string, prefix, startKey, nextToken string, limit int) ([]string, string) {
+	sort.Strings(names)
+	if nextToken != "" {
+		startKey = nextToken
+	}
+	result := []string{}
+	for _, name := range names {
+		if !strings.HasPrefix(name, prefix) || name < startKey {
+			continue
+		}
+		if len(result) == limit {
+			return result, name
+		}
+		result = append(result, name)
+	}
+	return result, ""
+}
@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
@@ -28,8 +29,13 @@ type Client struct {
 	endpoint        *url.URL
 	option          *ClientOption
 	hclient         *http.Client
+	credentials     CredentialsFunc
 }
 
+// CredentialsFunc returns the credentials to sign each request, so that the temporary
+// credentials can be refreshed before they expire.
+type CredentialsFunc func() (accessKeyID, accessKeySecret, securityToken string, err error)
+
 func (option *ClientOption) setRetryTimes(retry int32) {
 	option.retryTimes = retry
 }
@@ -93,13 +99,26 @@ func NewClient(endpoint, accessKeyID, accessKeySecret string) (client *Client, e
 	return
 }
 
+// WithCredentials signs the requests with the credentials returned by f instead of the access key
+// passed to NewClient.
+func (c *Client) WithCredentials(f CredentialsFunc) *Client {
+	c.credentials = f
+	return c
+}
+
+// WrapTransport wraps the http transport of the client, such as to trace the requests.
+func (c *Client) WrapTransport(wrap func(http.RoundTripper) http.RoundTripper) *Client {
+	c.hclient.Transport = wrap(c.hclient.Transport)
+	return c
+}
+
 // WithRetryTimes : 进行可重入错误重试的次数, 目前对url.Error和500以及503错误进行重试
 func (c *Client) WithRetryTimes(retry int32) *Client {
 	c.option.setRetryTimes(retry)
 	return c
 }
 
-func (c *Client) getCommontParam() map[string]string {
+func (c *Client) getCommontParam(accessKeyID string) map[string]string {
 	u, err := uuid.NewV4()
 	if err != nil {
 		panic(err)
@@ -108,7 +127,7 @@ func (c *Client) getCommontParam() map[string]string {
 		"Format":           "JSON",
 		"Version":          "2015-05-01",
 		"SignatureMethod":  "HMAC-SHA1",
-		"AccessKeyId":      c.accessKeyID,
+		"AccessKeyId":      accessKeyID,
 		"SignatureVersion": "1.0",
 		"SignatureNonce":   u.String(),
 		"Timestamp":        time.Now().UTC().Format(time.RFC3339),
@@ -179,11 +198,18 @@ func (c *Client) DeleteRole(roleName string) (*DeleteRoleResponse, error) {
 	return resp, nil
 }
 
-// ListRoles :
+// ListRoles returns the first page of the roles, use NewRoleIterator to list all the roles.
 func (c *Client) ListRoles() (*ListRolesResponse, error) {
+	return c.ListRolesPage("", 0)
+}
+
+// ListRolesPage returns the page of the roles starting from the marker,
+// maxItems is the page size and the default one of ram is used if it is 0.
+func (c *Client) ListRolesPage(marker string, maxItems int) (*ListRolesResponse, error) {
 	action := "ListRoles"
 	params := map[string]string{}
 	params["Action"] = action
+	setPageParams(params, marker, maxItems)
 
 	body, err := c.sendRequest(params)
 
@@ -199,6 +225,28 @@ func (c *Client) ListRoles() (*ListRolesResponse, error) {
 	return resp, nil
 }
 
+// UpdateRole replaces the trust policy of the role.
+func (c *Client) UpdateRole(roleName, newAssumeRolePolicyDocument string) (*UpdateRoleResponse, error) {
+	action := "UpdateRole"
+	params := map[string]string{}
+	params["RoleName"] = roleName
+	params["NewAssumeRolePolicyDocument"] = newAssumeRolePolicyDocument
+	params["Action"] = action
+
+	body, err := c.sendRequest(params)
+
+	if err != nil {
+		return nil, err
+	}
+	resp := &UpdateRoleResponse{}
+	err = json.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // CreatePolicy :
 func (c *Client) CreatePolicy(policyName, policyDocument, description string) (*CreatePolicyResponse, error) {
 	action := "CreatePolicy"
@@ -308,6 +356,48 @@ func (c *Client) CreatePolicyVersion(policyName, policyDocument, setAsDefault st
 	return resp, nil
 }
 
+// DeletePolicyVersion :
+func (c *Client) DeletePolicyVersion(policyName, versionID string) (*DeletePolicyVersionResponse, error) {
+	action := "DeletePolicyVersion"
+	params := map[string]string{}
+	params["PolicyName"] = policyName
+	params["VersionId"] = versionID
+	params["Action"] = action
+	body, err := c.sendRequest(params)
+
+	if err != nil {
+		return nil, err
+	}
+	resp := &DeletePolicyVersionResponse{}
+	err = json.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// SetDefaultPolicyVersion :
+func (c *Client) SetDefaultPolicyVersion(policyName, versionID string) (*SetDefaultPolicyVersionResponse, error) {
+	action := "SetDefaultPolicyVersion"
+	params := map[string]string{}
+	params["PolicyName"] = policyName
+	params["VersionId"] = versionID
+	params["Action"] = action
+	body, err := c.sendRequest(params)
+
+	if err != nil {
+		return nil, err
+	}
+	resp := &SetDefaultPolicyVersionResponse{}
+	err = json.Unmarshal(body, resp)
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
 // DeletePolicy :
 func (c *Client) DeletePolicy(policyName string) (*DeletePolicyResponse, error) {
 	action := "DeletePolicy"
@@ -329,11 +419,21 @@ func (c *Client) DeletePolicy(policyName string) (*DeletePolicyResponse, error)
 	return resp, nil
 }
 
-// ListPolicies :
+// ListPolicies returns the first page of the policies, use NewPolicyIterator to list all the policies.
 func (c *Client) ListPolicies() (*ListPoliciesResponse, error) {
+	return c.ListPoliciesPage("", "", 0)
+}
+
+// ListPoliciesPage returns the page of the policies starting from the marker,
+// policyType is Custom or System and all the policies are listed if it is empty.
+func (c *Client) ListPoliciesPage(policyType, marker string, maxItems int) (*ListPoliciesResponse, error) {
 	action := "ListPolicies"
 	params := map[string]string{}
 	params["Action"] = action
+	if policyType != "" {
+		params["PolicyType"] = policyType
+	}
+	setPageParams(params, marker, maxItems)
 
 	body, err := c.sendRequest(params)
 
@@ -417,18 +517,38 @@ func (c *Client) DetachPolicyFromRole(policyType, policyName, roleName string) (
 	return resp, nil
 }
 
+func setPageParams(params map[string]string, marker string, maxItems int) {
+	if marker != "" {
+		params["Marker"] = marker
+	}
+	if maxItems > 0 {
+		params["MaxItems"] = strconv.Itoa(maxItems)
+	}
+}
+
 func (c *Client) sendRequest(rawParams map[string]string) ([]byte, error) {
 	var body []byte
 	err := try.Do(func(attempt int) (bool, error) {
+		accessKeyID, accessKeySecret, securityToken := c.accessKeyID, c.accessKeySecret, ""
+		if c.credentials != nil {
+			var err error
+			accessKeyID, accessKeySecret, securityToken, err = c.credentials()
+			if err != nil {
+				return false, err
+			}
+		}
 		params := make(map[string]string)
 		for k, v := range rawParams {
 			params[k] = v
 		}
-		commonParams := c.getCommontParam()
+		commonParams := c.getCommontParam(accessKeyID)
 		for k, v := range commonParams {
 			params[k] = v
 		}
-		signature := CreateSignature(http.MethodPost, CreateQueryStr(params), c.accessKeySecret)
+		if securityToken != "" {
+			params["SecurityToken"] = securityToken
+		}
+		signature := CreateSignature(http.MethodPost, CreateQueryStr(params), accessKeySecret)
 		params["Signature"] = signature
 
 		targetURL := fmt.Sprintf("%s://%s/", c.endpoint.Scheme, c.endpoint.Host)
@@ -1,6 +1,9 @@
 package ram
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"strings"
+)
 
 // ServiceError :
 type ServiceError struct {
@@ -24,3 +27,25 @@ func (e ServiceError) String() string {
 func (e ServiceError) Error() string {
 	return e.String()
 }
+
+// IsNotFound returns whether the entity, such as the role or the policy, does not exist.
+func (e ServiceError) IsNotFound() bool {
+	return strings.HasPrefix(e.ErrorCode, "EntityNotExist")
+}
+
+// IsAlreadyExists returns whether the entity to create already exists.
+func (e ServiceError) IsAlreadyExists() bool {
+	return strings.HasPrefix(e.ErrorCode, "EntityAlreadyExists")
+}
+
+// IsNotFound returns whether err is a ram service error of a non-existent entity.
+func IsNotFound(err error) bool {
+	e, ok := err.(ServiceError)
+	return ok && e.IsNotFound()
+}
+
+// IsAlreadyExists returns whether err is a ram service error of an existing entity.
+func IsAlreadyExists(err error) bool {
+	e, ok := err.(ServiceError)
+	return ok && e.IsAlreadyExists()
+}
@@ -0,0 +1,127 @@
+package ram
+
+// pager fetches the pages by the marker until the result is not truncated.
+type pager struct {
+	marker   string
+	maxItems int
+	started  bool
+	done     bool
+	err      error
+	index    int
+	size     int
+	// fetch requests the page from the marker, returns the number of the items in the page,
+	// whether there are more pages, and the marker of the next page
+	fetch func(marker string, maxItems int) (size int, isTruncated bool, next string, err error)
+}
+
+func (p *pager) next() bool {
+	p.index++
+	for p.index >= p.size {
+		if p.done || p.err != nil || (p.started && p.marker == "") {
+			return false
+		}
+		size, isTruncated, next, err := p.fetch(p.marker, p.maxItems)
+		p.started = true
+		if err != nil {
+			p.err = err
+			return false
+		}
+		p.index, p.size, p.marker = 0, size, next
+		p.done = !isTruncated
+	}
+	return true
+}
+
+// RoleIterator iterates over all the roles page by page.
+type RoleIterator struct {
+	pager
+	roles []Role
+}
+
+// NewRoleIterator returns the iterator of all the roles, maxItems is the page size.
+func (c *Client) NewRoleIterator(maxItems int) *RoleIterator {
+	it := &RoleIterator{}
+	it.maxItems = maxItems
+	it.index = -1
+	it.fetch = func(marker string, maxItems int) (int, bool, string, error) {
+		resp, err := c.ListRolesPage(marker, maxItems)
+		if err != nil {
+			return 0, false, "", err
+		}
+		it.roles = resp.Roles.Role
+		return len(it.roles), resp.IsTruncated, resp.Marker, nil
+	}
+	return it
+}
+
+// Next advances to the next role, it returns false when there are no more roles or an error occurs.
+func (it *RoleIterator) Next() bool {
+	return it.next()
+}
+
+// Role returns the current role.
+func (it *RoleIterator) Role() Role {
+	return it.roles[it.index]
+}
+
+// Err returns the error which stops the iteration.
+func (it *RoleIterator) Err() error {
+	return it.err
+}
+
+// PolicyIterator iterates over all the policies page by page.
+type PolicyIterator struct {
+	pager
+	policies []Policy
+}
+
+// NewPolicyIterator returns the iterator of all the policies of the type, maxItems is the page size.
+func (c *Client) NewPolicyIterator(policyType string, maxItems int) *PolicyIterator {
+	it := &PolicyIterator{}
+	it.maxItems = maxItems
+	it.index = -1
+	it.fetch = func(marker string, maxItems int) (int, bool, string, error) {
+		resp, err := c.ListPoliciesPage(policyType, marker, maxItems)
+		if err != nil {
+			return 0, false, "", err
+		}
+		it.policies = resp.Policies.Policy
+		return len(it.policies), resp.IsTruncated, resp.Marker, nil
+	}
+	return it
+}
+
+// Next advances to the next policy, it returns false when there are no more policies or an error occurs.
+func (it *PolicyIterator) Next() bool {
+	return it.next()
+}
+
+// Policy returns the current policy.
+func (it *PolicyIterator) Policy() Policy {
+	return it.policies[it.index]
+}
+
+// Err returns the error which stops the iteration.
+func (it *PolicyIterator) Err() error {
+	return it.err
+}
+
+// ListAllRoles lists all the roles page by page.
+func (c *Client) ListAllRoles() ([]Role, error) {
+	roles := []Role{}
+	it := c.NewRoleIterator(0)
+	for it.Next() {
+		roles = append(roles, it.Role())
+	}
+	return roles, it.Err()
+}
+
+// ListAllPolicies lists all the policies of the type page by page.
+func (c *Client) ListAllPolicies(policyType string) ([]Policy, error) {
+	policies := []Policy{}
+	it := c.NewPolicyIterator(policyType, 0)
+	for it.Next() {
+		policies = append(policies, it.Policy())
+	}
+	return policies, it.Err()
+}
@@ -0,0 +1,51 @@
+package ram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/h2non/gock.v1"
+)
+
+func TestRoleIterator(t *testing.T) {
+	defer gock.Off()
+	c, err := NewClient("https://ram.aliyuncs.com", "ak", "sk")
+	assert.Nil(t, err)
+	gock.InterceptClient(c.hclient)
+
+	gock.New("https://ram.aliyuncs.com").Post("/").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"IsTruncated": true,
+			"Marker":      "m1",
+			"Roles":       map[string]interface{}{"Role": []map[string]string{{"RoleName": "r1"}, {"RoleName": "r2"}}},
+		})
+	gock.New("https://ram.aliyuncs.com").Post("/").
+		BodyString("Marker=m1").
+		Reply(200).
+		JSON(map[string]interface{}{
+			"IsTruncated": false,
+			"Roles":       map[string]interface{}{"Role": []map[string]string{{"RoleName": "r3"}}},
+		})
+
+	roles, err := c.ListAllRoles()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(roles))
+	assert.Equal(t, "r3", roles[2].RoleName)
+	assert.True(t, gock.IsDone())
+}
+
+func TestPolicyIteratorError(t *testing.T) {
+	defer gock.Off()
+	c, err := NewClient("https://ram.aliyuncs.com", "ak", "sk")
+	assert.Nil(t, err)
+	gock.InterceptClient(c.hclient)
+
+	gock.New("https://ram.aliyuncs.com").Post("/").
+		Reply(403).
+		JSON(map[string]string{"Code": "NoPermission", "Message": "denied"})
+
+	it := c.NewPolicyIterator("Custom", 10)
+	assert.False(t, it.Next())
+	assert.Equal(t, "NoPermission", it.Err().(ServiceError).ErrorCode)
+}
@@ -1,5 +1,7 @@
 package ram
 
+import "encoding/json"
+
 // default params for client options
 const (
 	DefaultRetryTimes          = 5
@@ -32,11 +34,32 @@ type Policy struct {
 	AttachmentCount int64  `json:"AttachmentCount"`
 }
 
+// StringList is a policy element which is either a single string or an array of strings.
+type StringList []string
+
+// UnmarshalJSON :
+func (l *StringList) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*l = StringList{s}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*l = list
+	return nil
+}
+
 // PolicyStatement :
 type PolicyStatement struct {
-	Effect   string   `json:"Effect"`
-	Action   []string `json:"Action"`
-	Resource []string `json:"Resource"`
+	Effect      string          `json:"Effect"`
+	Action      StringList      `json:"Action,omitempty"`
+	NotAction   StringList      `json:"NotAction,omitempty"`
+	Resource    StringList      `json:"Resource,omitempty"`
+	NotResource StringList      `json:"NotResource,omitempty"`
+	Condition   json.RawMessage `json:"Condition,omitempty"`
 }
 
 // PolicyDocument :
@@ -45,6 +68,32 @@ type PolicyDocument struct {
 	Statement []PolicyStatement `json:"Statement"`
 }
 
+// AssumeRolePrincipal is the principal of the trust policy, the principals other than Service and RAM,
+// such as Federated, are kept as is.
+type AssumeRolePrincipal struct {
+	Service StringList
+	RAM     StringList
+	other   map[string]json.RawMessage
+}
+
+// AssumeRoleStatement is the statement of the trust policy, the elements other than Action, Effect
+// and Principal, such as Condition, are kept as is.
+type AssumeRoleStatement struct {
+	Action    StringList
+	Effect    string
+	Principal AssumeRolePrincipal
+	other     map[string]json.RawMessage
+	// whether the action is a single string rather than an array in the document
+	singleAction bool
+}
+
+// AssumeRolePolicyDocument is the trust policy of the role.
+type AssumeRolePolicyDocument struct {
+	Statement []AssumeRoleStatement
+	Version   string
+	other     map[string]json.RawMessage
+}
+
 // Policies :
 type Policies struct {
 	Policy []Policy `json:"Policy"`
@@ -94,8 +143,16 @@ type DeleteRoleResponse struct {
 
 // ListRolesResponse :
 type ListRolesResponse struct {
+	RequestID   string `json:"RequestId"`
+	IsTruncated bool   `json:"IsTruncated"`
+	Marker      string `json:"Marker"`
+	Roles       Roles  `json:"Roles"`
+}
+
+// UpdateRoleResponse :
+type UpdateRoleResponse struct {
 	RequestID string `json:"RequestId"`
-	Roles     Roles  `json:"Roles"`
+	Role      Role   `json:"Role"`
 }
 
 // CreatePolicyResponse :
@@ -123,6 +180,16 @@ type ListPoliciesResponse struct {
 	Policies    Policies `json:"Policies"`
 }
 
+// DeletePolicyVersionResponse :
+type DeletePolicyVersionResponse struct {
+	RequestID string `json:"RequestId"`
+}
+
+// SetDefaultPolicyVersionResponse :
+type SetDefaultPolicyVersionResponse struct {
+	RequestID string `json:"RequestId"`
+}
+
 // AttachPolicyToRoleResponse :
 type AttachPolicyToRoleResponse struct {
 	RequestID string `json:"RequestId"`
@@ -0,0 +1,203 @@
+package ram
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ParseAssumeRolePolicyDocument parses the trust policy of the role.
+func ParseAssumeRolePolicyDocument(doc string) (*AssumeRolePolicyDocument, error) {
+	d := &AssumeRolePolicyDocument{}
+	if err := json.Unmarshal([]byte(doc), d); err != nil {
+		return nil, fmt.Errorf("invalid assume role policy document: %s", err)
+	}
+	return d, nil
+}
+
+// decodeObject decodes the json object, the known keys are decoded into the fields,
+// and the others are returned as is.
+func decodeObject(data []byte, fields map[string]interface{}) (map[string]json.RawMessage, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	for key, field := range fields {
+		value, ok := raw[key]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", key, err)
+		}
+		delete(raw, key)
+	}
+	return raw, nil
+}
+
+// encodeObject encodes the fields along with the other keys kept by decodeObject.
+func encodeObject(other map[string]json.RawMessage, fields map[string]interface{}) ([]byte, error) {
+	object := map[string]interface{}{}
+	for key, value := range other {
+		object[key] = value
+	}
+	for key, value := range fields {
+		object[key] = value
+	}
+	return json.Marshal(object)
+}
+
+// UnmarshalJSON :
+func (d *AssumeRolePolicyDocument) UnmarshalJSON(data []byte) error {
+	other, err := decodeObject(data, map[string]interface{}{"Statement": &d.Statement, "Version": &d.Version})
+	d.other = other
+	return err
+}
+
+// MarshalJSON :
+func (d AssumeRolePolicyDocument) MarshalJSON() ([]byte, error) {
+	statements := d.Statement
+	if statements == nil {
+		statements = []AssumeRoleStatement{}
+	}
+	return encodeObject(d.other, map[string]interface{}{"Statement": statements, "Version": d.Version})
+}
+
+// UnmarshalJSON :
+func (s *AssumeRoleStatement) UnmarshalJSON(data []byte) error {
+	var action json.RawMessage
+	other, err := decodeObject(data, map[string]interface{}{
+		"Action": &action, "Effect": &s.Effect, "Principal": &s.Principal})
+	if err != nil {
+		return err
+	}
+	s.other = other
+	if action == nil {
+		return nil
+	}
+	if err := json.Unmarshal(action, &s.Action); err != nil {
+		return fmt.Errorf("invalid Action: %s", err)
+	}
+	s.singleAction = strings.HasPrefix(strings.TrimSpace(string(action)), `"`)
+	return nil
+}
+
+// MarshalJSON :
+func (s AssumeRoleStatement) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{"Effect": s.Effect, "Principal": s.Principal}
+	if s.singleAction && len(s.Action) == 1 {
+		fields["Action"] = s.Action[0]
+	} else {
+		fields["Action"] = s.Action
+	}
+	return encodeObject(s.other, fields)
+}
+
+// UnmarshalJSON :
+func (p *AssumeRolePrincipal) UnmarshalJSON(data []byte) error {
+	other, err := decodeObject(data, map[string]interface{}{"Service": &p.Service, "RAM": &p.RAM})
+	p.other = other
+	return err
+}
+
+// MarshalJSON :
+func (p AssumeRolePrincipal) MarshalJSON() ([]byte, error) {
+	fields := map[string]interface{}{}
+	if len(p.Service) > 0 {
+		fields["Service"] = p.Service
+	}
+	if len(p.RAM) > 0 {
+		fields["RAM"] = p.RAM
+	}
+	return encodeObject(p.other, fields)
+}
+
+// allowsAssumeRole returns whether the statement allows to assume the role.
+func (s *AssumeRoleStatement) allowsAssumeRole() bool {
+	if s.Effect != "Allow" {
+		return false
+	}
+	for _, action := range s.Action {
+		if action == "sts:AssumeRole" || action == "sts:*" || action == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the trust policy in compact json.
+func (d *AssumeRolePolicyDocument) String() string {
+	b, err := json.Marshal(d)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// HasServicePrincipal returns whether the service, such as fc.aliyuncs.com, can assume the role.
+func (d *AssumeRolePolicyDocument) HasServicePrincipal(service string) bool {
+	for _, s := range d.Statement {
+		if !s.allowsAssumeRole() {
+			continue
+		}
+		for _, p := range s.Principal.Service {
+			if p == service {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// AddServicePrincipal allows the service to assume the role, it returns false if it is already allowed.
+func (d *AssumeRolePolicyDocument) AddServicePrincipal(service string) bool {
+	if d.HasServicePrincipal(service) {
+		return false
+	}
+	// the statements with the conditions are left untouched, which may not be met by the service
+	for i, s := range d.Statement {
+		if s.allowsAssumeRole() && len(s.Principal.Service) > 0 && len(s.other) == 0 {
+			d.Statement[i].Principal.Service = append(s.Principal.Service, service)
+			return true
+		}
+	}
+	if d.Version == "" {
+		d.Version = "1"
+	}
+	d.Statement = append(d.Statement, AssumeRoleStatement{
+		Action:       StringList{"sts:AssumeRole"},
+		Effect:       "Allow",
+		Principal:    AssumeRolePrincipal{Service: StringList{service}},
+		singleAction: true,
+	})
+	return true
+}
+
+// RemoveServicePrincipal disallows the service to assume the role, the statements without
+// any principal left are removed, and the deny statements are left untouched.
+// It returns false if the service is not allowed.
+func (d *AssumeRolePolicyDocument) RemoveServicePrincipal(service string) bool {
+	removed := false
+	statements := []AssumeRoleStatement{}
+	for _, s := range d.Statement {
+		services := StringList{}
+		for _, p := range s.Principal.Service {
+			if p == service && s.Effect == "Allow" {
+				continue
+			}
+			services = append(services, p)
+		}
+		if len(services) == len(s.Principal.Service) {
+			statements = append(statements, s)
+			continue
+		}
+		removed = true
+		if len(services) == 0 && len(s.Principal.RAM) == 0 && len(s.Principal.other) == 0 {
+			continue
+		}
+		s.Principal.Service = services
+		statements = append(statements, s)
+	}
+	d.Statement = statements
+	return removed
+}
@@ -0,0 +1,59 @@
+package ram
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssumeRolePolicyDocument(t *testing.T) {
+	d, err := ParseAssumeRolePolicyDocument(
+		`{"Statement":[{"Action":"sts:AssumeRole","Effect":"Allow","Principal":{"Service":"fc.aliyuncs.com"}}],"Version":"1"}`)
+	assert.Nil(t, err)
+	assert.True(t, d.HasServicePrincipal("fc.aliyuncs.com"))
+	assert.False(t, d.AddServicePrincipal("fc.aliyuncs.com"))
+
+	assert.True(t, d.AddServicePrincipal("log.aliyuncs.com"))
+	assert.Equal(t, `{"Statement":[{"Action":"sts:AssumeRole","Effect":"Allow",`+
+		`"Principal":{"Service":["fc.aliyuncs.com","log.aliyuncs.com"]}}],"Version":"1"}`, d.String())
+
+	assert.True(t, d.RemoveServicePrincipal("fc.aliyuncs.com"))
+	assert.True(t, d.RemoveServicePrincipal("log.aliyuncs.com"))
+	assert.False(t, d.RemoveServicePrincipal("log.aliyuncs.com"))
+	assert.Equal(t, 0, len(d.Statement))
+}
+
+func TestServiceError(t *testing.T) {
+	assert.True(t, IsNotFound(ServiceError{ErrorCode: "EntityNotExist.Role"}))
+	assert.True(t, IsAlreadyExists(ServiceError{ErrorCode: "EntityAlreadyExists.Policy"}))
+	assert.False(t, IsNotFound(ServiceError{ErrorCode: "EntityAlreadyExists.Role"}))
+	assert.False(t, IsNotFound(nil))
+}
+
+func TestAssumeRolePolicyDocumentKeepsUnknownElements(t *testing.T) {
+	doc := `{"Statement":[` +
+		`{"Action":["sts:AssumeRole"],"Condition":{"StringEquals":{"sts:ExternalId":"abc"}},"Effect":"Allow",` +
+		`"Principal":{"Service":["fc.aliyuncs.com"]}},` +
+		`{"Action":"sts:AssumeRole","Effect":"Allow","Principal":{"Federated":["acs:ram::123:saml-provider/idp"]}},` +
+		`{"Action":"sts:AssumeRole","Effect":"Deny","Principal":{"Service":["log.aliyuncs.com"]}}` +
+		`],"Version":"1"}`
+	d, err := ParseAssumeRolePolicyDocument(doc)
+	assert.Nil(t, err)
+	assert.True(t, d.HasServicePrincipal("fc.aliyuncs.com"))
+	assert.False(t, d.HasServicePrincipal("log.aliyuncs.com"))
+	assert.JSONEq(t, doc, d.String())
+
+	// the service is not added to the statement with the condition
+	assert.True(t, d.AddServicePrincipal("oss.aliyuncs.com"))
+	assert.Equal(t, 4, len(d.Statement))
+	assert.Equal(t, StringList{"oss.aliyuncs.com"}, d.Statement[3].Principal.Service)
+
+	// the deny statement and the federated principal are kept
+	assert.True(t, d.RemoveServicePrincipal("fc.aliyuncs.com"))
+	assert.False(t, d.RemoveServicePrincipal("log.aliyuncs.com"))
+	assert.JSONEq(t, `{"Statement":[`+
+		`{"Action":"sts:AssumeRole","Effect":"Allow","Principal":{"Federated":["acs:ram::123:saml-provider/idp"]}},`+
+		`{"Action":"sts:AssumeRole","Effect":"Deny","Principal":{"Service":["log.aliyuncs.com"]}},`+
+		`{"Action":"sts:AssumeRole","Effect":"Allow","Principal":{"Service":["oss.aliyuncs.com"]}}`+
+		`],"Version":"1"}`, d.String())
+}
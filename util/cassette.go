@@ -0,0 +1,272 @@
+package util
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// CassetteVersion is the version of the cassette file format.
+const CassetteVersion = 1
+
+// the credentials which are used to replay a cassette without the config
+const (
+	ReplayAccessKeyID     = "replay"
+	ReplayAccessKeySecret = "replay"
+)
+
+// the keys of the signed query and form which vary between the record and the replay,
+// they are ignored to match the requests
+var volatileCassetteKeys = []string{"AccessKeyId", "Timestamp", "SignatureNonce"}
+
+var activeCassette *Cassette
+
+// Cassette is the recorded http interactions of a fcli command with the secrets redacted,
+// which can be replayed without the network.
+type Cassette struct {
+	Version      int            `json:"version"`
+	Endpoint     string         `json:"endpoint,omitempty"`
+	SLSEndpoint  string         `json:"sls_endpoint,omitempty"`
+	Interactions []*Interaction `json:"interactions"`
+
+	path   string
+	replay bool
+	mu     sync.Mutex
+	used   []bool
+}
+
+// Interaction is a recorded http request and its response, or the error if the request failed.
+type Interaction struct {
+	Request  CassetteRequest   `json:"request"`
+	Response *CassetteResponse `json:"response,omitempty"`
+	Error    string            `json:"error,omitempty"`
+}
+
+// CassetteRequest is a recorded http request.
+type CassetteRequest struct {
+	Method     string      `json:"method"`
+	URL        string      `json:"url"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// CassetteResponse is a recorded http response, the binary body is encoded in base64.
+type CassetteResponse struct {
+	Status     int         `json:"status"`
+	Headers    http.Header `json:"headers,omitempty"`
+	Body       string      `json:"body,omitempty"`
+	BodyBase64 string      `json:"body_base64,omitempty"`
+}
+
+// EnableHTTPRecord records the http interactions of the fc, ram and sls clients to the cassette file,
+// which is written after each interaction.
+func EnableHTTPRecord(path, endpoint, slsEndpoint string) error {
+	c := &Cassette{Version: CassetteVersion, Endpoint: endpoint, SLSEndpoint: slsEndpoint,
+		Interactions: []*Interaction{}, path: path}
+	if err := c.save(); err != nil {
+		return err
+	}
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	installDefaultTransport()
+	activeCassette = c
+	return nil
+}
+
+// EnableHTTPReplay serves the http requests of the fc, ram and sls clients from the cassette file,
+// the requests which are not recorded fail without the network.
+func EnableHTTPReplay(path string) (*Cassette, error) {
+	c, err := LoadCassette(path)
+	if err != nil {
+		return nil, err
+	}
+	c.replay = true
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	installDefaultTransport()
+	activeCassette = c
+	return c, nil
+}
+
+// DisableHTTPCassette stops recording or replaying.
+func DisableHTTPCassette() {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	activeCassette = nil
+}
+
+// LoadCassette loads the cassette file.
+func LoadCassette(path string) (*Cassette, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := &Cassette{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid cassette %s: %v", path, err)
+	}
+	if c.Version != CassetteVersion {
+		return nil, fmt.Errorf("unsupported cassette version %d of %s, expect %d", c.Version, path, CassetteVersion)
+	}
+	c.path = path
+	c.used = make([]bool, len(c.Interactions))
+	return c, nil
+}
+
+func (c *Cassette) save() error {
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(c.path, data, 0600)
+}
+
+func (c *Cassette) record(i *Interaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Interactions = append(c.Interactions, i)
+	return c.save()
+}
+
+// match returns the first unused interaction of the request, the interactions of the same request
+// are replayed in the recorded order, such as the retries.
+func (c *Cassette) match(req *CassetteRequest) (*Interaction, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	key := req.matchKey()
+	for n, i := range c.Interactions {
+		if !c.used[n] && i.Request.matchKey() == key {
+			c.used[n] = true
+			return i, nil
+		}
+	}
+	return nil, fmt.Errorf("no recorded interaction of %s %s in the cassette %s", req.Method, req.URL, c.path)
+}
+
+// newCassetteRequest converts the request to the redacted one.
+func newCassetteRequest(req *http.Request, body []byte) *CassetteRequest {
+	r := &CassetteRequest{
+		Method:  req.Method,
+		URL:     redactURL(req.URL),
+		Headers: redactHeaders(req.Header),
+	}
+	r.Body, r.BodyBase64 = encodeCassetteBody(req.Header.Get("Content-Type"), body)
+	return r
+}
+
+// encodeCassetteBody redacts the text body, or encodes the binary body in base64.
+func encodeCassetteBody(contentType string, body []byte) (text, binary string) {
+	if !utf8.Valid(body) {
+		return "", base64.StdEncoding.EncodeToString(body)
+	}
+	return RedactBody(contentType, string(body)), ""
+}
+
+// matchKey is the method, the url and the body of the request without the volatile keys.
+func (r *CassetteRequest) matchKey() string {
+	u, err := url.Parse(r.URL)
+	if err != nil {
+		return r.Method + " " + r.URL + "\n" + r.Body + r.BodyBase64
+	}
+	u.RawQuery = withoutVolatileKeys(u.RawQuery)
+	body := r.Body + r.BodyBase64
+	if strings.Contains(r.Headers.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		body = withoutVolatileKeys(body)
+	}
+	return r.Method + " " + u.String() + "\n" + body
+}
+
+func withoutVolatileKeys(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil || len(values) == 0 {
+		return raw
+	}
+	for _, k := range volatileCassetteKeys {
+		values.Del(k)
+	}
+	return values.Encode()
+}
+
+func redactHeaders(header http.Header) http.Header {
+	h := http.Header{}
+	for k, v := range header {
+		if isSecretKey(k) {
+			v = []string{redacted}
+		}
+		h[k] = v
+	}
+	return h
+}
+
+// CassetteTransport records the http interactions to the cassette, or replays them from it.
+type CassetteTransport struct {
+	Base     http.RoundTripper
+	Cassette *Cassette
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *CassetteTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	recorded := newCassetteRequest(req, body)
+	if t.Cassette.replay {
+		i, err := t.Cassette.match(recorded)
+		if err != nil {
+			return nil, err
+		}
+		if i.Response == nil {
+			return nil, errors.New(i.Error)
+		}
+		return i.Response.httpResponse(req), nil
+	}
+
+	i := &Interaction{Request: *recorded}
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil {
+		i.Error = err.Error()
+	} else {
+		respBody, readErr := readBody(&resp.Body)
+		if readErr != nil {
+			return nil, readErr
+		}
+		i.Response = &CassetteResponse{Status: resp.StatusCode, Headers: redactHeaders(resp.Header)}
+		i.Response.Body, i.Response.BodyBase64 = encodeCassetteBody(resp.Header.Get("Content-Type"), respBody)
+	}
+	if recordErr := t.Cassette.record(i); recordErr != nil {
+		return nil, fmt.Errorf("failed to record the cassette: %v", recordErr)
+	}
+	return resp, err
+}
+
+func (r *CassetteResponse) httpResponse(req *http.Request) *http.Response {
+	body := []byte(r.Body)
+	if r.BodyBase64 != "" {
+		body, _ = base64.StdEncoding.DecodeString(r.BodyBase64)
+	}
+	header := r.Headers
+	if header == nil {
+		header = http.Header{}
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", r.Status, http.StatusText(r.Status)),
+		StatusCode:    r.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+func (s *UtilTestSuite) TestCassette() {
+	assert := s.Require()
+	dir, err := ioutil.TempDir("", "fcli-cassette")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	defer DisableHTTPCassette()
+	path := filepath.Join(dir, "cassette.json")
+
+	calls, unavailable := 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch r.URL.Path {
+		case "/unavailable":
+			unavailable++
+			if unavailable == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+			w.Write([]byte("ok"))
+		case "/code":
+			w.Header().Set("Content-Type", "application/zip")
+			w.Write([]byte{0x50, 0x4b, 0xff, 0xfe})
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"AccessKeySecret": "sts-secret", "RoleName": "demo"}`))
+		}
+	}))
+	client := &http.Client{Transport: InstrumentRoundTripper(&http.Transport{})}
+	post := func(timestamp string) (*http.Response, error) {
+		return client.Post(server.URL+"/", "application/x-www-form-urlencoded", strings.NewReader(
+			"AccessKeyId=id&Action=GetRole&Signature=sig&SignatureNonce="+timestamp+"&Timestamp="+timestamp))
+	}
+	get := func(path string) (int, string) {
+		resp, err := client.Get(server.URL + path)
+		assert.Nil(err)
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		assert.Nil(err)
+		return resp.StatusCode, string(body)
+	}
+
+	assert.Nil(EnableHTTPRecord(path, "https://123.cn-hangzhou.fc.aliyuncs.com", "cn-hangzhou.log.aliyuncs.com"))
+	resp, err := post("2019-01-01T00:00:00Z")
+	assert.Nil(err)
+	resp.Body.Close()
+	status, _ := get("/unavailable")
+	assert.Equal(http.StatusServiceUnavailable, status)
+	status, _ = get("/unavailable")
+	assert.Equal(http.StatusOK, status)
+	_, code := get("/code")
+	server.Close()
+	assert.Equal(4, calls)
+
+	data, err := ioutil.ReadFile(path)
+	assert.Nil(err)
+	assert.NotContains(string(data), "sts-secret")
+	assert.NotContains(string(data), "Signature=sig")
+
+	cassette, err := EnableHTTPReplay(path)
+	assert.Nil(err)
+	assert.Equal("https://123.cn-hangzhou.fc.aliyuncs.com", cassette.Endpoint)
+	// the timestamp and the nonce are ignored to match the request
+	resp, err = post("2019-06-01T00:00:00Z")
+	assert.Nil(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Nil(err)
+	assert.Equal(`{"AccessKeySecret": "***", "RoleName": "demo"}`, string(body))
+	// the same requests are replayed in order
+	status, _ = get("/unavailable")
+	assert.Equal(http.StatusServiceUnavailable, status)
+	status, _ = get("/unavailable")
+	assert.Equal(http.StatusOK, status)
+	_, replayed := get("/code")
+	assert.Equal(code, replayed)
+	assert.Equal(4, calls)
+
+	_, err = client.Get(server.URL + "/unavailable")
+	assert.NotNil(err)
+	assert.Contains(err.Error(), "no recorded interaction of GET")
+}
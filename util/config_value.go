@@ -0,0 +1,83 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ConfigKeys returns the keys of the config file, which are the yaml names of the config fields.
+func ConfigKeys() []string {
+	keys := []string{}
+	t := reflect.TypeOf(GlobalConfig{})
+	for i := 0; i < t.NumField(); i++ {
+		if key := configKey(t.Field(i)); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+func configKey(f reflect.StructField) string {
+	tag := f.Tag.Get("yaml")
+	if tag == "" || tag == "-" {
+		return ""
+	}
+	return strings.Split(tag, ",")[0]
+}
+
+func (cfg *GlobalConfig) configField(key string) (reflect.Value, error) {
+	v := reflect.ValueOf(cfg).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		if configKey(v.Type().Field(i)) == key {
+			return v.Field(i), nil
+		}
+	}
+	return reflect.Value{}, NewValidationError("unknown config key %s, the valid keys are: %s",
+		key, strings.Join(ConfigKeys(), ", "))
+}
+
+// GetValue returns the value of the config key as a string.
+func (cfg *GlobalConfig) GetValue(key string) (string, error) {
+	field, err := cfg.configField(key)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprint(field.Interface()), nil
+}
+
+// SetValue parses the value by the type of the config key and sets it.
+func (cfg *GlobalConfig) SetValue(key, value string) error {
+	field, err := cfg.configField(key)
+	if err != nil {
+		return err
+	}
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return NewValidationError("invalid value %s of %s, should be true or false", value, key)
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return NewValidationError("invalid value %s of %s, should be an integer", value, key)
+		}
+		field.SetInt(n)
+	case reflect.Uint:
+		n, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return NewValidationError("invalid value %s of %s, should be a non-negative integer", value, key)
+		}
+		field.SetUint(n)
+	default:
+		return fmt.Errorf("config key %s can not be set", key)
+	}
+	// the credentials are retrieved again with the new config
+	cfg.credentialProvider = nil
+	return nil
+}
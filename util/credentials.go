@@ -0,0 +1,350 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aliyun/fcli/ram"
+	"github.com/satori/go.uuid"
+)
+
+const (
+	// DefaultSTSEndpoint is the endpoint of the sts service
+	DefaultSTSEndpoint = "https://sts.aliyuncs.com"
+
+	// DefaultECSMetadataURL is the url of the ram role credentials in the ecs instance metadata
+	DefaultECSMetadataURL = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+
+	// DefaultRoleSessionName is the session name of the assumed role
+	DefaultRoleSessionName = "fcli"
+
+	// DefaultRoleSessionDuration is the duration in seconds of the assumed role credentials
+	DefaultRoleSessionDuration = 3600
+
+	// credentialsRefreshWindow is how long before the expiration the credentials are refreshed
+	credentialsRefreshWindow = 5 * time.Minute
+)
+
+// Credentials is the access key pair with the optional security token, the expiration is
+// zero for the long-term access key.
+type Credentials struct {
+	AccessKeyID     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+// CredentialProvider provides the credentials to sign the requests.
+type CredentialProvider interface {
+	// Retrieve returns the credentials, the error is ErrNoCredentials if the provider
+	// is not configured, so that the chain moves on to the next one.
+	Retrieve() (*Credentials, error)
+}
+
+// ErrNoCredentials means the provider has no credentials.
+var ErrNoCredentials = fmt.Errorf("no credentials")
+
+// StaticProvider provides the access key in the config.
+type StaticProvider struct {
+	Credentials Credentials
+}
+
+// Retrieve :
+func (p *StaticProvider) Retrieve() (*Credentials, error) {
+	if p.Credentials.AccessKeyID == "" || p.Credentials.AccessKeySecret == "" {
+		return nil, ErrNoCredentials
+	}
+	c := p.Credentials
+	return &c, nil
+}
+
+// EnvProvider provides the access key in the environment variables ALIBABA_CLOUD_ACCESS_KEY_ID,
+// ALIBABA_CLOUD_ACCESS_KEY_SECRET and ALIBABA_CLOUD_SECURITY_TOKEN, or the deprecated
+// ALIYUN_ACCESS_KEY_ID and ALIYUN_ACCESS_KEY_SECRET.
+type EnvProvider struct{}
+
+// Retrieve :
+func (p *EnvProvider) Retrieve() (*Credentials, error) {
+	for _, keys := range [][2]string{
+		{"ALIBABA_CLOUD_ACCESS_KEY_ID", "ALIBABA_CLOUD_ACCESS_KEY_SECRET"},
+		{"ALIYUN_ACCESS_KEY_ID", "ALIYUN_ACCESS_KEY_SECRET"},
+	} {
+		id, secret := os.Getenv(keys[0]), os.Getenv(keys[1])
+		if id != "" && secret != "" {
+			return &Credentials{
+				AccessKeyID:     id,
+				AccessKeySecret: secret,
+				SecurityToken:   os.Getenv("ALIBABA_CLOUD_SECURITY_TOKEN"),
+			}, nil
+		}
+	}
+	return nil, ErrNoCredentials
+}
+
+// ECSMetadataProvider provides the credentials of the ram role attached to the ecs instance.
+// The role is discovered from the metadata if RoleName is empty.
+type ECSMetadataProvider struct {
+	URL      string
+	RoleName string
+	Client   *http.Client
+}
+
+// Retrieve :
+func (p *ECSMetadataProvider) Retrieve() (*Credentials, error) {
+	url := p.URL
+	if url == "" {
+		url = DefaultECSMetadataURL
+	}
+	if !strings.HasSuffix(url, "/") {
+		url += "/"
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 3 * time.Second}
+	}
+
+	roleName := p.RoleName
+	if roleName == "" {
+		body, err := httpGet(client, url)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get the ecs ram role due to %v", err)
+		}
+		roleName = strings.TrimSpace(strings.SplitN(string(body), "\n", 2)[0])
+		if roleName == "" {
+			return nil, fmt.Errorf("no ram role is attached to the ecs instance")
+		}
+	}
+	body, err := httpGet(client, url+roleName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the credentials of the ecs ram role %s due to %v", roleName, err)
+	}
+	resp := struct {
+		Code            string `json:"Code"`
+		AccessKeyID     string `json:"AccessKeyId"`
+		AccessKeySecret string `json:"AccessKeySecret"`
+		SecurityToken   string `json:"SecurityToken"`
+		Expiration      string `json:"Expiration"`
+	}{}
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("invalid credentials of the ecs ram role %s: %v", roleName, err)
+	}
+	if resp.Code != "" && resp.Code != "Success" {
+		return nil, fmt.Errorf("failed to get the credentials of the ecs ram role %s: %s", roleName, resp.Code)
+	}
+	expiration, _ := time.Parse(time.RFC3339, resp.Expiration)
+	return &Credentials{
+		AccessKeyID:     resp.AccessKeyID,
+		AccessKeySecret: resp.AccessKeySecret,
+		SecurityToken:   resp.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+// AssumeRoleProvider assumes the role by sts with the credentials of the source provider,
+// the role can belong to another account.
+type AssumeRoleProvider struct {
+	Source          CredentialProvider
+	RoleARN         string
+	RoleSessionName string
+	// DurationSeconds is the duration of the credentials, 900 to 3600 by default
+	DurationSeconds int
+	Endpoint        string
+	Client          *http.Client
+}
+
+// Retrieve :
+func (p *AssumeRoleProvider) Retrieve() (*Credentials, error) {
+	source, err := p.Source.Retrieve()
+	if err != nil {
+		return nil, fmt.Errorf("no credentials to assume the role %s: %v", p.RoleARN, err)
+	}
+	sessionName := p.RoleSessionName
+	if sessionName == "" {
+		sessionName = DefaultRoleSessionName
+	}
+	duration := p.DurationSeconds
+	if duration == 0 {
+		duration = DefaultRoleSessionDuration
+	}
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = DefaultSTSEndpoint
+	}
+	client := p.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	u, err := uuid.NewV4()
+	if err != nil {
+		return nil, err
+	}
+	params := map[string]string{
+		"Format":           "JSON",
+		"Version":          "2015-04-01",
+		"Action":           "AssumeRole",
+		"SignatureMethod":  "HMAC-SHA1",
+		"SignatureVersion": "1.0",
+		"SignatureNonce":   u.String(),
+		"Timestamp":        time.Now().UTC().Format(time.RFC3339),
+		"AccessKeyId":      source.AccessKeyID,
+		"RoleArn":          p.RoleARN,
+		"RoleSessionName":  sessionName,
+		"DurationSeconds":  strconv.Itoa(duration),
+	}
+	if source.SecurityToken != "" {
+		params["SecurityToken"] = source.SecurityToken
+	}
+	params["Signature"] = ram.CreateSignature(http.MethodPost, ram.CreateQueryStr(params), source.AccessKeySecret)
+
+	req, err := http.NewRequest(http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/",
+		strings.NewReader(ram.CreateQueryStr(params)))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assume the role %s due to %v", p.RoleARN, err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to assume the role %s: %s", p.RoleARN, string(body))
+	}
+	result := struct {
+		Credentials struct {
+			AccessKeyID     string `json:"AccessKeyId"`
+			AccessKeySecret string `json:"AccessKeySecret"`
+			SecurityToken   string `json:"SecurityToken"`
+			Expiration      string `json:"Expiration"`
+		} `json:"Credentials"`
+	}{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("invalid response of assuming the role %s: %v", p.RoleARN, err)
+	}
+	expiration, _ := time.Parse(time.RFC3339, result.Credentials.Expiration)
+	return &Credentials{
+		AccessKeyID:     result.Credentials.AccessKeyID,
+		AccessKeySecret: result.Credentials.AccessKeySecret,
+		SecurityToken:   result.Credentials.SecurityToken,
+		Expiration:      expiration,
+	}, nil
+}
+
+// ChainProvider returns the credentials of the first provider which has them.
+type ChainProvider struct {
+	Providers []CredentialProvider
+}
+
+// Retrieve :
+func (p *ChainProvider) Retrieve() (*Credentials, error) {
+	for _, provider := range p.Providers {
+		c, err := provider.Retrieve()
+		if err == ErrNoCredentials {
+			continue
+		}
+		return c, err
+	}
+	return nil, ErrNoCredentials
+}
+
+// CachedProvider caches the credentials of the provider and refreshes them before they expire.
+type CachedProvider struct {
+	Provider CredentialProvider
+
+	mu          sync.Mutex
+	credentials *Credentials
+	now         func() time.Time
+}
+
+// Retrieve :
+func (p *CachedProvider) Retrieve() (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	if p.credentials != nil && (p.credentials.Expiration.IsZero() ||
+		now().Add(credentialsRefreshWindow).Before(p.credentials.Expiration)) {
+		c := *p.credentials
+		return &c, nil
+	}
+	c, err := p.Provider.Retrieve()
+	if err != nil {
+		return nil, err
+	}
+	p.credentials = c
+	copied := *c
+	return &copied, nil
+}
+
+// NewCredentialProvider builds the provider chain from the config: the access key in the config,
+// the access key in the environment variables, and the ecs ram role if it is configured.
+// The role is assumed with the credentials from the chain if role_arn is configured.
+func NewCredentialProvider(cfg *GlobalConfig) CredentialProvider {
+	chain := &ChainProvider{Providers: []CredentialProvider{
+		&StaticProvider{Credentials: Credentials{
+			AccessKeyID:     cfg.AccessKeyID,
+			AccessKeySecret: cfg.AccessKeySecret,
+			SecurityToken:   cfg.SecurityToken,
+		}},
+		&EnvProvider{},
+	}}
+	if cfg.ECSRAMRole != "" || cfg.ECSMetadataURL != "" {
+		chain.Providers = append(chain.Providers, &ECSMetadataProvider{
+			URL:      cfg.ECSMetadataURL,
+			RoleName: cfg.ECSRAMRole,
+		})
+	}
+	var provider CredentialProvider = chain
+	if cfg.RoleARN != "" {
+		provider = &AssumeRoleProvider{
+			Source:          chain,
+			RoleARN:         cfg.RoleARN,
+			RoleSessionName: cfg.RoleSessionName,
+			DurationSeconds: cfg.RoleSessionDuration,
+			Endpoint:        cfg.STSEndpoint,
+		}
+	}
+	return &CachedProvider{Provider: provider}
+}
+
+// HasCredentialSource returns whether the config has any source of the credentials.
+func HasCredentialSource(cfg *GlobalConfig) bool {
+	if cfg.ECSRAMRole != "" || cfg.ECSMetadataURL != "" {
+		return true
+	}
+	_, err := (&ChainProvider{Providers: []CredentialProvider{
+		&StaticProvider{Credentials: Credentials{AccessKeyID: cfg.AccessKeyID, AccessKeySecret: cfg.AccessKeySecret}},
+		&EnvProvider{},
+	}}).Retrieve()
+	return err == nil
+}
+
+func httpGet(client *http.Client, url string) ([]byte, error) {
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d: %s", resp.StatusCode, string(body))
+	}
+	return body, nil
+}
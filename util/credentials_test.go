@@ -0,0 +1,154 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"time"
+)
+
+type countingProvider struct {
+	count       int
+	credentials Credentials
+}
+
+func (p *countingProvider) Retrieve() (*Credentials, error) {
+	p.count++
+	c := p.credentials
+	c.AccessKeyID = fmt.Sprintf("%s-%d", c.AccessKeyID, p.count)
+	return &c, nil
+}
+
+func (s *UtilTestSuite) TestChainProvider() {
+	assert := s.Require()
+	os.Unsetenv("ALIBABA_CLOUD_ACCESS_KEY_ID")
+	os.Unsetenv("ALIYUN_ACCESS_KEY_ID")
+
+	chain := &ChainProvider{Providers: []CredentialProvider{&StaticProvider{}, &EnvProvider{}}}
+	_, err := chain.Retrieve()
+	assert.Equal(ErrNoCredentials, err)
+
+	os.Setenv("ALIBABA_CLOUD_ACCESS_KEY_ID", "env-id")
+	os.Setenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET", "env-secret")
+	defer os.Unsetenv("ALIBABA_CLOUD_ACCESS_KEY_ID")
+	defer os.Unsetenv("ALIBABA_CLOUD_ACCESS_KEY_SECRET")
+	c, err := chain.Retrieve()
+	assert.Nil(err)
+	assert.Equal("env-id", c.AccessKeyID)
+
+	chain.Providers[0] = &StaticProvider{Credentials: Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}}
+	c, err = chain.Retrieve()
+	assert.Nil(err)
+	assert.Equal("id", c.AccessKeyID)
+	assert.True(HasCredentialSource(&GlobalConfig{}))
+}
+
+func (s *UtilTestSuite) TestCachedProvider() {
+	assert := s.Require()
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &countingProvider{credentials: Credentials{AccessKeyID: "id", Expiration: now.Add(time.Hour)}}
+	p := &CachedProvider{Provider: source, now: func() time.Time { return now }}
+
+	c, err := p.Retrieve()
+	assert.Nil(err)
+	assert.Equal("id-1", c.AccessKeyID)
+	now = now.Add(50 * time.Minute)
+	c, _ = p.Retrieve()
+	assert.Equal("id-1", c.AccessKeyID)
+
+	// refresh in the window before the expiration
+	now = now.Add(6 * time.Minute)
+	c, _ = p.Retrieve()
+	assert.Equal("id-2", c.AccessKeyID)
+}
+
+func (s *UtilTestSuite) TestECSMetadataProvider() {
+	assert := s.Require()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ram/security-credentials/":
+			fmt.Fprint(w, "ecs-role")
+		case "/ram/security-credentials/ecs-role":
+			fmt.Fprint(w, `{"Code": "Success", "AccessKeyId": "STS.id", "AccessKeySecret": "secret",
+				"SecurityToken": "token", "Expiration": "2018-01-01T01:00:00Z"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	c, err := (&ECSMetadataProvider{URL: server.URL + "/ram/security-credentials"}).Retrieve()
+	assert.Nil(err)
+	assert.Equal("STS.id", c.AccessKeyID)
+	assert.Equal("token", c.SecurityToken)
+	assert.Equal(time.Date(2018, 1, 1, 1, 0, 0, 0, time.UTC), c.Expiration)
+
+	_, err = (&ECSMetadataProvider{URL: server.URL + "/ram/security-credentials/", RoleName: "other"}).Retrieve()
+	assert.NotNil(err)
+}
+
+func (s *UtilTestSuite) TestAssumeRoleProvider() {
+	assert := s.Require()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("Action") != "AssumeRole" || r.Form.Get("AccessKeyId") != "id" ||
+			r.Form.Get("Signature") == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprintf(w, `{"Credentials": {"AccessKeyId": "STS.%s", "AccessKeySecret": "secret",
+			"SecurityToken": "%s", "Expiration": "2018-01-01T01:00:00Z"}}`,
+			r.Form.Get("RoleSessionName"), r.Form.Get("RoleArn"))
+	}))
+	defer server.Close()
+
+	p := &AssumeRoleProvider{
+		Source:   &StaticProvider{Credentials: Credentials{AccessKeyID: "id", AccessKeySecret: "secret"}},
+		RoleARN:  "acs:ram::123:role/fc",
+		Endpoint: server.URL,
+	}
+	c, err := p.Retrieve()
+	assert.Nil(err)
+	assert.Equal("STS.fcli", c.AccessKeyID)
+	assert.Equal("acs:ram::123:role/fc", c.SecurityToken)
+
+	p.Source = &StaticProvider{Credentials: Credentials{AccessKeyID: "other", AccessKeySecret: "secret"}}
+	_, err = p.Retrieve()
+	assert.NotNil(err)
+}
+
+func (s *UtilTestSuite) TestClientsRefreshCredentials() {
+	assert := s.Require()
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	source := &countingProvider{credentials: Credentials{
+		AccessKeyID: "STS.id", AccessKeySecret: "secret", SecurityToken: "token", Expiration: now.Add(10 * time.Minute)}}
+	cfg := NewGlobalConfig()
+	cfg.Endpoint, cfg.SLSEndpoint = "http://1.cn-hangzhou.fc.aliyuncs.com", "cn-hangzhou.log.aliyuncs.com"
+	cfg.credentialProvider = &CachedProvider{Provider: source, now: func() time.Time { return now }}
+
+	fcClient, err := NewFClient(cfg)
+	assert.Nil(err)
+	slsClient, err := NewSLSClient(cfg)
+	assert.Nil(err)
+	client, err := fcClient.client()
+	assert.Nil(err)
+	assert.Equal("STS.id-1", client.Config.AccessKeyID)
+	assert.Equal("token", client.Config.SecurityToken)
+	assert.Equal("STS.id-1", slsClient.AccessKeyID)
+
+	// the client is kept until the credentials are renewed in the window before the expiration
+	now = now.Add(time.Minute)
+	same, err := fcClient.client()
+	assert.Nil(err)
+	assert.True(client == same)
+	now = now.Add(5 * time.Minute)
+	source.credentials.Expiration = now.Add(time.Hour)
+	client, err = fcClient.client()
+	assert.Nil(err)
+	assert.Equal("STS.id-2", client.Config.AccessKeyID)
+	assert.Equal(cfg.Endpoint, client.Config.Endpoint)
+	assert.True(client == fcClient.sdk)
+	assert.Nil(RefreshSLSClient(slsClient, cfg))
+	assert.Equal("STS.id-2", slsClient.AccessKeyID)
+}
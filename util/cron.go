@@ -0,0 +1,305 @@
+package util
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EveryPrefix defines the prefix of the fixed interval timer expression, such as "@every 5m"
+	EveryPrefix = "@every"
+
+	// MinEveryInterval defines the minimal interval supported by the fixed interval timer expression
+	MinEveryInterval = time.Minute
+
+	// maxCronSearchYears bounds the search of the next fire time, so that an expression
+	// that never fires (e.g. "0 0 0 30 2 *") does not loop forever.
+	maxCronSearchYears = 5
+)
+
+// CronSchedule describes when a timer trigger fires.
+type CronSchedule interface {
+	// Next returns the next fire time strictly after t.
+	Next(t time.Time) time.Time
+}
+
+// cronField defines the name and the value range of a cron expression field.
+type cronField struct {
+	name  string
+	min   uint
+	max   uint
+	names map[string]uint
+}
+
+var (
+	secondField = cronField{name: "second", min: 0, max: 59}
+	minuteField = cronField{name: "minute", min: 0, max: 59}
+	hourField   = cronField{name: "hour", min: 0, max: 23}
+	domField    = cronField{name: "day of month", min: 1, max: 31}
+	monthField  = cronField{name: "month", min: 1, max: 12, names: map[string]uint{
+		"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+		"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+	}}
+	// both 0 and 7 stand for sunday
+	dowField = cronField{name: "day of week", min: 0, max: 7, names: map[string]uint{
+		"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+	}}
+)
+
+// SpecSchedule is the schedule of a six fields cron expression:
+// second minute hour day-of-month month day-of-week.
+type SpecSchedule struct {
+	second, minute, hour, dom, month, dow uint64
+	// domStar and dowStar record whether the day fields are unrestricted ("*" or "?")
+	domStar, dowStar bool
+}
+
+// EverySchedule is the schedule of a fixed interval expression such as "@every 1h30m".
+// The fire times are aligned to Anchor, which is usually the last modified time of the trigger.
+type EverySchedule struct {
+	Interval time.Duration
+	Anchor   time.Time
+}
+
+// ParseCronExpression parse the cron expression of the timer trigger.
+// Both the six fields cron expression (in UTC) and "@every <duration>" are supported.
+func ParseCronExpression(expr string) (CronSchedule, error) {
+	expr = strings.TrimSpace(expr)
+	if expr == "" {
+		return nil, fmt.Errorf("empty cron expression")
+	}
+
+	if strings.HasPrefix(expr, "@") {
+		return parseEveryExpression(expr)
+	}
+
+	fields := strings.Fields(expr)
+	if len(fields) != 6 {
+		return nil, fmt.Errorf(
+			"invalid cron expression %q: expect 6 fields (second minute hour day-of-month month day-of-week), actual %d",
+			expr, len(fields))
+	}
+
+	var err error
+	s := &SpecSchedule{}
+	if s.second, err = parseCronField(fields[0], secondField, false); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if s.minute, err = parseCronField(fields[1], minuteField, false); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if s.hour, err = parseCronField(fields[2], hourField, false); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if s.dom, err = parseCronField(fields[3], domField, true); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if s.month, err = parseCronField(fields[4], monthField, false); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if s.dow, err = parseCronField(fields[5], dowField, true); err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	s.domStar = fields[3] == "*" || fields[3] == "?"
+	s.dowStar = fields[5] == "*" || fields[5] == "?"
+	if s.Next(time.Now().UTC()).IsZero() {
+		return nil, NewValidationError("invalid cron expression %q: it never fires", expr)
+	}
+	return s, nil
+}
+
+func parseEveryExpression(expr string) (CronSchedule, error) {
+	if !strings.HasPrefix(expr, EveryPrefix+" ") {
+		return nil, NewValidationError("invalid cron expression %q: only %s is supported, e.g. \"@every 5m\"", expr, EveryPrefix)
+	}
+	interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(expr, EveryPrefix)))
+	if err != nil {
+		return nil, NewValidationError("invalid cron expression %q: %v", expr, err)
+	}
+	if interval < MinEveryInterval {
+		return nil, NewValidationError("invalid cron expression %q: the interval must be at least %v", expr, MinEveryInterval)
+	}
+	return &EverySchedule{Interval: interval}, nil
+}
+
+// parseCronField parse one field into a bit set, each set bit is an allowed value.
+func parseCronField(field string, f cronField, allowQuestion bool) (uint64, error) {
+	var bits uint64
+	for _, expr := range strings.Split(field, ",") {
+		b, err := parseCronRange(expr, f, allowQuestion)
+		if err != nil {
+			return 0, err
+		}
+		bits |= b
+	}
+	if f.name == dowField.name && bits&(1<<7) != 0 {
+		bits = bits&^(1<<7) | 1
+	}
+	return bits, nil
+}
+
+func parseCronRange(expr string, f cronField, allowQuestion bool) (uint64, error) {
+	if expr == "" {
+		return 0, fmt.Errorf("empty value in %s field", f.name)
+	}
+	rangeAndStep := strings.Split(expr, "/")
+	if len(rangeAndStep) > 2 {
+		return 0, fmt.Errorf("too many slashes in %s field: %s", f.name, expr)
+	}
+
+	var start, end uint
+	lowAndHigh := strings.Split(rangeAndStep[0], "-")
+	switch {
+	case lowAndHigh[0] == "*" || lowAndHigh[0] == "?":
+		if lowAndHigh[0] == "?" && !allowQuestion {
+			return 0, fmt.Errorf("'?' is only allowed in day-of-month and day-of-week fields")
+		}
+		if len(lowAndHigh) > 1 {
+			return 0, NewValidationError("invalid range in %s field: %s", f.name, expr)
+		}
+		start, end = f.min, f.max
+	default:
+		var err error
+		if start, err = parseCronValue(lowAndHigh[0], f); err != nil {
+			return 0, err
+		}
+		switch len(lowAndHigh) {
+		case 1:
+			end = start
+		case 2:
+			if end, err = parseCronValue(lowAndHigh[1], f); err != nil {
+				return 0, err
+			}
+		default:
+			return 0, fmt.Errorf("too many hyphens in %s field: %s", f.name, expr)
+		}
+	}
+
+	step := uint(1)
+	if len(rangeAndStep) == 2 {
+		n, err := strconv.ParseUint(rangeAndStep[1], 10, 8)
+		if err != nil || n == 0 {
+			return 0, NewValidationError("invalid step in %s field: %s", f.name, expr)
+		}
+		step = uint(n)
+		// "N/step" means from N to the max value
+		if len(lowAndHigh) == 1 {
+			end = f.max
+		}
+	}
+	if start > end {
+		return 0, fmt.Errorf("beginning of range (%d) beyond end of range (%d) in %s field: %s",
+			start, end, f.name, expr)
+	}
+
+	var bits uint64
+	for i := start; i <= end; i += step {
+		bits |= 1 << i
+	}
+	return bits, nil
+}
+
+func parseCronValue(s string, f cronField) (uint, error) {
+	if v, ok := f.names[strings.ToLower(s)]; ok {
+		return v, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil {
+		return 0, NewValidationError("invalid value in %s field: %s", f.name, s)
+	}
+	v := uint(n)
+	if v < f.min || v > f.max {
+		return 0, fmt.Errorf("value %d out of range [%d, %d] in %s field", v, f.min, f.max, f.name)
+	}
+	return v, nil
+}
+
+// Next returns the next fire time after t, evaluated in UTC.
+// The zero time is returned if the expression does not fire within the next years.
+func (s *SpecSchedule) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Second).Add(time.Second)
+	yearLimit := t.Year() + maxCronSearchYears
+
+WRAP:
+	if t.Year() > yearLimit {
+		return time.Time{}
+	}
+
+	for 1<<uint(t.Month())&s.month == 0 {
+		t = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, 1, 0)
+		if t.Month() == time.January {
+			goto WRAP
+		}
+	}
+
+	for !s.dayMatches(t) {
+		month := t.Month()
+		t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).AddDate(0, 0, 1)
+		if t.Month() != month {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Hour())&s.hour == 0 {
+		day := t.Day()
+		t = t.Truncate(time.Hour).Add(time.Hour)
+		if t.Day() != day {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Minute())&s.minute == 0 {
+		hour := t.Hour()
+		t = t.Truncate(time.Minute).Add(time.Minute)
+		if t.Hour() != hour {
+			goto WRAP
+		}
+	}
+
+	for 1<<uint(t.Second())&s.second == 0 {
+		minute := t.Minute()
+		t = t.Add(time.Second)
+		if t.Minute() != minute {
+			goto WRAP
+		}
+	}
+
+	return t
+}
+
+// dayMatches follows the cron convention: when both day fields are restricted,
+// the day matches if either of them matches.
+func (s *SpecSchedule) dayMatches(t time.Time) bool {
+	domMatch := 1<<uint(t.Day())&s.dom > 0
+	dowMatch := 1<<uint(t.Weekday())&s.dow > 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// Next returns the next fire time after t. If the anchor is unknown,
+// the fire times are aligned to t itself.
+func (s *EverySchedule) Next(t time.Time) time.Time {
+	if s.Anchor.IsZero() || s.Anchor.After(t) {
+		return t.Add(s.Interval).Truncate(time.Second)
+	}
+	elapsed := t.Sub(s.Anchor)
+	n := elapsed/s.Interval + 1
+	return s.Anchor.Add(n * s.Interval)
+}
+
+// NextFireTimes returns the next count fire times after t.
+func NextFireTimes(schedule CronSchedule, t time.Time, count int) []time.Time {
+	ret := []time.Time{}
+	for i := 0; i < count; i++ {
+		t = schedule.Next(t)
+		if t.IsZero() {
+			break
+		}
+		ret = append(ret, t)
+	}
+	return ret
+}
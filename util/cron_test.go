@@ -0,0 +1,49 @@
+package util
+
+import (
+	"time"
+)
+
+func (s *UtilTestSuite) TestParseCronExpression() {
+	for _, expr := range []string{
+		"0 0/5 * * * *",
+		"0 0 8 ? * MON-FRI",
+		"0 30 1 1,15 * ?",
+		"0 0 0 * * 5-7",
+		"@every 1h30m",
+	} {
+		_, err := ParseCronExpression(expr)
+		s.Nil(err, expr)
+	}
+
+	for _, expr := range []string{
+		"",
+		"0 0 * * *",
+		"60 * * * * *",
+		"0 ? * * * *",
+		"0 0 0 30 2 ?",
+		"@every 30s",
+		"@daily",
+	} {
+		_, err := ParseCronExpression(expr)
+		s.NotNil(err, expr)
+	}
+}
+
+func (s *UtilTestSuite) TestNextFireTimes() {
+	start := time.Date(2018, time.January, 31, 23, 58, 0, 0, time.UTC)
+
+	schedule, err := ParseCronExpression("0 0/1 * * * *")
+	s.Nil(err)
+	s.Equal([]time.Time{
+		time.Date(2018, time.January, 31, 23, 59, 0, 0, time.UTC),
+		time.Date(2018, time.February, 1, 0, 0, 0, 0, time.UTC),
+	}, NextFireTimes(schedule, start, 2))
+
+	schedule, err = ParseCronExpression("0 0 8 ? * MON")
+	s.Nil(err)
+	s.Equal(time.Date(2018, time.February, 5, 8, 0, 0, 0, time.UTC), schedule.Next(start))
+
+	every := &EverySchedule{Interval: time.Hour, Anchor: start.Add(-90 * time.Minute)}
+	s.Equal(start.Add(30*time.Minute), every.Next(start))
+}
@@ -0,0 +1,225 @@
+package util
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+)
+
+// ErrorClass is the class of an error, which decides the exit code of fcli.
+type ErrorClass string
+
+// the error classes
+const (
+	ErrorClassUnknown    ErrorClass = "Unknown"
+	ErrorClassValidation ErrorClass = "Validation"
+	ErrorClassAuth       ErrorClass = "Auth"
+	ErrorClassNotFound   ErrorClass = "NotFound"
+	ErrorClassConflict   ErrorClass = "Conflict"
+	ErrorClassThrottled  ErrorClass = "Throttled"
+)
+
+// the exit codes of the error classes, which are stable for the scripts
+const (
+	ExitCodeOK         = 0
+	ExitCodeUnknown    = 1
+	ExitCodeValidation = 2
+	ExitCodeAuth       = 3
+	ExitCodeNotFound   = 4
+	ExitCodeConflict   = 5
+	ExitCodeThrottled  = 6
+)
+
+var exitCodes = map[ErrorClass]int{
+	ErrorClassUnknown:    ExitCodeUnknown,
+	ErrorClassValidation: ExitCodeValidation,
+	ErrorClassAuth:       ExitCodeAuth,
+	ErrorClassNotFound:   ExitCodeNotFound,
+	ErrorClassConflict:   ExitCodeConflict,
+	ErrorClassThrottled:  ExitCodeThrottled,
+}
+
+// the services whose api errors are wrapped
+const (
+	ServiceFC  = "fc"
+	ServiceRAM = "ram"
+	ServiceSLS = "sls"
+)
+
+// the error codes of the invalid credentials, which are returned with 400 or 403 by some services
+var authErrorCodes = []string{"InvalidAccessKeyId", "SignatureNotMatch", "SignatureDoesNotMatch",
+	"InvalidSecurityToken", "SecurityTokenExpired", "Unauthorized", "AccessDenied", "NoPermission", "Forbidden"}
+
+// APIError is the failure of a fc, ram or sls api call.
+type APIError struct {
+	Service    string `json:"service"`
+	HTTPStatus int    `json:"httpStatus,omitempty"`
+	Code       string `json:"code,omitempty"`
+	Message    string `json:"message"`
+	RequestID  string `json:"requestId,omitempty"`
+	Hint       string `json:"hint,omitempty"`
+	// Context is what fcli was doing when the api failed, such as "failed to create role demo"
+	Context string `json:"context,omitempty"`
+}
+
+func (e *APIError) Error() string {
+	msg := e.Message
+	if e.Code != "" {
+		msg = e.Code + ": " + msg
+	}
+	if e.RequestID != "" {
+		msg += fmt.Sprintf(" (request id: %s)", e.RequestID)
+	}
+	if e.Context != "" {
+		msg = e.Context + ": " + msg
+	}
+	return msg
+}
+
+// Class returns the class of the error by the http status and the error code.
+func (e *APIError) Class() ErrorClass {
+	for _, code := range authErrorCodes {
+		if strings.HasPrefix(e.Code, code) {
+			return ErrorClassAuth
+		}
+	}
+	switch {
+	case e.HTTPStatus == http.StatusTooManyRequests || strings.Contains(e.Code, "Throttl"):
+		return ErrorClassThrottled
+	case e.HTTPStatus == http.StatusUnauthorized || e.HTTPStatus == http.StatusForbidden:
+		return ErrorClassAuth
+	case e.HTTPStatus == http.StatusNotFound || strings.HasSuffix(e.Code, "NotFound") ||
+		strings.HasSuffix(e.Code, "NotExist") || strings.HasPrefix(e.Code, "EntityNotExist"):
+		return ErrorClassNotFound
+	case e.HTTPStatus == http.StatusConflict || e.HTTPStatus == http.StatusPreconditionFailed ||
+		strings.Contains(e.Code, "AlreadyExist"):
+		return ErrorClassConflict
+	case e.HTTPStatus == http.StatusBadRequest:
+		return ErrorClassValidation
+	}
+	return ErrorClassUnknown
+}
+
+// ValidationError is the error of the invalid flags, arguments, input or config of a command.
+type ValidationError struct {
+	Message string
+	Hint    string
+}
+
+// NewValidationError creates the validation error of the message.
+func NewValidationError(format string, args ...interface{}) *ValidationError {
+	return &ValidationError{Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// WithHint sets the hint of the error.
+func (e *ValidationError) WithHint(hint string) *ValidationError {
+	e.Hint = hint
+	return e
+}
+
+// WrapError converts the errors of the fc, ram and sls sdks to *APIError with a hint of how to fix
+// the error, the other errors are returned as is.
+func WrapError(err error) error {
+	var e *APIError
+	switch v := err.(type) {
+	case fc.ServiceError:
+		e = &APIError{Service: ServiceFC, HTTPStatus: v.HTTPStatus, Code: v.ErrorCode,
+			Message: v.ErrorMessage, RequestID: v.RequestID}
+	case *fc.ServiceError:
+		if v == nil {
+			return err
+		}
+		return WrapError(*v)
+	case ram.ServiceError:
+		e = &APIError{Service: ServiceRAM, HTTPStatus: v.HTTPStatus, Code: v.ErrorCode,
+			Message: v.ErrorMessage, RequestID: v.RequestID}
+	case sls.Error:
+		e = &APIError{Service: ServiceSLS, HTTPStatus: int(v.HTTPCode), Code: v.Code,
+			Message: v.Message, RequestID: v.RequestID}
+	case *sls.Error:
+		if v == nil {
+			return err
+		}
+		return WrapError(*v)
+	default:
+		return err
+	}
+	e.Hint = errorHint(e)
+	return e
+}
+
+// WrapErrorf prefixes the error with the context of the format, the class, the hint and the fields
+// of the api and validation errors are kept, so that the exit code and the json envelope do not change.
+func WrapErrorf(err error, format string, args ...interface{}) error {
+	context := fmt.Sprintf(format, args...)
+	switch e := WrapError(err).(type) {
+	case *APIError:
+		wrapped := *e
+		if wrapped.Context != "" {
+			context += ": " + wrapped.Context
+		}
+		wrapped.Context = context
+		return &wrapped
+	case *ValidationError:
+		wrapped := *e
+		wrapped.Message = context + ": " + e.Message
+		return &wrapped
+	}
+	return fmt.Errorf("%s: %v", context, err)
+}
+
+func errorHint(e *APIError) string {
+	switch e.Class() {
+	case ErrorClassAuth:
+		if e.HTTPStatus == http.StatusForbidden || e.Code == "NoPermission" || e.Code == "AccessDenied" {
+			return "please check your role's policy has the access for these resources"
+		}
+		return "please check the access_key_id, access_key_secret and security_token of the config"
+	case ErrorClassThrottled:
+		return "the request is throttled after the retries, please try again later or raise max_attempts of the config"
+	case ErrorClassValidation:
+		if e.Service == ServiceSLS {
+			return "please check your region of log project, make sure the sls_endpoint " +
+				"in the config match log's region"
+		}
+	}
+	return ""
+}
+
+// ClassOf returns the class of the error.
+func ClassOf(err error) ErrorClass {
+	switch e := WrapError(err).(type) {
+	case *APIError:
+		return e.Class()
+	case *ValidationError:
+		return ErrorClassValidation
+	}
+	return ErrorClassUnknown
+}
+
+// ExitCode returns the exit code of the error, 0 if it is nil.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeOK
+	}
+	return exitCodes[ClassOf(err)]
+}
+
+// ErrorHint returns the hint of the error, or empty if there is no hint.
+func ErrorHint(err error) string {
+	switch e := WrapError(err).(type) {
+	case *APIError:
+		return e.Hint
+	case *ValidationError:
+		return e.Hint
+	}
+	return ""
+}
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"errors"
+
+	"github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+)
+
+func (s *UtilTestSuite) TestWrapError() {
+	assert := s.Require()
+	err := WrapError(&fc.ServiceError{HTTPStatus: 404, ErrorCode: "ServiceNotFound",
+		ErrorMessage: "service 'demo' does not exist", RequestID: "req-1"})
+	e, ok := err.(*APIError)
+	assert.True(ok)
+	assert.Equal(ServiceFC, e.Service)
+	assert.Equal("ServiceNotFound: service 'demo' does not exist (request id: req-1)", e.Error())
+	assert.Equal(ErrorClassNotFound, ClassOf(err))
+	assert.Equal(ExitCodeNotFound, ExitCode(err))
+
+	err = WrapError(ram.ServiceError{HTTPStatus: 403, ErrorCode: "NoPermission", ErrorMessage: "denied"})
+	assert.Equal(ErrorClassAuth, ClassOf(err))
+	assert.Contains(ErrorHint(err), "role's policy")
+
+	err = WrapError(sls.Error{HTTPCode: 400, Code: "ParameterInvalid", Message: "invalid"})
+	assert.Equal(ServiceSLS, err.(*APIError).Service)
+	assert.Equal(ExitCodeValidation, ExitCode(err))
+	assert.Contains(ErrorHint(err), "sls_endpoint")
+
+	plain := errors.New("connection refused")
+	assert.Equal(plain, WrapError(plain))
+	assert.Equal(ExitCodeUnknown, ExitCode(plain))
+	assert.Equal(ExitCodeOK, ExitCode(nil))
+}
+
+func (s *UtilTestSuite) TestWrapErrorf() {
+	assert := s.Require()
+	cause := ram.ServiceError{HTTPStatus: 404, ErrorCode: "EntityNotExist.Role", ErrorMessage: "no role", RequestID: "req-1"}
+	err := WrapErrorf(WrapErrorf(cause, "failed to list policies of role %s", "demo"), "failed to simulate")
+	e, ok := err.(*APIError)
+	assert.True(ok)
+	assert.Equal("failed to simulate: failed to list policies of role demo", e.Context)
+	assert.Equal("failed to simulate: failed to list policies of role demo: EntityNotExist.Role: no role (request id: req-1)",
+		e.Error())
+	assert.Equal("req-1", e.RequestID)
+	assert.Equal(ExitCodeNotFound, ExitCode(err))
+
+	err = WrapErrorf(NewValidationError("invalid arn").WithHint("check the arn"), "failed to apply the policy")
+	assert.Equal("failed to apply the policy: invalid arn", err.Error())
+	assert.Equal(ExitCodeValidation, ExitCode(err))
+	assert.Equal("check the arn", ErrorHint(err))
+
+	err = WrapErrorf(errors.New("connection refused"), "failed to get role %s", "demo")
+	assert.Equal("failed to get role demo: connection refused", err.Error())
+	assert.Equal(ExitCodeUnknown, ExitCode(err))
+}
+
+func (s *UtilTestSuite) TestErrorClass() {
+	assert := s.Require()
+	cases := []struct {
+		status int
+		code   string
+		class  ErrorClass
+	}{
+		{409, "ServiceAlreadyExists", ErrorClassConflict},
+		{412, "PreconditionFailed", ErrorClassConflict},
+		{400, "EntityAlreadyExists.Role", ErrorClassConflict},
+		{429, "ResourceThrottled", ErrorClassThrottled},
+		{503, "Throttling.User", ErrorClassThrottled},
+		{401, "InvalidAccessKeyId.NotFound", ErrorClassAuth},
+		{400, "SignatureDoesNotMatch", ErrorClassAuth},
+		{404, "EntityNotExist.Role", ErrorClassNotFound},
+		{400, "ProjectNotExist", ErrorClassNotFound},
+		{400, "InvalidArgument", ErrorClassValidation},
+		{500, "InternalServerError", ErrorClassUnknown},
+	}
+	for _, c := range cases {
+		assert.Equal(c.class, (&APIError{HTTPStatus: c.status, Code: c.code}).Class(), c.code)
+	}
+
+	err := NewValidationError("-s(--service-name) is required").WithHint("run 'fcli service get --help'")
+	assert.Equal(ErrorClassValidation, ClassOf(err))
+	assert.Equal(ExitCodeValidation, ExitCode(err))
+	assert.Equal("run 'fcli service get --help'", ErrorHint(err))
+}
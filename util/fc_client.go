@@ -0,0 +1,329 @@
+package util
+
+import (
+	"sync"
+
+	"github.com/aliyun/fc-go-sdk"
+)
+
+// FClient is the fc client whose api calls are retried by the retryer of the config.
+// The create, publish and invoke calls are not idempotent, so they are retried only on throttling.
+// Neither are the updates with If-Match, whose etag has changed if a lost attempt was processed.
+// The client is rebuilt with the refreshed credentials once the temporary ones are renewed,
+// so that a long-lived client, such as the one of the shell, outlives the credentials.
+type FClient struct {
+	// the sdk client is only used through the wrapped calls, which hold the lock to read it
+	sdk     *fc.Client
+	retryer *Retryer
+
+	mu          sync.Mutex
+	credentials func() (*Credentials, error)
+	current     Credentials
+	build       func(c *Credentials) (*fc.Client, error)
+}
+
+// NewRetryFClient wraps the fc client with the retryer.
+func NewRetryFClient(client *fc.Client, retryer *Retryer) *FClient {
+	return &FClient{sdk: client, retryer: retryer}
+}
+
+// Endpoint returns the fc endpoint of the client.
+func (c *FClient) Endpoint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.sdk.Config.Endpoint
+}
+
+// client returns the fc client signing with the current credentials, it is rebuilt if they changed.
+func (c *FClient) client() (*fc.Client, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.credentials == nil {
+		return c.sdk, nil
+	}
+	cred, err := c.credentials()
+	if err != nil {
+		return nil, err
+	}
+	if *cred == c.current {
+		return c.sdk, nil
+	}
+	client, err := c.build(cred)
+	if err != nil {
+		return nil, err
+	}
+	c.sdk, c.current = client, *cred
+	return client, nil
+}
+
+// do calls the api by the fc client of the current credentials with retries, the Retry-After of the
+// failed responses is attached to the errors for the retryer.
+func (c *FClient) do(api string, idempotent bool, call func(client *fc.Client) error) error {
+	err := c.retryer.Do(api, idempotent, func() error {
+		client, err := c.client()
+		if err != nil {
+			return err
+		}
+		return withRetryAfter(call(client))
+	})
+	return withoutRetryAfter(err)
+}
+
+// doDelete calls the delete api with retries. The resource may have been deleted by an attempt whose
+// response was lost, so it is not found by the retry, which is the success of the delete.
+func (c *FClient) doDelete(api string, call func(client *fc.Client) error) error {
+	var last error
+	return c.do(api, true, func(client *fc.Client) error {
+		err := call(client)
+		if last != nil && !IsThrottlingError(last) && ClassOf(err) == ErrorClassNotFound {
+			return nil
+		}
+		last = err
+		return err
+	})
+}
+
+// withoutIfMatch returns whether the update has no etag to match, so that it can be retried.
+func withoutIfMatch(ifMatch *string) bool {
+	return ifMatch == nil || *ifMatch == ""
+}
+
+// CreateService calls fc CreateService with retries.
+func (c *FClient) CreateService(input *fc.CreateServiceInput) (output *fc.CreateServiceOutput, err error) {
+	err = c.do("CreateService", false, func(client *fc.Client) error {
+		output, err = client.CreateService(input)
+		return err
+	})
+	return output, err
+}
+
+// UpdateService calls fc UpdateService with retries.
+func (c *FClient) UpdateService(input *fc.UpdateServiceInput) (output *fc.UpdateServiceOutput, err error) {
+	err = c.do("UpdateService", withoutIfMatch(input.IfMatch), func(client *fc.Client) error {
+		output, err = client.UpdateService(input)
+		return err
+	})
+	return output, err
+}
+
+// GetService calls fc GetService with retries.
+func (c *FClient) GetService(input *fc.GetServiceInput) (output *fc.GetServiceOutput, err error) {
+	err = c.do("GetService", true, func(client *fc.Client) error {
+		output, err = client.GetService(input)
+		return err
+	})
+	return output, err
+}
+
+// DeleteService calls fc DeleteService with retries.
+func (c *FClient) DeleteService(input *fc.DeleteServiceInput) (output *fc.DeleteServiceOutput, err error) {
+	err = c.doDelete("DeleteService", func(client *fc.Client) error {
+		output, err = client.DeleteService(input)
+		return err
+	})
+	if err == nil && output == nil {
+		output = &fc.DeleteServiceOutput{}
+	}
+	return output, err
+}
+
+// ListServices calls fc ListServices with retries.
+func (c *FClient) ListServices(input *fc.ListServicesInput) (output *fc.ListServicesOutput, err error) {
+	err = c.do("ListServices", true, func(client *fc.Client) error {
+		output, err = client.ListServices(input)
+		return err
+	})
+	return output, err
+}
+
+// CreateFunction calls fc CreateFunction with retries.
+func (c *FClient) CreateFunction(input *fc.CreateFunctionInput) (output *fc.CreateFunctionOutput, err error) {
+	err = c.do("CreateFunction", false, func(client *fc.Client) error {
+		output, err = client.CreateFunction(input)
+		return err
+	})
+	return output, err
+}
+
+// UpdateFunction calls fc UpdateFunction with retries.
+func (c *FClient) UpdateFunction(input *fc.UpdateFunctionInput) (output *fc.UpdateFunctionOutput, err error) {
+	err = c.do("UpdateFunction", withoutIfMatch(input.IfMatch), func(client *fc.Client) error {
+		output, err = client.UpdateFunction(input)
+		return err
+	})
+	return output, err
+}
+
+// GetFunction calls fc GetFunction with retries.
+func (c *FClient) GetFunction(input *fc.GetFunctionInput) (output *fc.GetFunctionOutput, err error) {
+	err = c.do("GetFunction", true, func(client *fc.Client) error {
+		output, err = client.GetFunction(input)
+		return err
+	})
+	return output, err
+}
+
+// GetFunctionCode calls fc GetFunctionCode with retries.
+func (c *FClient) GetFunctionCode(input *fc.GetFunctionCodeInput) (output *fc.GetFunctionCodeOutput, err error) {
+	err = c.do("GetFunctionCode", true, func(client *fc.Client) error {
+		output, err = client.GetFunctionCode(input)
+		return err
+	})
+	return output, err
+}
+
+// DeleteFunction calls fc DeleteFunction with retries.
+func (c *FClient) DeleteFunction(input *fc.DeleteFunctionInput) (output *fc.DeleteFunctionOutput, err error) {
+	err = c.doDelete("DeleteFunction", func(client *fc.Client) error {
+		output, err = client.DeleteFunction(input)
+		return err
+	})
+	if err == nil && output == nil {
+		output = &fc.DeleteFunctionOutput{}
+	}
+	return output, err
+}
+
+// ListFunctions calls fc ListFunctions with retries.
+func (c *FClient) ListFunctions(input *fc.ListFunctionsInput) (output *fc.ListFunctionsOutput, err error) {
+	err = c.do("ListFunctions", true, func(client *fc.Client) error {
+		output, err = client.ListFunctions(input)
+		return err
+	})
+	return output, err
+}
+
+// InvokeFunction calls fc InvokeFunction with retries.
+func (c *FClient) InvokeFunction(input *fc.InvokeFunctionInput) (output *fc.InvokeFunctionOutput, err error) {
+	err = c.do("InvokeFunction", false, func(client *fc.Client) error {
+		output, err = client.InvokeFunction(input)
+		return err
+	})
+	return output, err
+}
+
+// CreateTrigger calls fc CreateTrigger with retries.
+func (c *FClient) CreateTrigger(input *fc.CreateTriggerInput) (output *fc.CreateTriggerOutput, err error) {
+	err = c.do("CreateTrigger", false, func(client *fc.Client) error {
+		output, err = client.CreateTrigger(input)
+		return err
+	})
+	return output, err
+}
+
+// UpdateTrigger calls fc UpdateTrigger with retries.
+func (c *FClient) UpdateTrigger(input *fc.UpdateTriggerInput) (output *fc.UpdateTriggerOutput, err error) {
+	err = c.do("UpdateTrigger", withoutIfMatch(input.IfMatch), func(client *fc.Client) error {
+		output, err = client.UpdateTrigger(input)
+		return err
+	})
+	return output, err
+}
+
+// GetTrigger calls fc GetTrigger with retries.
+func (c *FClient) GetTrigger(input *fc.GetTriggerInput) (output *fc.GetTriggerOutput, err error) {
+	err = c.do("GetTrigger", true, func(client *fc.Client) error {
+		output, err = client.GetTrigger(input)
+		return err
+	})
+	return output, err
+}
+
+// DeleteTrigger calls fc DeleteTrigger with retries.
+func (c *FClient) DeleteTrigger(input *fc.DeleteTriggerInput) (output *fc.DeleteTriggerOutput, err error) {
+	err = c.doDelete("DeleteTrigger", func(client *fc.Client) error {
+		output, err = client.DeleteTrigger(input)
+		return err
+	})
+	if err == nil && output == nil {
+		output = &fc.DeleteTriggerOutput{}
+	}
+	return output, err
+}
+
+// ListTriggers calls fc ListTriggers with retries.
+func (c *FClient) ListTriggers(input *fc.ListTriggersInput) (output *fc.ListTriggersOutput, err error) {
+	err = c.do("ListTriggers", true, func(client *fc.Client) error {
+		output, err = client.ListTriggers(input)
+		return err
+	})
+	return output, err
+}
+
+// PublishServiceVersion calls fc PublishServiceVersion with retries.
+func (c *FClient) PublishServiceVersion(input *fc.PublishServiceVersionInput) (output *fc.PublishServiceVersionOutput, err error) {
+	err = c.do("PublishServiceVersion", false, func(client *fc.Client) error {
+		output, err = client.PublishServiceVersion(input)
+		return err
+	})
+	return output, err
+}
+
+// ListServiceVersions calls fc ListServiceVersions with retries.
+func (c *FClient) ListServiceVersions(input *fc.ListServiceVersionsInput) (output *fc.ListServiceVersionsOutput, err error) {
+	err = c.do("ListServiceVersions", true, func(client *fc.Client) error {
+		output, err = client.ListServiceVersions(input)
+		return err
+	})
+	return output, err
+}
+
+// DeleteServiceVersion calls fc DeleteServiceVersion with retries.
+func (c *FClient) DeleteServiceVersion(input *fc.DeleteServiceVersionInput) (output *fc.DeleteServiceVersionOutput, err error) {
+	err = c.doDelete("DeleteServiceVersion", func(client *fc.Client) error {
+		output, err = client.DeleteServiceVersion(input)
+		return err
+	})
+	if err == nil && output == nil {
+		output = &fc.DeleteServiceVersionOutput{}
+	}
+	return output, err
+}
+
+// CreateAlias calls fc CreateAlias with retries.
+func (c *FClient) CreateAlias(input *fc.CreateAliasInput) (output *fc.CreateAliasOutput, err error) {
+	err = c.do("CreateAlias", false, func(client *fc.Client) error {
+		output, err = client.CreateAlias(input)
+		return err
+	})
+	return output, err
+}
+
+// UpdateAlias calls fc UpdateAlias with retries.
+func (c *FClient) UpdateAlias(input *fc.UpdateAliasInput) (output *fc.UpdateAliasOutput, err error) {
+	err = c.do("UpdateAlias", withoutIfMatch(input.IfMatch), func(client *fc.Client) error {
+		output, err = client.UpdateAlias(input)
+		return err
+	})
+	return output, err
+}
+
+// GetAlias calls fc GetAlias with retries.
+func (c *FClient) GetAlias(input *fc.GetAliasInput) (output *fc.GetAliasOutput, err error) {
+	err = c.do("GetAlias", true, func(client *fc.Client) error {
+		output, err = client.GetAlias(input)
+		return err
+	})
+	return output, err
+}
+
+// DeleteAlias calls fc DeleteAlias with retries.
+func (c *FClient) DeleteAlias(input *fc.DeleteAliasInput) (output *fc.DeleteAliasOutput, err error) {
+	err = c.doDelete("DeleteAlias", func(client *fc.Client) error {
+		output, err = client.DeleteAlias(input)
+		return err
+	})
+	if err == nil && output == nil {
+		output = &fc.DeleteAliasOutput{}
+	}
+	return output, err
+}
+
+// ListAliases calls fc ListAliases with retries.
+func (c *FClient) ListAliases(input *fc.ListAliasesInput) (output *fc.ListAliasesOutput, err error) {
+	err = c.do("ListAliases", true, func(client *fc.Client) error {
+		output, err = client.ListAliases(input)
+		return err
+	})
+	return output, err
+}
@@ -0,0 +1,207 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/fcli/ram"
+)
+
+// Policy evaluation decisions.
+const (
+	PolicyDecisionAllow        = "Allow"
+	PolicyDecisionExplicitDeny = "ExplicitDeny"
+	PolicyDecisionImplicitDeny = "ImplicitDeny"
+	// PolicyDecisionNotEvaluated means the decision depends on the elements which are not evaluated,
+	// such as Condition, the request is not regarded as allowed.
+	PolicyDecisionNotEvaluated = "NotEvaluated"
+)
+
+// the elements of a statement which the policy evaluator understands
+var evaluatedStatementElements = map[string]bool{
+	"Sid": true, "Effect": true, "Action": true, "NotAction": true, "Resource": true, "NotResource": true,
+}
+
+// NamedPolicyDocument is a policy document with the name of the policy it comes from.
+type NamedPolicyDocument struct {
+	PolicyName string
+	PolicyType string
+	VersionID  string
+	Document   ram.PolicyDocument
+	// Unevaluated is the elements of the statements by the index, which are not evaluated, such as Condition
+	Unevaluated map[int][]string
+}
+
+// ParsePolicyDocument parses the policy document, the elements of the statements which are not
+// evaluated are listed in Unevaluated.
+func ParsePolicyDocument(doc string) (NamedPolicyDocument, error) {
+	raw := struct {
+		Version   string
+		Statement []map[string]json.RawMessage
+	}{}
+	if err := json.Unmarshal([]byte(doc), &raw); err != nil {
+		return NamedPolicyDocument{}, fmt.Errorf("invalid policy document: %s", err)
+	}
+	parsed := NamedPolicyDocument{Document: ram.PolicyDocument{Version: raw.Version}, Unevaluated: map[int][]string{}}
+	for i, elements := range raw.Statement {
+		data, _ := json.Marshal(elements)
+		s := ram.PolicyStatement{}
+		if err := json.Unmarshal(data, &s); err != nil {
+			return NamedPolicyDocument{}, fmt.Errorf("invalid policy statement %s: %s", data, err)
+		}
+		for key := range elements {
+			if !evaluatedStatementElements[key] {
+				parsed.Unevaluated[i] = append(parsed.Unevaluated[i], key)
+			}
+		}
+		sort.Strings(parsed.Unevaluated[i])
+		parsed.Document.Statement = append(parsed.Document.Statement, s)
+	}
+	return parsed, nil
+}
+
+// MatchedStatement is a statement which matches the action and the resource of the request.
+type MatchedStatement struct {
+	PolicyName     string
+	PolicyType     string
+	VersionID      string
+	StatementIndex int
+	Statement      ram.PolicyStatement
+	Unevaluated    []string
+}
+
+// PolicyEvaluation is the result of evaluating the policies against a request.
+// Statements are the statements which lead to the decision, they are the deny statements
+// for ExplicitDeny, the allow statements for Allow, the statements with the unevaluated elements
+// for NotEvaluated and empty for ImplicitDeny.
+type PolicyEvaluation struct {
+	Action     string
+	Resource   string
+	Decision   string
+	Statements []MatchedStatement
+}
+
+// Allowed returns whether the request is allowed.
+func (e *PolicyEvaluation) Allowed() bool {
+	return e.Decision == PolicyDecisionAllow
+}
+
+// EvaluatePolicies evaluates the policies in the same way as ram:
+// an explicit deny in any policy overrides all the allows, and the request is denied
+// implicitly if no statement allows it. The statements with the elements which are not evaluated,
+// such as Condition, are treated conservatively: such a deny makes the decision NotEvaluated
+// unless another deny applies, and such an allow does not allow the request.
+func EvaluatePolicies(policies []NamedPolicyDocument, action, resource string) *PolicyEvaluation {
+	allows := []MatchedStatement{}
+	denies := []MatchedStatement{}
+	conditionalAllows := []MatchedStatement{}
+	conditionalDenies := []MatchedStatement{}
+	for _, p := range policies {
+		for i, statement := range p.Document.Statement {
+			if !StatementMatches(statement, action, resource) {
+				continue
+			}
+			matched := MatchedStatement{
+				PolicyName:     p.PolicyName,
+				PolicyType:     p.PolicyType,
+				VersionID:      p.VersionID,
+				StatementIndex: i,
+				Statement:      statement,
+				Unevaluated:    p.Unevaluated[i],
+			}
+			conditional := len(matched.Unevaluated) > 0
+			switch {
+			case strings.EqualFold(statement.Effect, "Deny") && conditional:
+				conditionalDenies = append(conditionalDenies, matched)
+			case strings.EqualFold(statement.Effect, "Deny"):
+				denies = append(denies, matched)
+			case strings.EqualFold(statement.Effect, "Allow") && conditional:
+				conditionalAllows = append(conditionalAllows, matched)
+			case strings.EqualFold(statement.Effect, "Allow"):
+				allows = append(allows, matched)
+			}
+		}
+	}
+
+	evaluation := &PolicyEvaluation{Action: action, Resource: resource}
+	switch {
+	case len(denies) > 0:
+		evaluation.Decision = PolicyDecisionExplicitDeny
+		evaluation.Statements = denies
+	case len(conditionalDenies) > 0:
+		evaluation.Decision = PolicyDecisionNotEvaluated
+		evaluation.Statements = conditionalDenies
+	case len(allows) > 0:
+		evaluation.Decision = PolicyDecisionAllow
+		evaluation.Statements = allows
+	case len(conditionalAllows) > 0:
+		evaluation.Decision = PolicyDecisionNotEvaluated
+		evaluation.Statements = conditionalAllows
+	default:
+		evaluation.Decision = PolicyDecisionImplicitDeny
+		evaluation.Statements = []MatchedStatement{}
+	}
+	return evaluation
+}
+
+// StatementMatches returns whether both the action and the resource match the statement, by Action
+// or NotAction and by Resource or NotResource. Actions are matched case-insensitively while resources
+// are case-sensitive.
+func StatementMatches(statement ram.PolicyStatement, action, resource string) bool {
+	var actionMatched bool
+	if len(statement.NotAction) > 0 {
+		actionMatched = !matchAnyPattern(statement.NotAction, action, true)
+	} else {
+		actionMatched = matchAnyPattern(statement.Action, action, true)
+	}
+	if !actionMatched {
+		return false
+	}
+	if len(statement.NotResource) > 0 {
+		return !matchAnyPattern(statement.NotResource, resource, false)
+	}
+	return matchAnyPattern(statement.Resource, resource, false)
+}
+
+func matchAnyPattern(patterns []string, value string, ignoreCase bool) bool {
+	if ignoreCase {
+		value = strings.ToLower(value)
+	}
+	for _, p := range patterns {
+		if ignoreCase {
+			p = strings.ToLower(p)
+		}
+		if MatchPolicyPattern(p, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchPolicyPattern matches the value against the pattern of the policy element,
+// '*' matches any sequence of characters including '/' and ':', '?' matches any single character.
+func MatchPolicyPattern(pattern, value string) bool {
+	p, v := 0, 0
+	star, mark := -1, 0
+	for v < len(value) {
+		if p < len(pattern) && (pattern[p] == '?' || pattern[p] == value[v]) {
+			p++
+			v++
+		} else if p < len(pattern) && pattern[p] == '*' {
+			star, mark = p, v
+			p++
+		} else if star >= 0 {
+			p = star + 1
+			mark++
+			v = mark
+		} else {
+			return false
+		}
+	}
+	for p < len(pattern) && pattern[p] == '*' {
+		p++
+	}
+	return p == len(pattern)
+}
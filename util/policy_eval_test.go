@@ -0,0 +1,174 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/aliyun/fcli/ram"
+)
+
+func (s *UtilTestSuite) TestMatchPolicyPattern() {
+	s.True(MatchPolicyPattern("*", "acs:log:*:123:project/p/logstore/l"))
+	s.True(MatchPolicyPattern("acs:log:*:123:project/p/*", "acs:log:cn-hangzhou:123:project/p/logstore/l"))
+	s.True(MatchPolicyPattern("log:Get*", "log:GetLogStore"))
+	s.True(MatchPolicyPattern("oss:?etObject", "oss:GetObject"))
+	s.False(MatchPolicyPattern("acs:log:*:123:project/p/*", "acs:log:cn-hangzhou:456:project/p/logstore/l"))
+	s.False(MatchPolicyPattern("log:Get*", "log:PostLogStoreLogs"))
+}
+
+func (s *UtilTestSuite) TestEvaluatePolicies() {
+	assert := s.Require()
+	doc := ram.PolicyDocument{}
+	err := json.Unmarshal([]byte(`{"Version": "1", "Statement": [
+		{"Effect": "Allow", "Action": "log:*", "Resource": "acs:log:*:123:project/p/*"},
+		{"Effect": "Deny", "Action": ["log:PostLogStoreLogs"], "Resource": ["acs:log:*:123:project/p/logstore/secret"]}
+	]}`), &doc)
+	assert.Nil(err)
+	policies := []NamedPolicyDocument{
+		{PolicyName: "custom", PolicyType: "Custom", Document: doc},
+		{PolicyName: "system", PolicyType: "System", Document: ram.PolicyDocument{Statement: []ram.PolicyStatement{
+			{Effect: "Allow", Action: []string{"log:postlogstorelogs"}, Resource: []string{"*"}},
+		}}},
+	}
+
+	e := EvaluatePolicies(policies, "log:PostLogStoreLogs", "acs:log:cn-hangzhou:123:project/p/logstore/l")
+	assert.True(e.Allowed())
+	assert.Equal(2, len(e.Statements))
+
+	e = EvaluatePolicies(policies, "log:PostLogStoreLogs", "acs:log:cn-hangzhou:123:project/p/logstore/secret")
+	assert.Equal(PolicyDecisionExplicitDeny, e.Decision)
+	assert.Equal(1, len(e.Statements))
+	assert.Equal("custom", e.Statements[0].PolicyName)
+	assert.Equal(1, e.Statements[0].StatementIndex)
+
+	e = EvaluatePolicies(policies[:1], "oss:GetObject", "acs:oss:*:123:bucket/object")
+	assert.Equal(PolicyDecisionImplicitDeny, e.Decision)
+	assert.False(e.Allowed())
+}
+
+func (s *UtilTestSuite) TestEvaluateUnevaluatedElements() {
+	assert := s.Require()
+	conditional, err := ParsePolicyDocument(`{"Version": "1", "Statement": [
+		{"Effect": "Allow", "Action": "log:*", "Resource": "*", "Condition": {"IpAddress": {"acs:SourceIp": "10.0.0.0/8"}}}
+	]}`)
+	assert.Nil(err)
+	assert.Equal(map[int][]string{0: {"Condition"}}, conditional.Unevaluated)
+	assert.NotEmpty(conditional.Document.Statement[0].Condition)
+
+	// the conditional allow does not allow the request
+	e := EvaluatePolicies([]NamedPolicyDocument{conditional}, "log:PostLogStoreLogs", "acs:log:*:123:project/p")
+	assert.Equal(PolicyDecisionNotEvaluated, e.Decision)
+	assert.False(e.Allowed())
+	assert.Equal([]string{"Condition"}, e.Statements[0].Unevaluated)
+
+	// the deny with NotAction and NotResource
+	deny, err := ParsePolicyDocument(`{"Version": "1", "Statement": [
+		{"Effect": "Allow", "Action": "log:*", "Resource": "*"},
+		{"Effect": "Deny", "NotAction": "log:Get*", "NotResource": "acs:log:*:123:project/public"}
+	]}`)
+	assert.Nil(err)
+	assert.Empty(deny.Unevaluated)
+	e = EvaluatePolicies([]NamedPolicyDocument{deny}, "log:PostLogStoreLogs", "acs:log:*:123:project/p")
+	assert.Equal(PolicyDecisionExplicitDeny, e.Decision)
+	assert.True(EvaluatePolicies([]NamedPolicyDocument{deny}, "log:GetLogStore", "acs:log:*:123:project/p").Allowed())
+	assert.True(EvaluatePolicies([]NamedPolicyDocument{deny}, "log:PostLogStoreLogs", "acs:log:*:123:project/public").Allowed())
+
+	// the conditional deny may apply even if another statement allows the request
+	conditionalDeny := deny
+	conditionalDeny.Document.Statement = append([]ram.PolicyStatement{}, deny.Document.Statement[0],
+		ram.PolicyStatement{Effect: "Deny", Action: []string{"log:*"}, Resource: []string{"*"}})
+	conditionalDeny.Unevaluated = map[int][]string{1: {"Condition"}}
+	e = EvaluatePolicies([]NamedPolicyDocument{conditionalDeny}, "log:GetLogStore", "acs:log:*:123:project/p")
+	assert.Equal(PolicyDecisionNotEvaluated, e.Decision)
+	assert.Equal(1, e.Statements[0].StatementIndex)
+}
+
+func (s *UtilTestSuite) TestMergePolicyStatementDocument() {
+	assert := s.Require()
+	statement := ram.PolicyStatement{Effect: "Allow", Action: []string{"fc:InvokeFunction"},
+		Resource: []string{"acs:fc:*:123:services/a/functions/f"}}
+
+	// the action is allowed on another resource, and the resource is listed in another statement
+	doc := `{"Version":"1","Statement":[
+		{"Effect":"Allow","Action":"fc:InvokeFunction","Resource":"acs:fc:*:123:services/b/functions/f"},
+		{"Effect":"Allow","Action":"fc:GetFunction","Resource":"acs:fc:*:123:services/a/functions/f"}]}`
+	covered, err := PolicyCoversStatement(doc, statement)
+	assert.Nil(err)
+	assert.False(covered)
+
+	covered, err = PolicyCoversStatement(`{"Version":"1","Statement":[
+		{"Effect":"Allow","Action":"fc:*","Resource":"acs:fc:*:123:services/a/*"}]}`, statement)
+	assert.Nil(err)
+	assert.True(covered)
+	covered, err = PolicyCoversStatement(`{"Version":"1","Statement":[
+		{"Effect":"Allow","Action":"fc:*","Resource":"*"},
+		{"Effect":"Deny","Action":"fc:InvokeFunction","Resource":"*"}]}`, statement)
+	assert.Nil(err)
+	assert.False(covered)
+	// the condition may not be met
+	covered, err = PolicyCoversStatement(`{"Version":"1","Statement":[
+		{"Effect":"Allow","Action":"fc:*","Resource":"*","Condition":{"IpAddress":{"acs:SourceIp":"10.0.0.0/8"}}}]}`,
+		statement)
+	assert.Nil(err)
+	assert.False(covered)
+
+	doc = `{"Version":"1","Statement":[
+		{"Effect":"Allow","Action":"log:*","Resource":"*","Condition":{"IpAddress":{"acs:SourceIp":"10.0.0.0/8"}}},
+		{"Effect":"Deny","NotAction":"log:Get*","NotResource":"acs:log:*:123:project/p"}]}`
+	merged, err := AppendPolicyStatement(doc, statement)
+	assert.Nil(err)
+	result := map[string]interface{}{}
+	assert.Nil(json.Unmarshal([]byte(merged), &result))
+	statements := result["Statement"].([]interface{})
+	assert.Len(statements, 3)
+	assert.Equal(map[string]interface{}{"acs:SourceIp": "10.0.0.0/8"},
+		statements[0].(map[string]interface{})["Condition"].(map[string]interface{})["IpAddress"])
+	assert.Equal("log:Get*", statements[1].(map[string]interface{})["NotAction"])
+	assert.Equal("acs:log:*:123:project/p", statements[1].(map[string]interface{})["NotResource"])
+	assert.Equal("fc:InvokeFunction", statements[2].(map[string]interface{})["Action"].([]interface{})[0])
+	assert.Equal("1", result["Version"])
+}
+
+func (s *UtilTestSuite) TestMergePolicyStatements() {
+	assert := s.Require()
+	created := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		switch r.Form.Get("Action") {
+		case "ListPolicyVersions":
+			fmt.Fprint(w, `{"PolicyVersions": {"PolicyVersion": [{"VersionId": "v1", "IsDefaultVersion": true}]}}`)
+		case "GetPolicyVersion":
+			fmt.Fprint(w, `{"PolicyVersion": {"VersionId": "v1", "PolicyDocument":
+				"{\"Version\":\"1\",\"Statement\":[{\"Effect\":\"Allow\",\"Action\":\"log:*\",\"Resource\":\"*\"}]}"}}`)
+		case "CreatePolicyVersion":
+			created = append(created, r.Form.Get("PolicyDocument"))
+			fmt.Fprint(w, `{"PolicyVersion": {"VersionId": "v2"}}`)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"Code": "InvalidAction"}`)
+		}
+	}))
+	defer server.Close()
+	client, err := ram.NewClient(server.URL, "id", "secret")
+	assert.Nil(err)
+
+	// all the statements are merged into one version, the covered one is skipped
+	merged, err := MergePolicyStatements(client, "fc-demo-policy",
+		ram.PolicyStatement{Effect: "Allow", Action: []string{"log:PostLogStoreLogs"}, Resource: []string{"*"}},
+		ram.PolicyStatement{Effect: "Allow", Action: []string{"oss:GetObject"}, Resource: []string{"acs:oss:*:123:a/*"}},
+		ram.PolicyStatement{Effect: "Allow", Action: []string{"oss:PutObject"}, Resource: []string{"acs:oss:*:123:a/*"}})
+	assert.Nil(err)
+	assert.True(merged)
+	assert.Len(created, 1)
+	doc := ram.PolicyDocument{}
+	assert.Nil(json.Unmarshal([]byte(created[0]), &doc))
+	assert.Len(doc.Statement, 3)
+
+	merged, err = MergePolicyStatements(client, "fc-demo-policy",
+		ram.PolicyStatement{Effect: "Allow", Action: []string{"log:GetLogStore"}, Resource: []string{"*"}})
+	assert.Nil(err)
+	assert.False(merged)
+	assert.Len(created, 1)
+}
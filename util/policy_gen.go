@@ -0,0 +1,178 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"github.com/aliyun/fcli/ram"
+)
+
+// the actions for fc to manage the elastic network interfaces in the vpc of the service
+var vpcNetworkInterfaceActions = []string{
+	"vpc:DescribeVSwitchAttributes",
+	"ecs:CreateNetworkInterface",
+	"ecs:DeleteNetworkInterface",
+	"ecs:DescribeNetworkInterfaces",
+	"ecs:CreateNetworkInterfacePermission",
+	"ecs:DescribeNetworkInterfacePermissions",
+	"ecs:DeleteNetworkInterfacePermission",
+}
+
+// LoggingPolicyStatement allows to write logs to the logstore, all the logstores of the project are
+// allowed if the logstore is empty.
+func LoggingPolicyStatement(uid, project, logstore string) ram.PolicyStatement {
+	if logstore == "" {
+		logstore = "*"
+	}
+	return ram.PolicyStatement{
+		Effect:   "Allow",
+		Action:   []string{"log:PostLogStoreLogs"},
+		Resource: []string{fmt.Sprintf("acs:log:*:%s:project/%s/logstore/%s", uid, project, logstore)},
+	}
+}
+
+// VPCPolicyStatement allows fc to attach the network interfaces to the vpc, the ecs actions
+// do not support the resource level authorization.
+func VPCPolicyStatement() ram.PolicyStatement {
+	return ram.PolicyStatement{
+		Effect:   "Allow",
+		Action:   append([]string{}, vpcNetworkInterfaceActions...),
+		Resource: []string{"*"},
+	}
+}
+
+// InvokeFunctionPolicyStatement allows to invoke the function of the service and its versions and aliases.
+func InvokeFunctionPolicyStatement(region, uid, service, function string) ram.PolicyStatement {
+	return ram.PolicyStatement{
+		Effect: "Allow",
+		Action: []string{"fc:InvokeFunction"},
+		Resource: []string{
+			fmt.Sprintf("acs:fc:%s:%s:services/%s/functions/%s", region, uid, service, function),
+			fmt.Sprintf("acs:fc:%s:%s:services/%s.*/functions/%s", region, uid, service, function),
+		},
+	}
+}
+
+// ServicePolicyDocument generates the minimal policy document of the service role from the log,
+// vpc and nas config of the service. The nas file systems are accessed through the vpc, so the
+// service with nas config only needs the vpc permission. It returns nil if no permission is needed.
+func ServicePolicyDocument(uid string, logConfig *fc.LogConfig, vpcConfig *fc.VPCConfig, nasConfig *fc.NASConfig) *ram.PolicyDocument {
+	statements := []ram.PolicyStatement{}
+	if logConfig != nil && logConfig.Project != nil && *logConfig.Project != "" {
+		logstore := ""
+		if logConfig.Logstore != nil {
+			logstore = *logConfig.Logstore
+		}
+		statements = append(statements, LoggingPolicyStatement(uid, *logConfig.Project, logstore))
+	}
+	hasVPC := vpcConfig != nil && vpcConfig.VPCID != nil && *vpcConfig.VPCID != ""
+	hasNAS := nasConfig != nil && len(nasConfig.MountPoints) > 0
+	if hasVPC || hasNAS {
+		statements = append(statements, VPCPolicyStatement())
+	}
+	if len(statements) == 0 {
+		return nil
+	}
+	return &ram.PolicyDocument{Version: "1", Statement: statements}
+}
+
+// TriggerPolicyDocument generates the minimal policy document of the trigger invocation role.
+// Besides invoking the function, the log trigger needs to write the job logs to its logstore.
+// It returns nil if the trigger type does not need an invocation role.
+func TriggerPolicyDocument(region, uid, service, function, triggerType string, triggerConfig interface{}) (*ram.PolicyDocument, error) {
+	if _, ok := TriggerInvocationPrincipals[triggerType]; !ok {
+		return nil, nil
+	}
+	statements := []ram.PolicyStatement{InvokeFunctionPolicyStatement(region, uid, service, function)}
+	if triggerType == fc.TRIGGER_TYPE_LOG && triggerConfig != nil {
+		logTriggerConfig, err := GetLogTriggerConfig(triggerConfig)
+		if err != nil {
+			return nil, err
+		}
+		if c := logTriggerConfig.LogConfig; c != nil && c.Project != nil && c.Logstore != nil {
+			statements = append(statements, LoggingPolicyStatement(uid, *c.Project, *c.Logstore))
+		}
+	}
+	return &ram.PolicyDocument{Version: "1", Statement: statements}, nil
+}
+
+// GetLogTriggerConfig converts the trigger config in the response to the log trigger config.
+func GetLogTriggerConfig(triggerConfig interface{}) (*fc.LogTriggerConfig, error) {
+	data, err := json.Marshal(triggerConfig)
+	if err != nil {
+		return nil, err
+	}
+	logTriggerConfig := &fc.LogTriggerConfig{}
+	err = json.Unmarshal(data, logTriggerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse log trigger config due to %v", err)
+	}
+	return logTriggerConfig, nil
+}
+
+// ApplyRolePolicy makes the custom policy have exactly the document and attaches it to the role.
+// The role is created if it does not exist, and the policy is updated as a new default version
+// if its document is different.
+func ApplyRolePolicy(cli *ram.Client, roleName, principal, policyName string, doc *ram.PolicyDocument) (string, error) {
+	var roleARN string
+	gresp, err := cli.GetRole(roleName)
+	if err == nil {
+		roleARN = gresp.Role.Arn
+		trustPolicy, err := ram.ParseAssumeRolePolicyDocument(gresp.Role.AssumeRolePolicyDocument)
+		if err != nil {
+			return roleARN, err
+		}
+		if !trustPolicy.HasServicePrincipal(principal) {
+			return roleARN, fmt.Errorf("role %s can not be assumed by %s, please use another role", roleName, principal)
+		}
+	} else if ram.IsNotFound(err) {
+		roleARN, err = CreateRole(cli, roleName, principal)
+		if err != nil {
+			return roleARN, err
+		}
+	} else {
+		return roleARN, err
+	}
+
+	b, err := json.Marshal(doc)
+	if err != nil {
+		return roleARN, err
+	}
+	_, err = cli.GetPolicy(policyName, "Custom")
+	if ram.IsNotFound(err) {
+		desc := fmt.Sprintf("create the policy %s", policyName)
+		if _, err := cli.CreatePolicy(policyName, string(b), desc); err != nil {
+			return roleARN, fmt.Errorf("failed to create policy %s due to %s", policyName, err)
+		}
+	} else if err != nil {
+		return roleARN, err
+	} else {
+		version, err := GetDefaultPolicyVersion(cli, policyName, "Custom")
+		if err != nil {
+			return roleARN, err
+		}
+		current := &ram.PolicyDocument{}
+		if json.Unmarshal([]byte(version.PolicyDocument), current) != nil || !reflect.DeepEqual(current, doc) {
+			if err := PrunePolicyVersions(cli, policyName, MaxPolicyVersions-1); err != nil {
+				return roleARN, err
+			}
+			if _, err := cli.CreatePolicyVersion(policyName, string(b), "true"); err != nil {
+				return roleARN, fmt.Errorf("failed to create policy version of %s due to %s", policyName, err)
+			}
+		}
+	}
+
+	policies, err := cli.ListPoliciesForRole(roleName)
+	if err != nil {
+		return roleARN, err
+	}
+	for _, v := range policies.Policies.Policy {
+		if strings.EqualFold(v.PolicyName, policyName) && v.PolicyType == "Custom" {
+			return roleARN, nil
+		}
+	}
+	return roleARN, AttachPolicy(cli, policyName, roleName)
+}
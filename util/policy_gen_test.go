@@ -0,0 +1,27 @@
+package util
+
+import (
+	"github.com/aliyun/fc-go-sdk"
+)
+
+func (s *UtilTestSuite) TestServicePolicyDocument() {
+	assert := s.Require()
+	assert.Nil(ServicePolicyDocument("123", nil, nil, nil))
+
+	project, logstore := "p", "l"
+	doc := ServicePolicyDocument("123", &fc.LogConfig{Project: &project, Logstore: &logstore}, nil,
+		&fc.NASConfig{MountPoints: []fc.NASMountConfig{{ServerAddr: "xxx.nas.aliyuncs.com:/", MountDir: "/mnt"}}})
+	assert.Equal(2, len(doc.Statement))
+	assert.Equal([]string{"acs:log:*:123:project/p/logstore/l"}, []string(doc.Statement[0].Resource))
+	assert.Contains(doc.Statement[1].Action, "ecs:CreateNetworkInterface")
+
+	doc, err := TriggerPolicyDocument("cn-hangzhou", "123", "svc", "fn", fc.TRIGGER_TYPE_TIMER, nil)
+	assert.Nil(err)
+	assert.Nil(doc)
+	doc, err = TriggerPolicyDocument("cn-hangzhou", "123", "svc", "fn", fc.TRIGGER_TYPE_OSS, nil)
+	assert.Nil(err)
+	assert.Equal([]string{
+		"acs:fc:cn-hangzhou:123:services/svc/functions/fn",
+		"acs:fc:cn-hangzhou:123:services/svc.*/functions/fn",
+	}, []string(doc.Statement[0].Resource))
+}
@@ -0,0 +1,91 @@
+package util
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v2"
+)
+
+// ProjectConfigFile is the name of the project config file, which is looked up in the current
+// directory and its parents.
+const ProjectConfigFile = ".fcli.yaml"
+
+// ProjectConfig is the non-secret defaults of the project, which are merged over the global config.
+// The endpoint is built from the account id and the region if it is not specified.
+type ProjectConfig struct {
+	Endpoint    string `yaml:"endpoint,omitempty"`
+	AccountID   string `yaml:"account_id,omitempty"`
+	Region      string `yaml:"region,omitempty"`
+	ServiceName string `yaml:"service_name,omitempty"`
+	Qualifier   string `yaml:"qualifier,omitempty"`
+}
+
+// FindProjectConfig returns the path of the project config file in the dir or its nearest parent,
+// it returns empty if there is none.
+func FindProjectConfig(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		p := filepath.Join(dir, ProjectConfigFile)
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			return p
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// LoadProjectConfig loads the project config file, the unknown keys such as the secrets are rejected
+// since the file is usually committed to the repository.
+func LoadProjectConfig(path string) (*ProjectConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	p := &ProjectConfig{}
+	if err := yaml.UnmarshalStrict(data, p); err != nil {
+		return nil, fmt.Errorf("invalid project config %s, only endpoint, account_id, region, "+
+			"service_name and qualifier are allowed: %v", path, err)
+	}
+	return p, nil
+}
+
+// Apply merges the project config over the config, and returns the config keys which are changed.
+func (p *ProjectConfig) Apply(cfg *GlobalConfig) []string {
+	keys := []string{}
+	endpoint := p.Endpoint
+	if endpoint == "" && (p.AccountID != "" || p.Region != "") {
+		accountID, region := p.AccountID, p.Region
+		if accountID == "" {
+			accountID, _ = GetUIDFromEndpoint(cfg.Endpoint)
+		}
+		if region == "" && cfg.Endpoint != "" {
+			region = GetRegionNoForEndpoint(cfg.Endpoint)
+		}
+		if accountID != "" && region != "" {
+			endpoint = fmt.Sprintf(EndpointFmt, accountID, region)
+		}
+	}
+	if endpoint != "" {
+		cfg.Endpoint = endpoint
+		cfg.SLSEndpoint = SLSEndpointForEndpoint(endpoint)
+		keys = append(keys, "endpoint", "sls_endpoint")
+	}
+	if p.ServiceName != "" {
+		cfg.ServiceName = p.ServiceName
+		keys = append(keys, "service_name")
+	}
+	if p.Qualifier != "" {
+		cfg.Qualifier = p.Qualifier
+		keys = append(keys, "qualifier")
+	}
+	return keys
+}
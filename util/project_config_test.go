@@ -0,0 +1,35 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+func (s *UtilTestSuite) TestProjectConfig() {
+	assert := s.Require()
+	root, err := ioutil.TempDir("", "fcli-project")
+	assert.Nil(err)
+	defer os.RemoveAll(root)
+	sub := filepath.Join(root, "a", "b")
+	assert.Nil(os.MkdirAll(sub, 0755))
+	assert.Equal("", FindProjectConfig(sub))
+
+	p := filepath.Join(root, ProjectConfigFile)
+	assert.Nil(ioutil.WriteFile(p, []byte("region: cn-shanghai\nservice_name: demo\n"), 0644))
+	assert.Equal(p, FindProjectConfig(sub))
+
+	projectConfig, err := LoadProjectConfig(p)
+	assert.Nil(err)
+	cfg := NewGlobalConfig()
+	cfg.Endpoint = "https://123.cn-hangzhou.fc.aliyuncs.com"
+	keys := projectConfig.Apply(cfg)
+	assert.Equal([]string{"endpoint", "sls_endpoint", "service_name"}, keys)
+	assert.Equal("https://123.cn-shanghai.fc.aliyuncs.com", cfg.Endpoint)
+	assert.Equal("cn-shanghai.log.aliyuncs.com", cfg.SLSEndpoint)
+	assert.Equal("demo", cfg.ServiceName)
+
+	assert.Nil(ioutil.WriteFile(p, []byte("access_key_secret: secret\n"), 0644))
+	_, err = LoadProjectConfig(p)
+	assert.NotNil(err)
+}
@@ -0,0 +1,276 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RegionCatalogFile is the name of the cached region catalog in the config directory.
+const RegionCatalogFile = "regions.json"
+
+// the network types of the fc endpoint
+const (
+	NetworkPublic   = "public"
+	NetworkInternal = "internal"
+	NetworkVPC      = "vpc"
+)
+
+const (
+	// InternalEndpointFmt fc internal endpoint fmt
+	InternalEndpointFmt = `https://%s.%s-internal.fc.aliyuncs.com`
+
+	// VPCEndpointFmt fc vpc endpoint fmt
+	VPCEndpointFmt = `https://%s.%s-vpc.fc.aliyuncs.com`
+
+	// IntranetLogEndpointFmt loghub intranet endpoint fmt
+	IntranetLogEndpointFmt = `%s-intranet.log.aliyuncs.com`
+)
+
+// Region is a region of the catalog and the services available in it.
+type Region struct {
+	ID  string `json:"id"`
+	FC  bool   `json:"fc"`
+	Log bool   `json:"log"`
+}
+
+// RegionCatalog is the list of the known regions.
+type RegionCatalog struct {
+	Regions []Region `json:"regions"`
+}
+
+// bundledRegionCatalog is the fallback when there is no cached catalog.
+const bundledRegionCatalog = `{
+  "regions": [
+    {"id": "cn-beijing", "fc": true, "log": true},
+    {"id": "cn-qingdao", "fc": true, "log": true},
+    {"id": "cn-zhangjiakou", "fc": true, "log": true},
+    {"id": "cn-hangzhou", "fc": true, "log": true},
+    {"id": "cn-shanghai", "fc": true, "log": true},
+    {"id": "cn-shenzhen", "fc": true, "log": true},
+    {"id": "cn-hongkong", "fc": true, "log": true},
+    {"id": "cn-huhehaote", "fc": true, "log": true},
+    {"id": "ap-southeast-1", "fc": true, "log": true},
+    {"id": "ap-southeast-2", "fc": true, "log": true},
+    {"id": "ap-northeast-1", "fc": true, "log": true},
+    {"id": "us-west-1", "fc": true, "log": true},
+    {"id": "us-east-1", "fc": true, "log": true},
+    {"id": "eu-central-1", "fc": true, "log": true},
+    {"id": "ap-south-1", "fc": true, "log": true},
+    {"id": "cn-chengdu", "fc": false, "log": true},
+    {"id": "ap-southeast-3", "fc": false, "log": true},
+    {"id": "ap-southeast-5", "fc": false, "log": true},
+    {"id": "me-east-1", "fc": false, "log": true},
+    {"id": "eu-west-1", "fc": false, "log": true}
+  ]
+}`
+
+var (
+	regionCatalogMu sync.RWMutex
+	regionCatalog   *RegionCatalog
+)
+
+func init() {
+	c, err := ParseRegionCatalog([]byte(bundledRegionCatalog))
+	if err != nil {
+		panic(err)
+	}
+	regionCatalog = c
+}
+
+// ParseRegionCatalog parses and validates the json region catalog.
+func ParseRegionCatalog(data []byte) (*RegionCatalog, error) {
+	c := &RegionCatalog{}
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("invalid region catalog: %v", err)
+	}
+	fc := 0
+	for _, r := range c.Regions {
+		if !regionIDPattern.MatchString(r.ID) {
+			return nil, fmt.Errorf("invalid region catalog: bad region id %q", r.ID)
+		}
+		if r.FC {
+			fc++
+		}
+	}
+	if fc == 0 {
+		return nil, fmt.Errorf("invalid region catalog: no function compute regions")
+	}
+	return c, nil
+}
+
+// CurrentRegionCatalog returns the region catalog in use.
+func CurrentRegionCatalog() *RegionCatalog {
+	regionCatalogMu.RLock()
+	defer regionCatalogMu.RUnlock()
+	return regionCatalog
+}
+
+// SetRegionCatalog replaces the region catalog in use.
+func SetRegionCatalog(c *RegionCatalog) {
+	regionCatalogMu.Lock()
+	defer regionCatalogMu.Unlock()
+	regionCatalog = c
+}
+
+// LoadRegionCatalogFile uses the cached catalog file if it exists and is valid,
+// otherwise the bundled catalog is kept.
+func LoadRegionCatalogFile(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	c, err := ParseRegionCatalog(data)
+	if err != nil {
+		return fmt.Errorf("%s: %v", path, err)
+	}
+	SetRegionCatalog(c)
+	return nil
+}
+
+// RefreshRegionCatalog fetches the catalog from the http(s) url or the local file, caches it
+// to the path and uses it.
+func RefreshRegionCatalog(source, path string) (*RegionCatalog, error) {
+	var data []byte
+	var err error
+	if strings.HasPrefix(source, "http://") || strings.HasPrefix(source, "https://") {
+		data, err = fetchRegionCatalog(source)
+	} else {
+		data, err = ioutil.ReadFile(source)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c, err := ParseRegionCatalog(data)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, err
+	}
+	if err := ioutil.WriteFile(path, data, 0644); err != nil {
+		return nil, err
+	}
+	SetRegionCatalog(c)
+	return c, nil
+}
+
+func fetchRegionCatalog(url string) ([]byte, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to fetch the region catalog from %s: %s", url, resp.Status)
+	}
+	return ioutil.ReadAll(resp.Body)
+}
+
+// FCRegions returns the ids of the regions which have function compute.
+func (c *RegionCatalog) FCRegions() []string {
+	regions := []string{}
+	for _, r := range c.Regions {
+		if r.FC {
+			regions = append(regions, r.ID)
+		}
+	}
+	return regions
+}
+
+// LogRegions returns the ids of the regions which have log service.
+func (c *RegionCatalog) LogRegions() []string {
+	regions := []string{}
+	for _, r := range c.Regions {
+		if r.Log {
+			regions = append(regions, r.ID)
+		}
+	}
+	return regions
+}
+
+var (
+	regionIDPattern = regexp.MustCompile(`^[a-z]+(-[a-z0-9]+)+$`)
+	// <account>.<region>[-internal|-vpc].fc.aliyuncs.com
+	fcEndpointPattern = regexp.MustCompile(
+		`^(?:https?://)?([^./]+)\.([a-z0-9-]+?)(-internal|-vpc)?\.fc\.aliyuncs\.com(?::\d+)?/?$`)
+)
+
+// EndpointInfo is the parts of the fc endpoint.
+type EndpointInfo struct {
+	AccountID string
+	Region    string
+	Network   string
+}
+
+// ParseEndpoint parses the standard fc endpoint of the public, internal or vpc network.
+// The region is not checked against the catalog, so that the new regions work.
+func ParseEndpoint(endpoint string) (*EndpointInfo, error) {
+	m := fcEndpointPattern.FindStringSubmatch(strings.TrimSpace(endpoint))
+	if m == nil {
+		return nil, fmt.Errorf("invalid fc endpoint: %s, expect https://{accountID}.{region}[-internal|-vpc].fc.aliyuncs.com", endpoint)
+	}
+	info := &EndpointInfo{AccountID: m[1], Region: m[2], Network: NetworkPublic}
+	switch m[3] {
+	case "-internal":
+		info.Network = NetworkInternal
+	case "-vpc":
+		info.Network = NetworkVPC
+	}
+	return info, nil
+}
+
+// FCEndpoint returns the fc endpoint of the account in the region of the network.
+func FCEndpoint(accountID, region, network string) string {
+	switch network {
+	case NetworkInternal:
+		return fmt.Sprintf(InternalEndpointFmt, accountID, region)
+	case NetworkVPC:
+		return fmt.Sprintf(VPCEndpointFmt, accountID, region)
+	default:
+		return fmt.Sprintf(EndpointFmt, accountID, region)
+	}
+}
+
+// SLSEndpoint returns the log service endpoint of the region, the intranet one is used for the
+// internal and vpc networks.
+func SLSEndpoint(region, network string) string {
+	if network == NetworkInternal || network == NetworkVPC {
+		return fmt.Sprintf(IntranetLogEndpointFmt, region)
+	}
+	return fmt.Sprintf(LogEndpointFmt, region)
+}
+
+// SLSEndpointForEndpoint derives the log service endpoint from the fc endpoint. The region of the
+// custom endpoints is looked up in the catalog, it returns empty if the region is unknown.
+func SLSEndpointForEndpoint(endpoint string) string {
+	if info, err := ParseEndpoint(endpoint); err == nil {
+		return SLSEndpoint(info.Region, info.Network)
+	}
+	if region := GetRegionNoForSLSEndpoint(endpoint); region != "" {
+		return SLSEndpoint(region, NetworkPublic)
+	}
+	return ""
+}
+
+// matchRegion returns the longest region contained in the endpoint, or empty if there is none.
+func matchRegion(endpoint string, regions []string) string {
+	match := ""
+	for _, region := range regions {
+		if strings.Contains(endpoint, region) && len(region) > len(match) {
+			match = region
+		}
+	}
+	return match
+}
@@ -0,0 +1,93 @@
+package util
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+)
+
+func (s *UtilTestSuite) TestParseEndpoint() {
+	assert := s.Require()
+	info, err := ParseEndpoint("https://123.cn-hangzhou.fc.aliyuncs.com")
+	assert.Nil(err)
+	assert.Equal(EndpointInfo{AccountID: "123", Region: "cn-hangzhou", Network: NetworkPublic}, *info)
+
+	info, err = ParseEndpoint("https://123.cn-shanghai-internal.fc.aliyuncs.com")
+	assert.Nil(err)
+	assert.Equal(EndpointInfo{AccountID: "123", Region: "cn-shanghai", Network: NetworkInternal}, *info)
+
+	// the regions which are not in the catalog are parsed too
+	info, err = ParseEndpoint("123.ap-northeast-2-vpc.fc.aliyuncs.com")
+	assert.Nil(err)
+	assert.Equal(EndpointInfo{AccountID: "123", Region: "ap-northeast-2", Network: NetworkVPC}, *info)
+
+	_, err = ParseEndpoint("https://fc.example.com")
+	assert.NotNil(err)
+}
+
+func (s *UtilTestSuite) TestSLSEndpointForEndpoint() {
+	assert := s.Require()
+	assert.Equal("cn-hangzhou.log.aliyuncs.com", SLSEndpointForEndpoint("https://123.cn-hangzhou.fc.aliyuncs.com"))
+	assert.Equal("cn-hangzhou-intranet.log.aliyuncs.com",
+		SLSEndpointForEndpoint("https://123.cn-hangzhou-internal.fc.aliyuncs.com"))
+	assert.Equal("eu-west-1-intranet.log.aliyuncs.com",
+		SLSEndpointForEndpoint("https://123.eu-west-1-vpc.fc.aliyuncs.com"))
+	// the region of the custom endpoint is looked up in the catalog
+	assert.Equal("cn-shenzhen.log.aliyuncs.com", SLSEndpointForEndpoint("https://fc-cn-shenzhen.example.com"))
+
+	assert.Equal("cn-hangzhou", GetRegionNoForEndpoint("https://123.cn-hangzhou-internal.fc.aliyuncs.com"))
+	assert.Equal("ap-southeast-1", GetRegionNoForEndpoint("https://fc.ap-southeast-1.example.com"))
+	// the unknown endpoints have no region instead of a guessed one
+	assert.Equal("", GetRegionNoForEndpoint("http://127.0.0.1:8001"))
+	assert.Equal("", SLSEndpointForEndpoint("https://fc.example.com"))
+	_, err := GetRegionForEndpoint("http://127.0.0.1:8001")
+	assert.Equal(ErrorClassValidation, ClassOf(err))
+	_, err = BuildEndpoint("http://127.0.0.1:8001", "123", "")
+	assert.NotNil(err)
+	assert.Contains(ErrorHint(err), "--region")
+
+	endpoint, err := BuildEndpoint("https://123.cn-hangzhou-vpc.fc.aliyuncs.com", "", "cn-beijing")
+	assert.Nil(err)
+	assert.Equal("https://123.cn-beijing-vpc.fc.aliyuncs.com", endpoint)
+}
+
+func (s *UtilTestSuite) TestRegionCatalog() {
+	assert := s.Require()
+	bundled := CurrentRegionCatalog()
+	defer SetRegionCatalog(bundled)
+	assert.Contains(GetRegions(), "cn-hangzhou")
+	assert.NotContains(GetRegions(), "eu-west-1")
+	assert.Contains(bundled.LogRegions(), "eu-west-1")
+
+	_, err := ParseRegionCatalog([]byte(`{"regions": [{"id": "cn-hangzhou", "log": true}]}`))
+	assert.NotNil(err)
+	_, err = ParseRegionCatalog([]byte(`{"regions": [{"id": "not a region", "fc": true}]}`))
+	assert.NotNil(err)
+
+	dir, err := ioutil.TempDir("", "fcli-regions")
+	assert.Nil(err)
+	defer os.RemoveAll(dir)
+	cache := filepath.Join(dir, ".fcli", RegionCatalogFile)
+	assert.Nil(LoadRegionCatalogFile(cache))
+	assert.Equal(bundled, CurrentRegionCatalog())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"regions": [{"id": "cn-hangzhou", "fc": true, "log": true},
+			{"id": "cn-wulanchabu", "fc": true, "log": true}]}`))
+	}))
+	defer server.Close()
+	c, err := RefreshRegionCatalog(server.URL, cache)
+	assert.Nil(err)
+	assert.Equal([]string{"cn-hangzhou", "cn-wulanchabu"}, c.FCRegions())
+	assert.Nil(ValidateRegion("cn-wulanchabu"))
+
+	SetRegionCatalog(bundled)
+	assert.NotNil(ValidateRegion("cn-wulanchabu"))
+	assert.Nil(LoadRegionCatalogFile(cache))
+	assert.Nil(ValidateRegion("cn-wulanchabu"))
+
+	assert.Nil(ioutil.WriteFile(cache, []byte("{"), 0644))
+	assert.NotNil(LoadRegionCatalogFile(cache))
+}
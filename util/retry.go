@@ -0,0 +1,182 @@
+package util
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/aliyun/fc-go-sdk"
+)
+
+const (
+	// DefaultMaxAttempts is the default max attempts of a fc api call, including the first one.
+	DefaultMaxAttempts = 3
+
+	defaultRetryBaseDelay = 200 * time.Millisecond
+	defaultRetryMaxDelay  = 20 * time.Second
+)
+
+// RetryAfterError is implemented by the errors which carry the Retry-After of the response,
+// the server asks to send the request again after the duration.
+type RetryAfterError interface {
+	RetryAfter() time.Duration
+}
+
+// Retryer retries the api calls on throttling, 5xx and transport errors with jittered
+// exponential backoff. The non-idempotent calls are retried only on throttling, since the
+// throttled requests are not processed.
+type Retryer struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	// Logf logs the retries, it is nil if the retries are not logged.
+	Logf func(format string, args ...interface{})
+
+	sleep  func(time.Duration)
+	jitter func() float64
+}
+
+// NewRetryer creates the retryer of the config, the retries are logged to the debug output in debug mode.
+func NewRetryer(cfg *GlobalConfig) *Retryer {
+	r := &Retryer{
+		MaxAttempts: cfg.MaxAttempts,
+		BaseDelay:   defaultRetryBaseDelay,
+		MaxDelay:    defaultRetryMaxDelay,
+		sleep:       time.Sleep,
+		jitter:      rand.Float64,
+	}
+	if r.MaxAttempts <= 0 {
+		r.MaxAttempts = DefaultMaxAttempts
+	}
+	if cfg.Debug {
+		r.Logf = func(format string, args ...interface{}) {
+			fmt.Fprintf(debugOutput(), format+"\n", args...)
+		}
+	}
+	return r
+}
+
+// Do calls fn until it succeeds, the error is not retryable or the max attempts are reached,
+// and returns the last error.
+func (r *Retryer) Do(op string, idempotent bool, fn func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= r.MaxAttempts || !IsRetryableError(err, idempotent) {
+			return err
+		}
+		delay := r.delay(attempt, err)
+		if r.Logf != nil {
+			r.Logf("Retry %s in %v (attempt %d/%d): %v", op, delay, attempt+1, r.MaxAttempts, oneLine(err))
+		}
+		r.sleep(delay)
+	}
+}
+
+// delay returns the Retry-After of the error if any, otherwise the full jitter backoff of the attempt.
+func (r *Retryer) delay(attempt int, err error) time.Duration {
+	if e, ok := err.(RetryAfterError); ok && e.RetryAfter() > 0 {
+		if d := e.RetryAfter(); d < r.MaxDelay {
+			return d
+		}
+		return r.MaxDelay
+	}
+	backoff := float64(r.BaseDelay) * math.Exp2(float64(attempt-1))
+	if backoff > float64(r.MaxDelay) {
+		backoff = float64(r.MaxDelay)
+	}
+	return time.Duration(r.jitter() * backoff)
+}
+
+// IsThrottlingError returns whether the error is the throttling of the fc api.
+func IsThrottlingError(err error) bool {
+	e, ok := serviceError(err)
+	if !ok {
+		return false
+	}
+	return e.HTTPStatus == 429 || strings.Contains(e.ErrorCode, "Throttl")
+}
+
+// IsRetryableError returns whether the call can be retried on the error, only the throttling
+// and the errors with Retry-After are retryable for the non-idempotent calls.
+func IsRetryableError(err error, idempotent bool) bool {
+	if IsThrottlingError(err) {
+		return true
+	}
+	if e, ok := err.(RetryAfterError); ok && e.RetryAfter() > 0 {
+		return true
+	}
+	if !idempotent {
+		return false
+	}
+	if e, ok := serviceError(err); ok {
+		return e.HTTPStatus >= 500
+	}
+	return isTransportError(err)
+}
+
+// retryAfterError is the error of the response with Retry-After, which is recorded by the
+// instrumented transport since the sdk does not keep the headers of the failed responses.
+type retryAfterError struct {
+	error
+	retryAfter time.Duration
+}
+
+func (e *retryAfterError) RetryAfter() time.Duration {
+	return e.retryAfter
+}
+
+// withRetryAfter attaches the Retry-After of the response to the fc service error.
+func withRetryAfter(err error) error {
+	e, ok := serviceError(err)
+	if !ok || e.RequestID == "" {
+		return err
+	}
+	if d, ok := takeRetryAfter(e.RequestID); ok {
+		return &retryAfterError{error: err, retryAfter: d}
+	}
+	return err
+}
+
+// withoutRetryAfter returns the original error of withRetryAfter.
+func withoutRetryAfter(err error) error {
+	if e, ok := err.(*retryAfterError); ok {
+		return e.error
+	}
+	return err
+}
+
+func serviceError(err error) (*fc.ServiceError, bool) {
+	switch e := err.(type) {
+	case *retryAfterError:
+		return serviceError(e.error)
+	case fc.ServiceError:
+		return &e, true
+	case *fc.ServiceError:
+		return e, e != nil
+	}
+	return nil, false
+}
+
+// isTransportError returns whether the error is returned by the http transport, the other errors
+// are not retried even if their messages look like the network failures.
+func isTransportError(err error) bool {
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		return true
+	}
+	switch e := err.(type) {
+	case *retryAfterError:
+		return isTransportError(e.error)
+	case *url.Error, net.Error:
+		return true
+	}
+	return false
+}
+
+func oneLine(err error) string {
+	return strings.Join(strings.Fields(err.Error()), " ")
+}
@@ -0,0 +1,169 @@
+package util
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	"github.com/aliyun/fc-go-sdk"
+)
+
+type retryAfterTestError struct {
+	fc.ServiceError
+	after time.Duration
+}
+
+func (e retryAfterTestError) RetryAfter() time.Duration {
+	return e.after
+}
+
+func newTestRetryer(maxAttempts int, delays *[]time.Duration) *Retryer {
+	r := NewRetryer(&GlobalConfig{MaxAttempts: maxAttempts})
+	r.sleep = func(d time.Duration) { *delays = append(*delays, d) }
+	r.jitter = func() float64 { return 1 }
+	return r
+}
+
+func (s *UtilTestSuite) TestRetryableError() {
+	assert := s.Require()
+	throttled := fc.ServiceError{HTTPStatus: 429, ErrorCode: "ResourceThrottled"}
+	unavailable := &fc.ServiceError{HTTPStatus: 503, ErrorCode: "ServiceUnavailable"}
+	notFound := fc.ServiceError{HTTPStatus: 404, ErrorCode: "ServiceNotFound"}
+	network := &url.Error{Op: "Get", URL: "https://123.cn-hangzhou.fc.aliyuncs.com", Err: errors.New("connection reset by peer")}
+
+	assert.True(IsRetryableError(throttled, true))
+	assert.True(IsRetryableError(throttled, false))
+	assert.True(IsRetryableError(unavailable, true))
+	assert.False(IsRetryableError(unavailable, false))
+	assert.True(IsRetryableError(network, true))
+	assert.False(IsRetryableError(network, false))
+	assert.False(IsRetryableError(notFound, true))
+	assert.False(IsRetryableError(errors.New("invalid input"), true))
+	assert.True(IsRetryableError(io.ErrUnexpectedEOF, true))
+	// the errors are classified by the type, not by the message
+	assert.False(IsRetryableError(errors.New("unexpected EOF in the template"), true))
+	assert.False(IsRetryableError(errors.New("invalid timeout: -1"), true))
+}
+
+func (s *UtilTestSuite) TestRetryer() {
+	assert := s.Require()
+	delays := []time.Duration{}
+	r := newTestRetryer(0, &delays)
+	assert.Equal(DefaultMaxAttempts, r.MaxAttempts)
+
+	calls := 0
+	err := r.Do("GetService", true, func() error {
+		calls++
+		if calls < 3 {
+			return fc.ServiceError{HTTPStatus: 500}
+		}
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal(3, calls)
+	assert.Equal([]time.Duration{200 * time.Millisecond, 400 * time.Millisecond}, delays)
+
+	// the last error is returned when the attempts are exhausted
+	calls, delays = 0, delays[:0]
+	err = r.Do("GetService", true, func() error {
+		calls++
+		return fc.ServiceError{HTTPStatus: 503, ErrorCode: "ServiceUnavailable"}
+	})
+	assert.Equal("ServiceUnavailable", err.(fc.ServiceError).ErrorCode)
+	assert.Equal(3, calls)
+
+	// the create calls are not retried on 5xx
+	calls = 0
+	err = r.Do("CreateService", false, func() error {
+		calls++
+		return fc.ServiceError{HTTPStatus: 500}
+	})
+	assert.NotNil(err)
+	assert.Equal(1, calls)
+
+	// the Retry-After is honored
+	calls, delays = 0, delays[:0]
+	err = r.Do("CreateService", false, func() error {
+		calls++
+		if calls == 1 {
+			return retryAfterTestError{fc.ServiceError{HTTPStatus: 429}, 5 * time.Second}
+		}
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal([]time.Duration{5 * time.Second}, delays)
+
+	// the retries are disabled by one attempt
+	calls = 0
+	r = newTestRetryer(1, &delays)
+	r.Do("GetService", true, func() error {
+		calls++
+		return fc.ServiceError{HTTPStatus: 429}
+	})
+	assert.Equal(1, calls)
+}
+
+func (s *UtilTestSuite) TestRetryAfterOfResponse() {
+	assert := s.Require()
+	now := time.Date(2018, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(3*time.Second, parseRetryAfter("3", now))
+	assert.Equal(time.Minute, parseRetryAfter(now.Add(time.Minute).Format(http.TimeFormat), now))
+	assert.Equal(time.Duration(0), parseRetryAfter("later", now))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Fc-Request-Id", "req-1")
+		w.Header().Set("Retry-After", "2")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	client := &http.Client{Transport: InstrumentRoundTripper(&http.Transport{})}
+	_, err := client.Get(server.URL)
+	assert.Nil(err)
+
+	// the create call is retried after the Retry-After of the response
+	cause := fc.ServiceError{HTTPStatus: 503, ErrorCode: "ServiceUnavailable", RequestID: "req-1"}
+	err = withRetryAfter(cause)
+	e, ok := err.(RetryAfterError)
+	assert.True(ok)
+	assert.Equal(2*time.Second, e.RetryAfter())
+	assert.True(IsRetryableError(err, false))
+	assert.Equal(cause, withoutRetryAfter(err))
+	// the Retry-After is used once
+	assert.Equal(cause, withRetryAfter(cause))
+}
+
+func (s *UtilTestSuite) TestRetryDeleteAndIfMatch() {
+	assert := s.Require()
+	delays := []time.Duration{}
+	c := NewRetryFClient(nil, newTestRetryer(3, &delays))
+	unavailable := fc.ServiceError{HTTPStatus: 503, ErrorCode: "ServiceUnavailable"}
+	notFound := fc.ServiceError{HTTPStatus: 404, ErrorCode: "ServiceNotFound"}
+
+	// the lost response of the deleted resource
+	errs := []error{unavailable, notFound}
+	err := c.doDelete("DeleteService", func(*fc.Client) error {
+		err := errs[0]
+		errs = errs[1:]
+		return err
+	})
+	assert.Nil(err)
+
+	// the resource is not found by the first attempt
+	err = c.doDelete("DeleteService", func(*fc.Client) error { return notFound })
+	assert.Equal(notFound, err)
+
+	// the update with If-Match is not retried on the transport errors
+	calls := 0
+	network := &url.Error{Op: "Put", URL: "https://123.cn-hangzhou.fc.aliyuncs.com", Err: errors.New("connection reset by peer")}
+	etag := "etag"
+	err = c.do("UpdateTrigger", withoutIfMatch(&etag), func(*fc.Client) error {
+		calls++
+		return network
+	})
+	assert.Equal(network, err)
+	assert.Equal(1, calls)
+	assert.True(withoutIfMatch(nil))
+}
@@ -0,0 +1,152 @@
+package util
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// ConfigPassphraseEnv is the environment variable of the passphrase to encrypt and decrypt
+	// the secrets in the config file, which is used in CI instead of prompting.
+	ConfigPassphraseEnv = "FCLI_CONFIG_PASSPHRASE"
+
+	encryptedSecretPrefix = "fcli-enc:v1:"
+	secretSaltSize        = 16
+	secretKeyIterations   = 100000
+)
+
+// IsEncryptedSecret returns whether the value in the config file is encrypted.
+func IsEncryptedSecret(value string) bool {
+	return strings.HasPrefix(value, encryptedSecretPrefix)
+}
+
+// EncryptSecret encrypts the value by AES-GCM with the key derived from the passphrase,
+// the salt and the nonce are stored with the cipher text.
+func EncryptSecret(value, passphrase string) (string, error) {
+	if value == "" || IsEncryptedSecret(value) {
+		return value, nil
+	}
+	salt := make([]byte, secretSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	gcm, err := newSecretCipher(passphrase, salt)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := append(salt, nonce...)
+	data = gcm.Seal(data, nonce, []byte(value), nil)
+	return encryptedSecretPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// DecryptSecret decrypts the value encrypted by EncryptSecret, the plain value is returned as it is.
+func DecryptSecret(value, passphrase string) (string, error) {
+	if !IsEncryptedSecret(value) {
+		return value, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(value, encryptedSecretPrefix))
+	if err != nil || len(data) < secretSaltSize {
+		return "", fmt.Errorf("invalid encrypted secret")
+	}
+	gcm, err := newSecretCipher(passphrase, data[:secretSaltSize])
+	if err != nil {
+		return "", err
+	}
+	data = data[secretSaltSize:]
+	if len(data) < gcm.NonceSize() {
+		return "", fmt.Errorf("invalid encrypted secret")
+	}
+	plain, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to decrypt the secret, the passphrase may be wrong")
+	}
+	return string(plain), nil
+}
+
+func newSecretCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	if passphrase == "" {
+		return nil, fmt.Errorf("the passphrase is empty")
+	}
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), salt, secretKeyIterations, 32))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 derives the key from the password as PBKDF2 with HMAC-SHA256 in RFC 8018.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	hashLen := prf.Size()
+	blocks := (keyLen + hashLen - 1) / hashLen
+	key := make([]byte, 0, blocks*hashLen)
+	index := make([]byte, 4)
+	u := make([]byte, hashLen)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		binary.BigEndian.PutUint32(index, uint32(block))
+		prf.Write(index)
+		key = prf.Sum(key)
+		t := key[len(key)-hashLen:]
+		copy(u, t)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range u {
+				t[j] ^= u[j]
+			}
+		}
+	}
+	return key[:keyLen]
+}
+
+// HasEncryptedSecrets returns whether any secret of the config is encrypted.
+func (cfg *GlobalConfig) HasEncryptedSecrets() bool {
+	return IsEncryptedSecret(cfg.AccessKeySecret) || IsEncryptedSecret(cfg.SecurityToken)
+}
+
+// DecryptSecrets decrypts the access key secret and the security token of the config in place.
+func (cfg *GlobalConfig) DecryptSecrets(passphrase string) error {
+	secret, err := DecryptSecret(cfg.AccessKeySecret, passphrase)
+	if err != nil {
+		return err
+	}
+	token, err := DecryptSecret(cfg.SecurityToken, passphrase)
+	if err != nil {
+		return err
+	}
+	cfg.AccessKeySecret, cfg.SecurityToken = secret, token
+	return nil
+}
+
+// MarshalConfig marshals the config to yaml, the access key secret and the security token
+// are encrypted if the passphrase is not empty.
+func MarshalConfig(cfg *GlobalConfig, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return yaml.Marshal(cfg)
+	}
+	encrypted := *cfg
+	var err error
+	if encrypted.AccessKeySecret, err = EncryptSecret(cfg.AccessKeySecret, passphrase); err != nil {
+		return nil, err
+	}
+	if encrypted.SecurityToken, err = EncryptSecret(cfg.SecurityToken, passphrase); err != nil {
+		return nil, err
+	}
+	return yaml.Marshal(&encrypted)
+}
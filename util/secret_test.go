@@ -0,0 +1,52 @@
+package util
+
+import (
+	"encoding/hex"
+
+	"gopkg.in/yaml.v2"
+)
+
+func (s *UtilTestSuite) TestPBKDF2SHA256() {
+	assert := s.Require()
+	assert.Equal("c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a",
+		hex.EncodeToString(pbkdf2SHA256([]byte("password"), []byte("salt"), 4096, 32)))
+	assert.Equal("55ac046e56e3089fec1691c22544b605f94185216dde0465e68b9d57c20dacbc"+
+		"49ca9cccf179b645991664b39d77ef317c71b845b1e30bd509112041d3a19783",
+		hex.EncodeToString(pbkdf2SHA256([]byte("passwd"), []byte("salt"), 1, 64)))
+}
+
+func (s *UtilTestSuite) TestEncryptSecret() {
+	assert := s.Require()
+	encrypted, err := EncryptSecret("secret", "passphrase")
+	assert.Nil(err)
+	assert.True(IsEncryptedSecret(encrypted))
+	assert.NotContains(encrypted, "secret")
+
+	plain, err := DecryptSecret(encrypted, "passphrase")
+	assert.Nil(err)
+	assert.Equal("secret", plain)
+	_, err = DecryptSecret(encrypted, "wrong")
+	assert.NotNil(err)
+
+	plain, err = DecryptSecret("secret", "passphrase")
+	assert.Nil(err)
+	assert.Equal("secret", plain)
+}
+
+func (s *UtilTestSuite) TestMarshalConfig() {
+	assert := s.Require()
+	cfg := NewGlobalConfig()
+	cfg.AccessKeyID = "id"
+	cfg.AccessKeySecret = "secret"
+	data, err := MarshalConfig(cfg, "passphrase")
+	assert.Nil(err)
+	assert.Equal("secret", cfg.AccessKeySecret)
+
+	loaded := NewGlobalConfig()
+	assert.Nil(yaml.Unmarshal(data, loaded))
+	assert.Equal("id", loaded.AccessKeyID)
+	assert.True(loaded.HasEncryptedSecrets())
+	assert.Nil(loaded.DecryptSecrets("passphrase"))
+	assert.Equal("secret", loaded.AccessKeySecret)
+	assert.Equal("", loaded.SecurityToken)
+}
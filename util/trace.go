@@ -0,0 +1,277 @@
+package util
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultTraceBodyLimit is the max bytes of the request and response bodies in the trace.
+const DefaultTraceBodyLimit = 2048
+
+const redacted = "***"
+
+// maxRetryAfters is the max number of the Retry-After kept for the retryer
+const maxRetryAfters = 100
+
+var (
+	transportMu      sync.Mutex
+	defaultTransport http.RoundTripper
+	traceOutput      io.Writer
+	traceWriteMu     sync.Mutex
+
+	// retryAfters is the Retry-After of the responses by the request id
+	retryAfterMu sync.Mutex
+	retryAfters  = map[string]time.Duration{}
+)
+
+// the headers of the request id of the fc, sls and ram responses
+var requestIDHeaders = []string{"X-Fc-Request-Id", "X-Log-Requestid", "X-Acs-Request-Id"}
+
+// EnableHTTPTrace logs the http requests and responses of the fc, ram and sls clients to w,
+// which are sent by the default transport or the transports wrapped by InstrumentRoundTripper.
+func EnableHTTPTrace(w io.Writer) {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	installDefaultTransport()
+	traceOutput = w
+}
+
+// DisableHTTPTrace stops logging the http requests.
+func DisableHTTPTrace() {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	traceOutput = nil
+}
+
+// instrumentDefaultTransport instruments the default transport, which is used by the fc and sls clients.
+func instrumentDefaultTransport() {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	installDefaultTransport()
+}
+
+// installDefaultTransport instruments the default transport, transportMu is held by the caller.
+func installDefaultTransport() {
+	if defaultTransport == nil {
+		defaultTransport = http.DefaultTransport
+		http.DefaultTransport = InstrumentRoundTripper(defaultTransport)
+	}
+}
+
+// InstrumentRoundTripper wraps the transport to trace, record or replay the http requests when
+// they are enabled.
+func InstrumentRoundTripper(base http.RoundTripper) http.RoundTripper {
+	return &instrumentedTransport{base: base}
+}
+
+// debugOutput returns the output of the debug logs, the trace output if it is enabled, otherwise stderr.
+func debugOutput() io.Writer {
+	transportMu.Lock()
+	defer transportMu.Unlock()
+	if traceOutput != nil {
+		return traceOutput
+	}
+	return os.Stderr
+}
+
+// instrumentedTransport checks the trace, record and replay for each request, so that the clients
+// created before they are enabled are covered too.
+type instrumentedTransport struct {
+	base http.RoundTripper
+}
+
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	transportMu.Lock()
+	out, cassette := traceOutput, activeCassette
+	transportMu.Unlock()
+	rt := t.base
+	if cassette != nil {
+		rt = &CassetteTransport{Base: rt, Cassette: cassette}
+	}
+	if out != nil {
+		rt = &TraceTransport{Base: rt, Out: out, MaxBody: DefaultTraceBodyLimit}
+	}
+	resp, err := rt.RoundTrip(req)
+	if err == nil {
+		recordRetryAfter(resp, time.Now())
+	}
+	return resp, err
+}
+
+// recordRetryAfter keeps the Retry-After of the response for the retryer by the request id.
+func recordRetryAfter(resp *http.Response, now time.Time) {
+	d := parseRetryAfter(resp.Header.Get("Retry-After"), now)
+	if d <= 0 {
+		return
+	}
+	for _, header := range requestIDHeaders {
+		if id := resp.Header.Get(header); id != "" {
+			retryAfterMu.Lock()
+			if len(retryAfters) >= maxRetryAfters {
+				retryAfters = map[string]time.Duration{}
+			}
+			retryAfters[id] = d
+			retryAfterMu.Unlock()
+			return
+		}
+	}
+}
+
+// takeRetryAfter returns and forgets the Retry-After of the response of the request id.
+func takeRetryAfter(requestID string) (time.Duration, bool) {
+	retryAfterMu.Lock()
+	defer retryAfterMu.Unlock()
+	d, ok := retryAfters[requestID]
+	delete(retryAfters, requestID)
+	return d, ok
+}
+
+// parseRetryAfter parses the Retry-After in seconds or as a http date, it is 0 if invalid.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// TraceTransport logs the method, url, headers, status, latency and the truncated bodies of the
+// http requests and responses. The credentials in the headers, the query and the bodies are redacted.
+type TraceTransport struct {
+	Base    http.RoundTripper
+	Out     io.Writer
+	MaxBody int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TraceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(&req.Body)
+	if err != nil {
+		return nil, err
+	}
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+	latency := time.Since(start)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "--> %s %s\n", req.Method, redactURL(req.URL))
+	writeHeaders(&b, req.Header)
+	t.writeBody(&b, req.Header.Get("Content-Type"), reqBody)
+	if err != nil {
+		fmt.Fprintf(&b, "<-- error (%v): %v\n\n", latency.Round(time.Millisecond), err)
+		t.write(b.Bytes())
+		return nil, err
+	}
+	respBody, readErr := readBody(&resp.Body)
+	fmt.Fprintf(&b, "<-- %s (%v)\n", resp.Status, latency.Round(time.Millisecond))
+	writeHeaders(&b, resp.Header)
+	t.writeBody(&b, resp.Header.Get("Content-Type"), respBody)
+	b.WriteString("\n")
+	t.write(b.Bytes())
+	return resp, readErr
+}
+
+func (t *TraceTransport) write(p []byte) {
+	traceWriteMu.Lock()
+	defer traceWriteMu.Unlock()
+	t.Out.Write(p)
+}
+
+func (t *TraceTransport) writeBody(b *bytes.Buffer, contentType string, body []byte) {
+	if len(body) == 0 {
+		return
+	}
+	s := RedactBody(contentType, string(body))
+	if t.MaxBody > 0 && len(s) > t.MaxBody {
+		s = fmt.Sprintf("%s... (%d bytes truncated)", s[:t.MaxBody], len(s)-t.MaxBody)
+	}
+	b.WriteString(s)
+	b.WriteString("\n")
+}
+
+// readBody reads the body and replaces it with a reader of the content.
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+	data, err := ioutil.ReadAll(*body)
+	(*body).Close()
+	*body = ioutil.NopCloser(bytes.NewReader(data))
+	return data, err
+}
+
+// isSecretKey returns whether the header, query or body key carries the credentials.
+func isSecretKey(key string) bool {
+	k := strings.ToLower(key)
+	if strings.HasSuffix(k, "signature") {
+		return true
+	}
+	for _, s := range []string{"authorization", "secret", "password",
+		"securitytoken", "security-token", "security_token"} {
+		if strings.Contains(k, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func writeHeaders(b *bytes.Buffer, header http.Header) {
+	keys := make([]string, 0, len(header))
+	for k := range header {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		v := strings.Join(header[k], ", ")
+		if isSecretKey(k) {
+			v = redacted
+		}
+		fmt.Fprintf(b, "%s: %s\n", k, v)
+	}
+}
+
+func redactURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = redactValues(c.RawQuery)
+	return c.String()
+}
+
+// redactValues redacts the secrets of the url encoded values, which are kept as is if they are not valid.
+func redactValues(raw string) string {
+	values, err := url.ParseQuery(raw)
+	if err != nil || len(values) == 0 {
+		return raw
+	}
+	for k := range values {
+		if isSecretKey(k) {
+			values[k] = []string{redacted}
+		}
+	}
+	return values.Encode()
+}
+
+var jsonSecretPattern = regexp.MustCompile(`(?i)((?:"[^"]*(?:authorization|secret|password|security[-_]?token)[^"]*"|"[^"]*signature")\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// RedactBody redacts the secrets of the json or form body.
+func RedactBody(contentType, body string) string {
+	if strings.Contains(contentType, "application/x-www-form-urlencoded") {
+		return redactValues(body)
+	}
+	return jsonSecretPattern.ReplaceAllString(body, `$1"`+redacted+`"`)
+}
@@ -0,0 +1,70 @@
+package util
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+)
+
+func (s *UtilTestSuite) TestTraceTransport() {
+	assert := s.Require()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"Credentials": {"AccessKeyId": "STS.id", "AccessKeySecret": "sts-secret",` +
+			` "SecurityToken": "sts-token"}, "Padding": "` + strings.Repeat("x", 100) + `"}`))
+	}))
+	defer server.Close()
+
+	var out bytes.Buffer
+	client := &http.Client{Transport: &TraceTransport{Base: http.DefaultTransport, Out: &out, MaxBody: 120}}
+	req, err := http.NewRequest(http.MethodPost, server.URL+"/?Action=AssumeRole&Signature=sig",
+		strings.NewReader("AccessKeyId=id&SecurityToken=token"))
+	assert.Nil(err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "FC id:signature")
+	req.Header.Set("X-Fc-Security-Token", "token")
+	resp, err := client.Do(req)
+	assert.Nil(err)
+	body, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.Nil(err)
+	// the response body is still readable
+	assert.Contains(string(body), "sts-secret")
+
+	trace := out.String()
+	assert.Contains(trace, "--> POST "+server.URL+"/?Action=AssumeRole&Signature=%2A%2A%2A")
+	assert.Contains(trace, "Authorization: ***")
+	assert.Contains(trace, "X-Fc-Security-Token: ***")
+	assert.Contains(trace, "AccessKeyId=id&SecurityToken=%2A%2A%2A")
+	assert.Contains(trace, "<-- 200 OK (")
+	assert.Contains(trace, `"AccessKeyId": "STS.id"`)
+	assert.Contains(trace, `"AccessKeySecret": "***"`)
+	assert.Contains(trace, `"SecurityToken": "***"`)
+	assert.Contains(trace, "bytes truncated)")
+	for _, secret := range []string{"sig\n", "signature", "sts-secret", "sts-token", "=token"} {
+		assert.NotContains(trace, secret)
+	}
+}
+
+func (s *UtilTestSuite) TestEnableHTTPTrace() {
+	assert := s.Require()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	traced := &http.Client{Transport: InstrumentRoundTripper(&http.Transport{})}
+
+	var out bytes.Buffer
+	EnableHTTPTrace(&out)
+	_, err := http.Get(server.URL + "/default")
+	assert.Nil(err)
+	_, err = traced.Get(server.URL + "/wrapped")
+	assert.Nil(err)
+	DisableHTTPTrace()
+	_, err = http.Get(server.URL + "/disabled")
+	assert.Nil(err)
+
+	assert.Contains(out.String(), "--> GET "+server.URL+"/default")
+	assert.Contains(out.String(), "--> GET "+server.URL+"/wrapped")
+	assert.NotContains(out.String(), "/disabled")
+}
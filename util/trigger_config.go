@@ -0,0 +1,353 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/aliyun/fc-go-sdk"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// TriggerConfigKey is the top level key of the trigger config file
+	TriggerConfigKey = "triggerConfig"
+
+	// TriggerTypeTableStore is the trigger type of the tablestore stream
+	TriggerTypeTableStore = "tablestore"
+
+	// TriggerTypeRDS is the trigger type of the rds binlog
+	TriggerTypeRDS = "rds"
+)
+
+// TriggerTypes lists all the supported trigger types
+var TriggerTypes = []string{
+	fc.TRIGGER_TYPE_OSS,
+	fc.TRIGGER_TYPE_LOG,
+	fc.TRIGGER_TYPE_TIMER,
+	fc.TRIGGER_TYPE_HTTP,
+	fc.TRIGGER_TYPE_CDN_EVENTS,
+	fc.TRIGGER_TYPE_MNS_TOPIC,
+	TriggerTypeTableStore,
+	TriggerTypeRDS,
+}
+
+// TableStoreTriggerConfig defines the tablestore trigger config, the instance and table
+// are specified by the source arn acs:ots:<region>:<account_id>:instance/<instance>/table/<table>.
+type TableStoreTriggerConfig struct {
+}
+
+// RDSTriggerConfig defines the rds trigger config, the source arn is acs:rds:<region>:<account_id>:dbinstance/<instance>.
+type RDSTriggerConfig struct {
+	SubscriptionObjects []string `json:"subscriptionObjects"`
+	Retry               *int     `json:"retry"`
+	Concurrency         *int     `json:"concurrency"`
+	EventFormat         *string  `json:"eventFormat"`
+}
+
+// TriggerInvocationPrincipals maps the trigger type to the service which assumes the invocation role
+var TriggerInvocationPrincipals = map[string]string{
+	fc.TRIGGER_TYPE_OSS:        "oss.aliyuncs.com",
+	fc.TRIGGER_TYPE_LOG:        "log.aliyuncs.com",
+	fc.TRIGGER_TYPE_CDN_EVENTS: "cdn.aliyuncs.com",
+	fc.TRIGGER_TYPE_MNS_TOPIC:  "mns.aliyuncs.com",
+	TriggerTypeTableStore:      "otsdispatch.aliyuncs.com",
+	TriggerTypeRDS:             "rds.aliyuncs.com",
+}
+
+// BuildSourceARN builds the source arn of the trigger from the region and the account id in the fc endpoint.
+// source is the bucket of the oss trigger, the project or <project>/<logstore> of the log trigger
+// and the topic of the mns_topic trigger.
+func BuildSourceARN(triggerType, endpoint, source string) (string, error) {
+	if source == "" {
+		return "", fmt.Errorf("empty event source")
+	}
+	uid, err := GetUIDFromEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	region, err := GetRegionForEndpoint(endpoint)
+	if err != nil {
+		return "", err
+	}
+	switch triggerType {
+	case fc.TRIGGER_TYPE_OSS:
+		return fmt.Sprintf("acs:oss:%s:%s:%s", region, uid, source), nil
+	case fc.TRIGGER_TYPE_LOG:
+		project := strings.SplitN(source, "/", 2)[0]
+		return fmt.Sprintf("acs:log:%s:%s:project/%s", region, uid, project), nil
+	case fc.TRIGGER_TYPE_MNS_TOPIC:
+		return fmt.Sprintf("acs:mns:%s:%s:/topics/%s", region, uid, source), nil
+	default:
+		return "", fmt.Errorf("can not build the source arn of %s trigger, please specify it directly", triggerType)
+	}
+}
+
+// triggerConfigTypes maps the trigger type to the type of its trigger config
+var triggerConfigTypes = map[string]reflect.Type{
+	fc.TRIGGER_TYPE_OSS:        reflect.TypeOf(fc.OSSTriggerConfig{}),
+	fc.TRIGGER_TYPE_TIMER:      reflect.TypeOf(fc.TimeTriggerConfig{}),
+	fc.TRIGGER_TYPE_LOG:        reflect.TypeOf(fc.LogTriggerConfig{}),
+	fc.TRIGGER_TYPE_CDN_EVENTS: reflect.TypeOf(fc.CDNEventsTriggerConfig{}),
+	fc.TRIGGER_TYPE_HTTP:       reflect.TypeOf(fc.HTTPTriggerConfig{}),
+	fc.TRIGGER_TYPE_MNS_TOPIC:  reflect.TypeOf(fc.MnsTopicTriggerConfig{}),
+	TriggerTypeTableStore:      reflect.TypeOf(TableStoreTriggerConfig{}),
+	TriggerTypeRDS:             reflect.TypeOf(RDSTriggerConfig{}),
+}
+
+// requiredTriggerConfigFields lists the fields, relative to triggerConfig, that must be set for each trigger type
+var requiredTriggerConfigFields = map[string][]string{
+	fc.TRIGGER_TYPE_OSS:        {"events"},
+	fc.TRIGGER_TYPE_TIMER:      {"cronExpression"},
+	fc.TRIGGER_TYPE_LOG:        {"sourceConfig.logstore", "jobConfig", "logConfig.project", "logConfig.logstore", "enable"},
+	fc.TRIGGER_TYPE_CDN_EVENTS: {"eventName", "eventVersion", "notes", "filter"},
+	fc.TRIGGER_TYPE_HTTP:       {"authType", "methods"},
+	fc.TRIGGER_TYPE_MNS_TOPIC:  {},
+	TriggerTypeTableStore:      {},
+	TriggerTypeRDS:             {"subscriptionObjects"},
+}
+
+// triggerConfigFieldError describes an invalid field in the trigger config file.
+type triggerConfigFieldError struct {
+	// path is the keys from triggerConfig to the field
+	path    []string
+	message string
+}
+
+// ValidateTriggerConfigFile checks the trigger config file strictly: the misspelled
+// and the missing fields are reported with the line number in the file.
+// Only json and yaml files are checked, and unknown trigger types are ignored.
+func ValidateTriggerConfigFile(triggerType string, triggerConfigFile string) error {
+	configType, ok := triggerConfigTypes[triggerType]
+	if !ok {
+		return nil
+	}
+	data, err := ioutil.ReadFile(triggerConfigFile)
+	if err != nil {
+		return fmt.Errorf("failed to read config file %s due to %v", triggerConfigFile, err)
+	}
+
+	var content interface{}
+	switch strings.ToLower(filepath.Ext(triggerConfigFile)) {
+	case ".json":
+		err = json.Unmarshal(data, &content)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &content)
+		content = normalizeYAML(content)
+	default:
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to parse config file %s due to %v", triggerConfigFile, err)
+	}
+
+	errs := validateTriggerConfig(triggerType, configType, content)
+	if len(errs) == 0 {
+		return nil
+	}
+	lines := strings.Split(string(data), "\n")
+	msgs := make([]string, 0, len(errs))
+	for _, e := range errs {
+		if line := findKeyLine(lines, e.path); line > 0 {
+			msgs = append(msgs, fmt.Sprintf("  %s:%d: %s", triggerConfigFile, line, e.message))
+		} else {
+			msgs = append(msgs, fmt.Sprintf("  %s: %s", triggerConfigFile, e.message))
+		}
+	}
+	return NewValidationError("invalid %s trigger config:\n%s", triggerType, strings.Join(msgs, "\n"))
+}
+
+func validateTriggerConfig(triggerType string, configType reflect.Type, content interface{}) []triggerConfigFieldError {
+	root, ok := content.(map[string]interface{})
+	if !ok {
+		return []triggerConfigFieldError{{message: "expect an object at the top level"}}
+	}
+	var config interface{}
+	found := false
+	for k, v := range root {
+		if strings.EqualFold(k, TriggerConfigKey) {
+			config, found = v, true
+		}
+	}
+	if !found {
+		return []triggerConfigFieldError{{message: fmt.Sprintf("missing required field %s", TriggerConfigKey)}}
+	}
+
+	errs := []triggerConfigFieldError{}
+	validateTriggerConfigValue(config, configType, []string{TriggerConfigKey}, &errs)
+	for _, required := range requiredTriggerConfigFields[triggerType] {
+		path := append([]string{TriggerConfigKey}, strings.Split(required, ".")...)
+		if !hasTriggerConfigField(config, path[1:]) {
+			errs = append(errs, triggerConfigFieldError{
+				path:    path[:len(path)-1],
+				message: fmt.Sprintf("missing required field %s", strings.Join(path, ".")),
+			})
+		}
+	}
+	return errs
+}
+
+func validateTriggerConfigValue(value interface{}, t reflect.Type, path []string, errs *[]triggerConfigFieldError) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if value == nil {
+		return
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, triggerConfigFieldError{
+				path:    path,
+				message: fmt.Sprintf("%s should be an object", strings.Join(path, ".")),
+			})
+			return
+		}
+		keys := sortedKeys(obj)
+		for _, k := range keys {
+			field, ok := lookupConfigField(t, k)
+			if !ok {
+				expect := "no field is expected"
+				if names := configFieldNames(t); len(names) > 0 {
+					expect = "expect one of: " + strings.Join(names, ", ")
+				}
+				*errs = append(*errs, triggerConfigFieldError{
+					path:    append(append([]string{}, path...), k),
+					message: fmt.Sprintf("unknown field %q in %s, %s", k, strings.Join(path, "."), expect),
+				})
+				continue
+			}
+			validateTriggerConfigValue(obj[k], field.Type, append(append([]string{}, path...), k), errs)
+		}
+	case reflect.Slice:
+		arr, ok := value.([]interface{})
+		if !ok {
+			*errs = append(*errs, triggerConfigFieldError{
+				path:    path,
+				message: fmt.Sprintf("%s should be a list", strings.Join(path, ".")),
+			})
+			return
+		}
+		for _, v := range arr {
+			validateTriggerConfigValue(v, t.Elem(), path, errs)
+		}
+	case reflect.Map:
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			*errs = append(*errs, triggerConfigFieldError{
+				path:    path,
+				message: fmt.Sprintf("%s should be an object", strings.Join(path, ".")),
+			})
+			return
+		}
+		for _, k := range sortedKeys(obj) {
+			validateTriggerConfigValue(obj[k], t.Elem(), append(append([]string{}, path...), k), errs)
+		}
+	}
+}
+
+// lookupConfigField finds the field by its json name or field name, case insensitively like viper does.
+func lookupConfigField(t reflect.Type, key string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if strings.EqualFold(jsonFieldName(f), key) || strings.EqualFold(f.Name, key) {
+			return f, true
+		}
+	}
+	return reflect.StructField{}, false
+}
+
+func configFieldNames(t reflect.Type) []string {
+	names := []string{}
+	for i := 0; i < t.NumField(); i++ {
+		names = append(names, jsonFieldName(t.Field(i)))
+	}
+	return names
+}
+
+func jsonFieldName(f reflect.StructField) string {
+	name := strings.Split(f.Tag.Get("json"), ",")[0]
+	if name == "" || name == "-" {
+		return f.Name
+	}
+	return name
+}
+
+func hasTriggerConfigField(value interface{}, path []string) bool {
+	for _, key := range path {
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		found := false
+		for k, v := range obj {
+			if strings.EqualFold(k, key) && v != nil {
+				value, found = v, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// findKeyLine returns the 1-based line number of the last key in path,
+// searching each key after the line of its parent. 0 is returned if not found.
+func findKeyLine(lines []string, path []string) int {
+	if len(path) == 0 {
+		return 0
+	}
+	start := 0
+	line := 0
+	for _, key := range path {
+		line = 0
+		lowerKey := strings.ToLower(key)
+		for i := start; i < len(lines); i++ {
+			l := strings.ToLower(lines[i])
+			if strings.Contains(l, `"`+lowerKey+`"`) || strings.Contains(l, lowerKey+":") {
+				line = i + 1
+				break
+			}
+		}
+		if line == 0 {
+			return 0
+		}
+		start = line
+	}
+	return line
+}
+
+// normalizeYAML converts the map[interface{}]interface{} decoded by yaml into map[string]interface{}.
+func normalizeYAML(v interface{}) interface{} {
+	switch t := v.(type) {
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(t))
+		for k, val := range t {
+			m[fmt.Sprintf("%v", k)] = normalizeYAML(val)
+		}
+		return m
+	case []interface{}:
+		for i, val := range t {
+			t[i] = normalizeYAML(val)
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,65 @@
+package util
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+
+	"github.com/aliyun/fc-go-sdk"
+)
+
+func (s *UtilTestSuite) TestValidateTriggerConfigFile() {
+	dir, err := ioutil.TempDir("", "fcli")
+	s.Nil(err)
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, "oss.yaml")
+	s.Nil(ioutil.WriteFile(file, []byte(`triggerConfig:
+    events:
+        - oss:ObjectCreated:PutObject
+    filter:
+        key:
+            prefx: source/
+`), 0600))
+	err = ValidateTriggerConfigFile(fc.TRIGGER_TYPE_OSS, file)
+	s.NotNil(err)
+	s.Contains(err.Error(), file+`:6: unknown field "prefx" in triggerConfig.filter.key`)
+
+	file = path.Join(dir, "log.json")
+	s.Nil(ioutil.WriteFile(file, []byte(`{
+  "triggerConfig": {
+    "sourceConfig": {"logstore": "source"},
+    "jobConfig": {"maxRetryTime": 3, "triggerInterval": 60},
+    "logConfig": {"project": "project"},
+    "enable": true
+  }
+}`), 0600))
+	err = ValidateTriggerConfigFile(fc.TRIGGER_TYPE_LOG, file)
+	s.NotNil(err)
+	s.Contains(err.Error(), file+":5: missing required field triggerConfig.logConfig.logstore")
+
+	file = path.Join(dir, "timer.yml")
+	s.Nil(ioutil.WriteFile(file, []byte(`triggerConfig:
+    CronExpression: "@every 5m"
+    enable: true
+`), 0600))
+	s.Nil(ValidateTriggerConfigFile(fc.TRIGGER_TYPE_TIMER, file))
+}
+
+func (s *UtilTestSuite) TestBuildSourceARN() {
+	endpoint := "https://123456.cn-shanghai.fc.aliyuncs.com"
+	arn, err := BuildSourceARN(fc.TRIGGER_TYPE_OSS, endpoint, "bucket1")
+	s.Nil(err)
+	s.Equal("acs:oss:cn-shanghai:123456:bucket1", arn)
+
+	arn, err = BuildSourceARN(fc.TRIGGER_TYPE_LOG, endpoint, "project1/logstore1")
+	s.Nil(err)
+	s.Equal("acs:log:cn-shanghai:123456:project/project1", arn)
+
+	arn, err = BuildSourceARN(fc.TRIGGER_TYPE_MNS_TOPIC, endpoint, "topic1")
+	s.Nil(err)
+	s.Equal("acs:mns:cn-shanghai:123456:/topics/topic1", arn)
+
+	_, err = BuildSourceARN(fc.TRIGGER_TYPE_TIMER, endpoint, "any")
+	s.NotNil(err)
+}
@@ -0,0 +1,184 @@
+package util
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// GenerateTriggerEvent generates a sample event of the trigger for local testing.
+// The instance, table and database names are taken from the source arn if it is not empty.
+func GenerateTriggerEvent(triggerType, sourceARN string, now time.Time) (interface{}, error) {
+	resource := ""
+	if sourceARN != "" {
+		parts := strings.Split(sourceARN, ":")
+		resource = parts[len(parts)-1]
+	}
+
+	switch triggerType {
+	case TriggerTypeTableStore:
+		// the resource is instance/<instance>/table/<table>
+		table := "test_table"
+		if parts := strings.Split(resource, "/"); len(parts) == 4 {
+			table = parts[3]
+		}
+		return map[string]interface{}{
+			"Version": "Sync-v1",
+			"Records": []interface{}{
+				map[string]interface{}{
+					"Type": "PutRow",
+					"Info": map[string]interface{}{
+						"Timestamp": now.UnixNano() / int64(time.Microsecond),
+					},
+					"PrimaryKey": []interface{}{
+						map[string]interface{}{"ColumnName": "pk_0", "Value": now.UnixNano()},
+					},
+					"Columns": []interface{}{
+						map[string]interface{}{
+							"Type":       "Put",
+							"ColumnName": "attr_0",
+							"Value":      "hello " + table,
+							"Timestamp":  now.UnixNano() / int64(time.Millisecond),
+						},
+					},
+				},
+			},
+		}, nil
+	case TriggerTypeRDS:
+		// the resource is dbinstance/<instance>
+		instance := "rm-test"
+		if parts := strings.Split(resource, "/"); len(parts) == 2 {
+			instance = parts[1]
+		}
+		return map[string]interface{}{
+			"dbInstanceId": instance,
+			"events": []interface{}{
+				map[string]interface{}{
+					"recordType": "INSERT",
+					"dbName":     "db1",
+					"tableName":  "table1",
+					"recordTime": now.Unix(),
+					"before":     nil,
+					"after": map[string]interface{}{
+						"id":   1,
+						"name": "hello",
+					},
+				},
+			},
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported trigger type, expect %s or %s, actual %s",
+			TriggerTypeTableStore, TriggerTypeRDS, triggerType)
+	}
+}
+
+// EncodeCBOR encodes the value decoded from json (maps, slices, strings, numbers, booleans and nil)
+// in CBOR, which is the format of the events sent by the tablestore trigger.
+// The map keys are sorted so that the output is stable.
+func EncodeCBOR(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeCBOR(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+const (
+	cborUnsigned = 0 << 5
+	cborNegative = 1 << 5
+	cborBytes    = 2 << 5
+	cborText     = 3 << 5
+	cborArray    = 4 << 5
+	cborMap      = 5 << 5
+	cborSimple   = 7 << 5
+)
+
+func encodeCBOR(buf *bytes.Buffer, v interface{}) error {
+	if v == nil {
+		buf.WriteByte(cborSimple | 22)
+		return nil
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Bool:
+		if rv.Bool() {
+			buf.WriteByte(cborSimple | 21)
+		} else {
+			buf.WriteByte(cborSimple | 20)
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n := rv.Int()
+		if n >= 0 {
+			writeCBORHead(buf, cborUnsigned, uint64(n))
+		} else {
+			writeCBORHead(buf, cborNegative, uint64(-1-n))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		writeCBORHead(buf, cborUnsigned, rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		f := rv.Float()
+		if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+			return encodeCBOR(buf, int64(f))
+		}
+		buf.WriteByte(cborSimple | 27)
+		binary.Write(buf, binary.BigEndian, math.Float64bits(f))
+	case reflect.String:
+		writeCBORHead(buf, cborText, uint64(rv.Len()))
+		buf.WriteString(rv.String())
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.Uint8 {
+			writeCBORHead(buf, cborBytes, uint64(rv.Len()))
+			buf.Write(rv.Bytes())
+			return nil
+		}
+		writeCBORHead(buf, cborArray, uint64(rv.Len()))
+		for i := 0; i < rv.Len(); i++ {
+			if err := encodeCBOR(buf, rv.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return fmt.Errorf("unsupported map key type %s", rv.Type().Key())
+		}
+		keys := make([]string, 0, rv.Len())
+		for _, k := range rv.MapKeys() {
+			keys = append(keys, k.String())
+		}
+		sort.Strings(keys)
+		writeCBORHead(buf, cborMap, uint64(len(keys)))
+		for _, k := range keys {
+			encodeCBOR(buf, k)
+			if err := encodeCBOR(buf, rv.MapIndex(reflect.ValueOf(k)).Interface()); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported type %T", v)
+	}
+	return nil
+}
+
+func writeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major | byte(n))
+	case n <= math.MaxUint8:
+		buf.WriteByte(major | 24)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(major | 25)
+		binary.Write(buf, binary.BigEndian, uint16(n))
+	case n <= math.MaxUint32:
+		buf.WriteByte(major | 26)
+		binary.Write(buf, binary.BigEndian, uint32(n))
+	default:
+		buf.WriteByte(major | 27)
+		binary.Write(buf, binary.BigEndian, n)
+	}
+}
@@ -0,0 +1,14 @@
+package util
+
+func (s *UtilTestSuite) TestEncodeCBOR() {
+	data, err := EncodeCBOR(map[string]interface{}{
+		"b": []interface{}{1, -1, true, nil},
+		"a": "x",
+	})
+	s.Nil(err)
+	s.Equal([]byte{0xa2, 0x61, 'a', 0x61, 'x', 0x61, 'b', 0x84, 0x01, 0x20, 0xf5, 0xf6}, data)
+
+	data, err = EncodeCBOR(1000)
+	s.Nil(err)
+	s.Equal([]byte{0x19, 0x03, 0xe8}, data)
+}
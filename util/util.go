@@ -9,6 +9,7 @@ import (
 	"net"
 	"net/http"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -44,6 +45,9 @@ const (
 	// 因此查询间隔为1分钟，如果过短则会出现数据写入后查询时没有数据的情况，查询区间继续往下走则会漏掉这部分数据
 	GetLogsIntervalTimeInSecs int64 = 1 * 60
 
+	// MaxPolicyVersions defines the max number of the versions of a ram policy
+	MaxPolicyVersions = 5
+
 	// IncompleteProgress defines progress status in GetLogs with specific query
 	IncompleteProgress = "Incomplete"
 
@@ -68,6 +72,57 @@ type GlobalConfig struct {
 	Debug           bool   `yaml:"debug"`
 	Timeout         uint   `yaml:"timeout"`
 	SLSEndpoint     string `yaml:"sls_endpoint"`
+
+	// the defaults of the --service-name and --qualifier flags
+	ServiceName string `yaml:"service_name,omitempty"`
+	Qualifier   string `yaml:"qualifier,omitempty"`
+
+	// assume the role by sts with the credentials from the access key or the ecs ram role
+	RoleARN             string `yaml:"role_arn,omitempty"`
+	RoleSessionName     string `yaml:"role_session_name,omitempty"`
+	RoleSessionDuration int    `yaml:"role_session_duration,omitempty"`
+	STSEndpoint         string `yaml:"sts_endpoint,omitempty"`
+	// get the credentials of the ram role attached to the ecs instance from the metadata
+	ECSRAMRole     string `yaml:"ecs_ram_role,omitempty"`
+	ECSMetadataURL string `yaml:"ecs_metadata_url,omitempty"`
+
+	// the max attempts of a fc api call, DefaultMaxAttempts if it is not set
+	MaxAttempts int `yaml:"max_attempts,omitempty"`
+
+	// the url or the file to refresh the region catalog from
+	RegionCatalogURL string `yaml:"region_catalog_url,omitempty"`
+
+	credentialProvider CredentialProvider
+}
+
+// Credentials returns the credentials from the provider chain of the config,
+// the temporary credentials are refreshed before they expire.
+func (cfg *GlobalConfig) Credentials() (*Credentials, error) {
+	if cfg.credentialProvider == nil {
+		cfg.credentialProvider = NewCredentialProvider(cfg)
+	}
+	c, err := cfg.credentialProvider.Retrieve()
+	if err == ErrNoCredentials {
+		return nil, fmt.Errorf("no credentials found, please configure the access key by fcli config, " +
+			"or the environment variables ALIBABA_CLOUD_ACCESS_KEY_ID and ALIBABA_CLOUD_ACCESS_KEY_SECRET")
+	}
+	return c, err
+}
+
+// ForRegion returns a copy of the config with the endpoints of the region, the credentials are shared.
+// It initializes the credential provider of the config, so it is not safe to call concurrently.
+func (cfg *GlobalConfig) ForRegion(region string) (*GlobalConfig, error) {
+	endpoint, err := BuildEndpoint(cfg.Endpoint, "", region)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.credentialProvider == nil {
+		cfg.credentialProvider = NewCredentialProvider(cfg)
+	}
+	c := *cfg
+	c.Endpoint = endpoint
+	c.SLSEndpoint = SLSEndpointForEndpoint(endpoint)
+	return &c, nil
 }
 
 // NewGlobalConfig create a global config.
@@ -87,40 +142,78 @@ func NewGlobalConfig() *GlobalConfig {
 	return cfg
 }
 
-// NewFClient create a fc client.
-func NewFClient(cfg *GlobalConfig) (*fc.Client, error) {
-	return fc.NewClient(
-		cfg.Endpoint,
-		cfg.APIVersion,
-		cfg.AccessKeyID,
-		cfg.AccessKeySecret,
-		fc.WithSecurityToken(cfg.SecurityToken),
-		fc.WithTimeout(cfg.Timeout),
-		func(c *fc.Client) {
-			c.Config.UserAgent = cfg.UserAgent
-		},
-	)
+// NewFClient create a fc client with the credentials from the provider chain of the config,
+// the api calls are retried on throttling, 5xx and transport errors.
+func NewFClient(cfg *GlobalConfig) (*FClient, error) {
+	c, err := cfg.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	build := func(c *Credentials) (*fc.Client, error) {
+		return fc.NewClient(
+			cfg.Endpoint,
+			cfg.APIVersion,
+			c.AccessKeyID,
+			c.AccessKeySecret,
+			fc.WithSecurityToken(c.SecurityToken),
+			fc.WithTimeout(cfg.Timeout),
+			func(c *fc.Client) {
+				c.Config.UserAgent = cfg.UserAgent
+			},
+		)
+	}
+	client, err := build(c)
+	if err != nil {
+		return nil, err
+	}
+	// the Retry-After of the responses is read by the instrumented transport
+	instrumentDefaultTransport()
+	fclient := NewRetryFClient(client, NewRetryer(cfg))
+	fclient.credentials, fclient.current, fclient.build = cfg.Credentials, *c, build
+	return fclient, nil
 }
 
-// NewRAMClient create ram client
-func NewRAMClient(accessKeyID, accessKeySecret string) (*ram.Client, error) {
+// NewRAMClient create ram client, the credentials are retrieved from the provider chain
+// of the config for each request.
+func NewRAMClient(cfg *GlobalConfig) (*ram.Client, error) {
 	const endpoint = "https://ram.aliyuncs.com"
-	client, err := ram.NewClient(endpoint, accessKeyID, accessKeySecret)
+	if _, err := cfg.Credentials(); err != nil {
+		return nil, err
+	}
+	client, err := ram.NewClient(endpoint, "", "")
 	if err != nil {
 		return nil, fmt.Errorf("get ram client err: %s", err)
 	}
+	client.WrapTransport(InstrumentRoundTripper)
+	client.WithCredentials(func() (string, string, string, error) {
+		c, err := cfg.Credentials()
+		if err != nil {
+			return "", "", "", err
+		}
+		return c.AccessKeyID, c.AccessKeySecret, c.SecurityToken, nil
+	})
 	return client, nil
 }
 
-// NewSLSClient create sls client
-func NewSLSClient(cfg *GlobalConfig) *sls.Client {
-	c := &sls.Client{
-		Endpoint:        cfg.SLSEndpoint,
-		AccessKeyID:     cfg.AccessKeyID,
-		AccessKeySecret: cfg.AccessKeySecret,
-		SecurityToken:   cfg.SecurityToken,
+// NewSLSClient create sls client with the credentials from the provider chain of the config.
+// The client keeps the credentials, refresh them by RefreshSLSClient if it lives long.
+func NewSLSClient(cfg *GlobalConfig) (*sls.Client, error) {
+	c := &sls.Client{Endpoint: cfg.SLSEndpoint}
+	if err := RefreshSLSClient(c, cfg); err != nil {
+		return nil, err
 	}
-	return c
+	return c, nil
+}
+
+// RefreshSLSClient sets the current credentials from the provider chain of the config to the sls client,
+// the temporary credentials are renewed before they expire.
+func RefreshSLSClient(c *sls.Client, cfg *GlobalConfig) error {
+	cred, err := cfg.Credentials()
+	if err != nil {
+		return err
+	}
+	c.AccessKeyID, c.AccessKeySecret, c.SecurityToken = cred.AccessKeyID, cred.AccessKeySecret, cred.SecurityToken
+	return nil
 }
 
 // GetLogs read the log data from loghub with the count limit.
@@ -183,67 +276,70 @@ func PrettyPrintLog(v map[string]string) {
 
 // GetRegions get region list of fc
 func GetRegions() []string {
-	return []string{
-		"cn-beijing",
-		"cn-qingdao",
-		"cn-zhangjiakou",
-		"cn-hangzhou",
-		"cn-shanghai",
-		"cn-shenzhen",
-		"cn-hongkong",
-		"cn-huhehaote",
-		"ap-southeast-1",
-		"ap-southeast-2",
-		"ap-northeast-1",
-		"us-west-1",
-		"us-east-1",
-		"eu-central-1",
-		"ap-south-1",
-	}
-}
-
-// GetRegionNoForEndpoint get region no from fc endpoint for endpoint
-func GetRegionNoForEndpoint(endpoint string) string {
-	regions := GetRegions()
-	for _, region := range regions {
-		if strings.Contains(endpoint, region) {
-			return region
+	return CurrentRegionCatalog().FCRegions()
+}
+
+// ValidateRegion checks the region is one of the known regions.
+func ValidateRegion(region string) error {
+	for _, r := range GetRegions() {
+		if r == region {
+			return nil
 		}
 	}
-	return regions[0]
+	return fmt.Errorf("unknown region %s, the valid regions are: %s, "+
+		"run fcli region refresh to update the regions", region, strings.Join(GetRegions(), ", "))
+}
+
+// BuildEndpoint builds the fc endpoint of the account in the region, the account, the region
+// and the network default to the ones of the current endpoint.
+func BuildEndpoint(current, accountID, region string) (string, error) {
+	network := NetworkPublic
+	if info, err := ParseEndpoint(current); err == nil {
+		network = info.Network
+	}
+	if accountID == "" && current != "" {
+		accountID, _ = GetUIDFromEndpoint(current)
+	}
+	if accountID == "" {
+		return "", fmt.Errorf("the account id is required to build the endpoint")
+	}
+	if region == "" && current != "" {
+		region = GetRegionNoForEndpoint(current)
+	}
+	if region == "" {
+		return "", NewValidationError("the region is required to build the endpoint").
+			WithHint("specify the region by --region")
+	}
+	if err := ValidateRegion(region); err != nil {
+		return "", err
+	}
+	return FCEndpoint(accountID, region, network), nil
+}
+
+// GetRegionNoForEndpoint get region no from fc endpoint for endpoint, it returns empty if the endpoint
+// does not contain a known region, such as a custom domain or a local address.
+func GetRegionNoForEndpoint(endpoint string) string {
+	if info, err := ParseEndpoint(endpoint); err == nil {
+		return info.Region
+	}
+	return matchRegion(endpoint, GetRegions())
+}
+
+// GetRegionForEndpoint returns the region of the fc endpoint, it fails if the region is unknown.
+func GetRegionForEndpoint(endpoint string) (string, error) {
+	if region := GetRegionNoForEndpoint(endpoint); region != "" {
+		return region, nil
+	}
+	return "", NewValidationError("can not get the region of the endpoint %s", endpoint).
+		WithHint("specify the region by --region, or use the endpoint {account id}.{region}.fc.aliyuncs.com")
 }
 
 // GetRegionNoForSLSEndpoint get region no from fc endpoint for slsendpoint
 func GetRegionNoForSLSEndpoint(endpoint string) string {
-	// TODO Support the intranet endpoint.
-	regions := []string{
-		"cn-beijing",
-		"cn-hangzhou",
-		"cn-shanghai",
-		"cn-qingdao",
-		"cn-zhangjiakou",
-		"cn-huhehaote",
-		"cn-shenzhen",
-		"cn-chengdu",
-		"cn-hongkong",
-		"ap-northeast-1",
-		"ap-southeast-1",
-		"ap-southeast-2",
-		"ap-southeast-3",
-		"ap-southeast-5",
-		"me-east-1",
-		"us-west-1",
-		"eu-central-1",
-		"us-east-1",
-		"ap-south-1",
-		"eu-west-1",
-	}
-	for _, region := range regions {
-		if strings.Contains(endpoint, region) {
-			return region
-		}
+	if info, err := ParseEndpoint(endpoint); err == nil {
+		return info.Region
 	}
-	return regions[0]
+	return matchRegion(endpoint, CurrentRegionCatalog().LogRegions())
 }
 
 // GetUIDFromEndpoint extract the uid from the fc service endpoint.
@@ -258,11 +354,16 @@ func GetUIDFromEndpoint(endpoint string) (string, error) {
 	return uid, nil
 }
 
-// CreateRole create the RAM role.
-func CreateRole(cli *ram.Client, roleName, principal string) (roleARN string, err error) {
+// AssumeRolePolicyDocument returns the trust policy which allows the service principal to assume the role.
+func AssumeRolePolicyDocument(principal string) string {
 	tmpl := `{"Statement": [{"Action": "sts:AssumeRole", "Effect": "Allow", "Principal": { "%s": ["%s"]}}], "Version": "1"}`
 	const roleType = "Service"
-	doc := fmt.Sprintf(tmpl, roleType, principal)
+	return fmt.Sprintf(tmpl, roleType, principal)
+}
+
+// CreateRole create the RAM role.
+func CreateRole(cli *ram.Client, roleName, principal string) (roleARN string, err error) {
+	doc := AssumeRolePolicyDocument(principal)
 	desc := fmt.Sprintf("create the role %s", roleName)
 	resp, err := cli.CreateRole(roleName, doc, desc)
 	if err != nil {
@@ -316,25 +417,140 @@ func GetDefaultPolicyVersion(cli *ram.Client, policyName, policyType string) (*r
 }
 
 // CheckPolicyResourcePermission :
+// resource is a json array, the policy has the permission only if all the resources are allowed.
 func CheckPolicyResourcePermission(p *ram.PolicyVersion, resource string) (bool, error) {
-	hasResourcePermission := false
 	pd := &ram.PolicyDocument{}
 	err := json.Unmarshal([]byte(p.PolicyDocument), pd)
 	if err != nil {
 		return false, err
 	}
-	for _, val := range pd.Statement {
-		for _, vr := range val.Resource {
-			if resource == ("[\"" + vr + "\"]") {
-				hasResourcePermission = true
+	wanted := []string{}
+	if err := json.Unmarshal([]byte(resource), &wanted); err != nil {
+		return false, err
+	}
+	for _, w := range wanted {
+		found := false
+		for _, val := range pd.Statement {
+			for _, vr := range val.Resource {
+				if vr == w {
+					found = true
+					break
+				}
+			}
+			if found {
 				break
 			}
 		}
-		if hasResourcePermission {
-			break
+		if !found {
+			return false, nil
 		}
 	}
-	return hasResourcePermission, nil
+	return true, nil
+}
+
+// MergePolicyStatements adds the statements to the custom policy as its new default version, only one
+// version is created for all of them. The statements whose actions and resources are already allowed
+// are skipped, and the oldest versions are pruned before the version limit of ram is reached.
+func MergePolicyStatements(cli *ram.Client, policyName string, statements ...ram.PolicyStatement) (bool, error) {
+	version, err := GetDefaultPolicyVersion(cli, policyName, "Custom")
+	if err != nil {
+		return false, err
+	}
+	doc := version.PolicyDocument
+	for _, statement := range statements {
+		covered, err := PolicyCoversStatement(doc, statement)
+		if err != nil {
+			return false, err
+		}
+		if covered {
+			continue
+		}
+		if doc, err = AppendPolicyStatement(doc, statement); err != nil {
+			return false, err
+		}
+	}
+	if doc == version.PolicyDocument {
+		return false, nil
+	}
+	if err := PrunePolicyVersions(cli, policyName, MaxPolicyVersions-1); err != nil {
+		return false, err
+	}
+	if _, err := cli.CreatePolicyVersion(policyName, doc, "true"); err != nil {
+		return false, WrapErrorf(err, "failed to create policy version of %s", policyName)
+	}
+	return true, nil
+}
+
+// PolicyCoversStatement returns whether the policy document already allows every action on every
+// resource of the statement. The allow statements with the elements which are not evaluated, such as
+// Condition, do not count, and such a deny statement which matches makes the statement not covered.
+func PolicyCoversStatement(doc string, statement ram.PolicyStatement) (bool, error) {
+	parsed, err := ParsePolicyDocument(doc)
+	if err != nil {
+		return false, err
+	}
+	for _, action := range statement.Action {
+		for _, resource := range statement.Resource {
+			if !EvaluatePolicies([]NamedPolicyDocument{parsed}, action, resource).Allowed() {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// AppendPolicyStatement appends the statement to the policy document, the existing statements and
+// the other elements of the document are kept as they are.
+func AppendPolicyStatement(doc string, statement ram.PolicyStatement) (string, error) {
+	raw := map[string]json.RawMessage{}
+	if err := json.Unmarshal([]byte(doc), &raw); err != nil {
+		return "", fmt.Errorf("invalid policy document: %s", err)
+	}
+	statements := []json.RawMessage{}
+	if data, ok := raw["Statement"]; ok {
+		if err := json.Unmarshal(data, &statements); err != nil {
+			return "", fmt.Errorf("invalid policy statements: %s", err)
+		}
+	}
+	data, err := json.Marshal(statement)
+	if err != nil {
+		return "", err
+	}
+	statements = append(statements, data)
+	if raw["Statement"], err = json.Marshal(statements); err != nil {
+		return "", err
+	}
+	if _, ok := raw["Version"]; !ok {
+		raw["Version"] = json.RawMessage(`"1"`)
+	}
+	result, err := json.Marshal(raw)
+	return string(result), err
+}
+
+// PrunePolicyVersions deletes the oldest non-default versions of the custom policy
+// until at most keep versions are left.
+func PrunePolicyVersions(cli *ram.Client, policyName string, keep int) error {
+	resp, err := cli.ListPolicyVersions(policyName, "Custom")
+	if err != nil {
+		return err
+	}
+	versions := resp.PolicyVersions.PolicyVersion
+	candidates := []ram.PolicyVersion{}
+	for _, v := range versions {
+		if !v.IsDefaultVersion {
+			candidates = append(candidates, v)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].CreateDate < candidates[j].CreateDate
+	})
+	for n := len(versions); n > keep && len(candidates) > 0; n-- {
+		if _, err := cli.DeletePolicyVersion(policyName, candidates[0].VersionID); err != nil {
+			return fmt.Errorf("failed to delete version %s of policy %s due to %s", candidates[0].VersionID, policyName, err)
+		}
+		candidates = candidates[1:]
+	}
+	return nil
 }
 
 // AttachPolicy attach the policy to the specified role.
@@ -343,6 +559,60 @@ func AttachPolicy(cli *ram.Client, policyName, roleName string) error {
 	return err
 }
 
+// BindRolePolicyIdempotent create the role and the custom policy if they do not exist, merge the
+// action and resource into the default version of the policy, and attach the policy to the role.
+func BindRolePolicyIdempotent(cli *ram.Client, roleName, principal, policyName, action, resource string) (string, error) {
+	var roleARN string
+	gresp, err := cli.GetRole(roleName)
+	if err == nil {
+		roleARN = gresp.Role.Arn
+		trustPolicy, err := ram.ParseAssumeRolePolicyDocument(gresp.Role.AssumeRolePolicyDocument)
+		if err != nil {
+			return roleARN, err
+		}
+		if !trustPolicy.HasServicePrincipal(principal) {
+			return roleARN, fmt.Errorf("role %s can not be assumed by %s, please use another role", roleName, principal)
+		}
+	} else if ram.IsNotFound(err) {
+		roleARN, err = CreateRole(cli, roleName, principal)
+		if err != nil {
+			return roleARN, err
+		}
+	} else {
+		return roleARN, err
+	}
+
+	_, err = cli.GetPolicy(policyName, "Custom")
+	if ram.IsNotFound(err) {
+		err = CreatePolicy(cli, policyName, action, resource)
+	}
+	if err != nil {
+		return roleARN, err
+	}
+
+	// merge the permission into the policy, the existing statements are kept
+	statement := ram.PolicyStatement{Effect: "Allow"}
+	if err := json.Unmarshal([]byte(action), &statement.Action); err != nil {
+		return roleARN, fmt.Errorf("invalid action %s: %v", action, err)
+	}
+	if err := json.Unmarshal([]byte(resource), &statement.Resource); err != nil {
+		return roleARN, fmt.Errorf("invalid resource %s: %v", resource, err)
+	}
+	if _, err := MergePolicyStatements(cli, policyName, statement); err != nil {
+		return roleARN, err
+	}
+
+	policies, _ := cli.ListPoliciesForRole(roleName)
+	if policies != nil {
+		for _, v := range policies.Policies.Policy {
+			if v.PolicyName == policyName {
+				return roleARN, nil
+			}
+		}
+	}
+	return roleARN, AttachPolicy(cli, policyName, roleName)
+}
+
 // DownloadFromURL download file from the specified url.
 func DownloadFromURL(url string, writer io.Writer) error {
 	response, err := http.Get(url)
@@ -391,11 +661,21 @@ func ParseAdditionalVersionWeight(routes []string) map[string]float64 {
 
 // GetTriggerConfig ...
 func GetTriggerConfig(triggerType string, triggerConfigFile string) (interface{}, error) {
+	// the tablestore trigger has nothing to configure
+	if triggerType == TriggerTypeTableStore && triggerConfigFile == "" {
+		return TableStoreTriggerConfig{}, nil
+	}
+	if triggerConfigFile == "" {
+		return nil, fmt.Errorf("trigger config file is required for %s trigger", triggerType)
+	}
 	viper.SetConfigFile(triggerConfigFile)
 	err := viper.ReadInConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to read config file %s due to %v", triggerConfigFile, err)
 	}
+	if err := ValidateTriggerConfigFile(triggerType, triggerConfigFile); err != nil {
+		return nil, err
+	}
 
 	switch triggerType {
 	case fc.TRIGGER_TYPE_OSS:
@@ -405,6 +685,12 @@ func GetTriggerConfig(triggerType string, triggerConfigFile string) (interface{}
 	case fc.TRIGGER_TYPE_TIMER:
 		timeTriggerConfig := fc.TimeTriggerConfig{}
 		viper.UnmarshalKey("triggerConfig", &timeTriggerConfig)
+		if timeTriggerConfig.CronExpression == nil {
+			return nil, fmt.Errorf("cronExpression is required in timer trigger config file %s", triggerConfigFile)
+		}
+		if _, err := ParseCronExpression(*timeTriggerConfig.CronExpression); err != nil {
+			return nil, err
+		}
 		return timeTriggerConfig, nil
 	case fc.TRIGGER_TYPE_LOG:
 		logTriggerConfig := fc.LogTriggerConfig{}
@@ -418,24 +704,38 @@ func GetTriggerConfig(triggerType string, triggerConfigFile string) (interface{}
 		httpTriggerConfig := fc.HTTPTriggerConfig{}
 		viper.UnmarshalKey("triggerConfig", &httpTriggerConfig)
 		return httpTriggerConfig, nil
-	// case fc.TRIGGER_TYPE_RDS:
-	// 	rdsTriggerConfig := fc.RdsTriggerConfig{}
-	// 	viper.UnmarshalKey("triggerConfig", &rdsTriggerConfig)
-	// 	return rdsTriggerConfig, nil
-	// case fc.TRIGGER_TYPE_TABLESTORE:
-	// 	tableStoreTriggerConfig := fc.TableStoreTriggerConfig{}
-	// 	viper.UnmarshalKey("triggerConfig", &tableStoreTriggerConfig)
-	// 	return tableStoreTriggerConfig, nil
+	case TriggerTypeRDS:
+		rdsTriggerConfig := RDSTriggerConfig{}
+		viper.UnmarshalKey("triggerConfig", &rdsTriggerConfig)
+		return rdsTriggerConfig, nil
+	case TriggerTypeTableStore:
+		tableStoreTriggerConfig := TableStoreTriggerConfig{}
+		viper.UnmarshalKey("triggerConfig", &tableStoreTriggerConfig)
+		return tableStoreTriggerConfig, nil
 	case fc.TRIGGER_TYPE_MNS_TOPIC:
 		mnsTopicTriggerConfig := fc.MnsTopicTriggerConfig{}
 		viper.UnmarshalKey("triggerConfig", &mnsTopicTriggerConfig)
 		return mnsTopicTriggerConfig, nil
 	default:
-		return nil, fmt.Errorf("unsupported trigger type, expect oss, log, timer, http, cdn_events, mns_topic, actual %s", triggerType)
+		return nil, fmt.Errorf("unsupported trigger type, expect %s, actual %s", strings.Join(TriggerTypes, ", "), triggerType)
 	}
 
 }
 
+// GetTimeTriggerConfig convert the trigger config returned by the fc api to the timer trigger config.
+func GetTimeTriggerConfig(triggerConfig interface{}) (*fc.TimeTriggerConfig, error) {
+	data, err := json.Marshal(triggerConfig)
+	if err != nil {
+		return nil, err
+	}
+	timeTriggerConfig := &fc.TimeTriggerConfig{}
+	err = json.Unmarshal(data, timeTriggerConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse timer trigger config due to %v", err)
+	}
+	return timeTriggerConfig, nil
+}
+
 // GetPublicImageDigest Get docker hub public image digest: sha256:xxxxxxx
 func GetPublicImageDigest(name, tag string) (string, error) {
 	// get token
@@ -67,3 +67,39 @@ func (s *UtilTestSuite) TestGetLocalImageDigest() {
 	digest, _ := GetLocalImageDigest("aliyunfc/runtime-nodejs6", "build")
 	s.Equal(mockDigest, digest)
 }
+
+func (s *UtilTestSuite) TestConfigValue() {
+	assert := s.Require()
+	cfg := NewGlobalConfig()
+	assert.Contains(ConfigKeys(), "access_key_id")
+	assert.Contains(ConfigKeys(), "role_arn")
+
+	assert.Nil(cfg.SetValue("timeout", "120"))
+	assert.Equal(uint(120), cfg.Timeout)
+	assert.Nil(cfg.SetValue("debug", "true"))
+	assert.True(cfg.Debug)
+	assert.Nil(cfg.SetValue("role_session_duration", "900"))
+	value, err := cfg.GetValue("role_session_duration")
+	assert.Nil(err)
+	assert.Equal("900", value)
+
+	assert.NotNil(cfg.SetValue("timeout", "-1"))
+	assert.NotNil(cfg.SetValue("unknown", "x"))
+	_, err = cfg.GetValue("unknown")
+	assert.NotNil(err)
+}
+
+func (s *UtilTestSuite) TestBuildEndpoint() {
+	assert := s.Require()
+	endpoint, err := BuildEndpoint("https://123.cn-hangzhou.fc.aliyuncs.com", "", "cn-shanghai")
+	assert.Nil(err)
+	assert.Equal("https://123.cn-shanghai.fc.aliyuncs.com", endpoint)
+	endpoint, err = BuildEndpoint("https://123.cn-hangzhou.fc.aliyuncs.com", "456", "")
+	assert.Nil(err)
+	assert.Equal("https://456.cn-hangzhou.fc.aliyuncs.com", endpoint)
+
+	_, err = BuildEndpoint("", "", "cn-shanghai")
+	assert.NotNil(err)
+	_, err = BuildEndpoint("", "123", "cn-nowhere")
+	assert.NotNil(err)
+}